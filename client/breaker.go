@@ -0,0 +1,177 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// ErrCircuitOpen is returned without contacting the server while a circuit
+// breaker is open
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitState is the state of a CircuitBreaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets a single request through to probe the server
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerPolicy controls when a CircuitBreaker opens and closes
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failures opening the circuit
+	FailureThreshold int
+
+	// Cooldown is how long the circuit stays open before a probe request is
+	// let through
+	Cooldown time.Duration
+
+	// IsFailure reports whether an error counts as a failure of the server. By
+	// default, transient errors, timeouts and internal errors reported by the
+	// server do, while other errors returned by the server do not.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called whenever the circuit changes state
+	OnStateChange func(from, to CircuitState)
+}
+
+// DefaultCircuitBreakerPolicy opens the circuit after 5 consecutive failures
+// and probes the server again after 30 seconds
+var DefaultCircuitBreakerPolicy = CircuitBreakerPolicy{
+	FailureThreshold: 5,
+	Cooldown:         30 * time.Second,
+}
+
+func isServerFailure(err error) bool {
+	var rpcErr *mcp.JSONRPCErrorData
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == mcp.ErrorCodeInternalError
+	}
+	return IsTransient(err) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// CircuitBreaker fails requests fast while a server keeps failing. After
+// FailureThreshold consecutive failures the circuit opens, and requests fail
+// with ErrCircuitOpen until Cooldown has passed. A single probe request is then
+// let through: if it succeeds the circuit closes, otherwise it opens again.
+//
+// A CircuitBreaker guards a single server. It is installed with
+// WithInterceptors(breaker.Interceptor()).
+type CircuitBreaker struct {
+	policy CircuitBreakerPolicy
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// probing is set while the probe request of a half-open circuit is in flight
+	probing bool
+}
+
+// NewCircuitBreaker returns a closed circuit breaker applying policy
+func NewCircuitBreaker(policy CircuitBreakerPolicy) *CircuitBreaker {
+	if policy.IsFailure == nil {
+		policy.IsFailure = isServerFailure
+	}
+	return &CircuitBreaker{policy: policy}
+}
+
+// State returns the current state of the circuit
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.policy.Cooldown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// Interceptor returns the interceptor applying the breaker to requests
+func (b *CircuitBreaker) Interceptor() Interceptor {
+	return func(ctx context.Context, method string, params json.RawMessage, invoker Invoker) (json.RawMessage, error) {
+		if !b.allow() {
+			return nil, ErrCircuitOpen
+		}
+		result, err := invoker(ctx, method, params)
+		b.record(err)
+		return result, err
+	}
+}
+
+// allow reports whether a request may be sent, turning an open circuit whose
+// cooldown has passed into a half-open one probed by this request
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	from := b.state
+	allowed := true
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.policy.Cooldown {
+			allowed = false
+			break
+		}
+		b.state, b.probing = CircuitHalfOpen, true
+	case CircuitHalfOpen:
+		allowed = !b.probing
+		b.probing = true
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return allowed
+}
+
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	from := b.state
+	switch {
+	case b.state == CircuitHalfOpen && errors.Is(err, context.Canceled):
+		// A probe abandoned by its caller says nothing about the server
+		b.probing = false
+	case err == nil || !b.policy.IsFailure(err):
+		b.failures = 0
+		if b.state == CircuitHalfOpen {
+			b.state, b.probing = CircuitClosed, false
+		}
+	case b.state == CircuitHalfOpen:
+		b.state, b.probing, b.openedAt = CircuitOpen, false, time.Now()
+	default:
+		b.failures++
+		if b.failures >= b.policy.FailureThreshold {
+			b.state, b.failures, b.openedAt = CircuitOpen, 0, time.Now()
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+func (b *CircuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.policy.OnStateChange != nil {
+		b.policy.OnStateChange(from, to)
+	}
+}
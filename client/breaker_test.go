@@ -0,0 +1,208 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+var errUnreachable = errors.New("connection reset by peer")
+
+// scriptedInvoker returns the given errors in turn, then succeeds, counting
+// the requests it receives
+type scriptedInvoker struct {
+	mu    sync.Mutex
+	errs  []error
+	calls int
+}
+
+func (s *scriptedInvoker) invoke(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.RawMessage(`{}`), nil
+}
+
+func (s *scriptedInvoker) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+type transitions struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (t *transitions) record(from, to CircuitState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.log = append(t.log, fmt.Sprintf("%s->%s", from, to))
+}
+
+func (t *transitions) get() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.log)
+}
+
+func newTestBreaker(cooldown time.Duration) (*CircuitBreaker, *transitions) {
+	changes := &transitions{}
+	breaker := NewCircuitBreaker(CircuitBreakerPolicy{
+		FailureThreshold: 2,
+		Cooldown:         cooldown,
+		OnStateChange:    changes.record,
+	})
+	return breaker, changes
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	breaker, changes := newTestBreaker(20 * time.Millisecond)
+	intercept := breaker.Interceptor()
+	server := &scriptedInvoker{errs: []error{errUnreachable, errUnreachable, errUnreachable}}
+	call := func() error {
+		_, err := intercept(context.Background(), mcp.MethodPing, nil, server.invoke)
+		return err
+	}
+
+	// Closed until the threshold of consecutive failures is reached
+	call()
+	if state := breaker.State(); state != CircuitClosed {
+		t.Fatalf("state after 1 failure = %s", state)
+	}
+	call()
+	if state := breaker.State(); state != CircuitOpen {
+		t.Fatalf("state after 2 failures = %s", state)
+	}
+
+	// Open: requests fail fast without reaching the server
+	if err := call(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("error while open = %v, want %v", err, ErrCircuitOpen)
+	}
+	if n := server.count(); n != 2 {
+		t.Errorf("server received %d requests, want 2", n)
+	}
+
+	// Half-open after the cooldown: the failed probe opens the circuit again
+	time.Sleep(30 * time.Millisecond)
+	if state := breaker.State(); state != CircuitHalfOpen {
+		t.Fatalf("state after cooldown = %s", state)
+	}
+	if err := call(); !errors.Is(err, errUnreachable) {
+		t.Errorf("probe error = %v", err)
+	}
+	if state := breaker.State(); state != CircuitOpen {
+		t.Fatalf("state after a failed probe = %s", state)
+	}
+
+	// A successful probe closes it
+	time.Sleep(30 * time.Millisecond)
+	if err := call(); err != nil {
+		t.Errorf("probe: %v", err)
+	}
+	if state := breaker.State(); state != CircuitClosed {
+		t.Fatalf("state after a successful probe = %s", state)
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if got := changes.get(); !slices.Equal(got, want) {
+		t.Errorf("transitions = %v, want %v", got, want)
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	breaker, _ := newTestBreaker(time.Millisecond)
+	intercept := breaker.Interceptor()
+	failing := &scriptedInvoker{errs: []error{errUnreachable, errUnreachable}}
+	for i := 0; i < 2; i++ {
+		intercept(context.Background(), mcp.MethodPing, nil, failing.invoke)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// While the probe is in flight, other requests fail fast
+	probing, release := make(chan struct{}), make(chan struct{})
+	slow := func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+		close(probing)
+		<-release
+		return json.RawMessage(`{}`), nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := intercept(context.Background(), mcp.MethodPing, nil, slow)
+		done <- err
+	}()
+	<-probing
+	if _, err := intercept(context.Background(), mcp.MethodPing, nil, failing.invoke); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("error during the probe = %v, want %v", err, ErrCircuitOpen)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("probe: %v", err)
+	}
+	if state := breaker.State(); state != CircuitClosed {
+		t.Errorf("state = %s, want closed", state)
+	}
+}
+
+func TestCircuitBreakerCancelledProbe(t *testing.T) {
+	breaker, _ := newTestBreaker(time.Millisecond)
+	intercept := breaker.Interceptor()
+	server := &scriptedInvoker{errs: []error{errUnreachable, errUnreachable, context.Canceled}}
+	for i := 0; i < 3; i++ {
+		time.Sleep(2 * time.Millisecond)
+		intercept(context.Background(), mcp.MethodPing, nil, server.invoke)
+	}
+
+	// The abandoned probe neither closed nor reopened the circuit, and the
+	// next request probes the server again
+	if state := breaker.State(); state != CircuitHalfOpen {
+		t.Fatalf("state after a cancelled probe = %s", state)
+	}
+	if _, err := intercept(context.Background(), mcp.MethodPing, nil, server.invoke); err != nil {
+		t.Errorf("second probe: %v", err)
+	}
+	if state := breaker.State(); state != CircuitClosed {
+		t.Errorf("state = %s, want closed", state)
+	}
+}
+
+func TestCircuitBreakerIgnoresRequestErrors(t *testing.T) {
+	breaker, changes := newTestBreaker(time.Minute)
+	intercept := breaker.Interceptor()
+	invalid := mcp.NewError(mcp.ErrorCodeInvalidParams, "invalid")
+	// Errors about the request itself, and successes, reset the failure count
+	server := &scriptedInvoker{errs: []error{errUnreachable, invalid, errUnreachable, nil, errUnreachable, invalid, invalid}}
+	for i := 0; i < 7; i++ {
+		intercept(context.Background(), mcp.MethodPing, nil, server.invoke)
+	}
+	if state := breaker.State(); state != CircuitClosed {
+		t.Errorf("state = %s, want closed", state)
+	}
+	if got := changes.get(); len(got) != 0 {
+		t.Errorf("transitions = %v, want none", got)
+	}
+
+	// Internal errors reported by the server count as failures
+	internal := mcp.NewError(mcp.ErrorCodeInternalError, "internal")
+	server = &scriptedInvoker{errs: []error{internal, internal}}
+	for i := 0; i < 2; i++ {
+		intercept(context.Background(), mcp.MethodPing, nil, server.invoke)
+	}
+	if state := breaker.State(); state != CircuitOpen {
+		t.Errorf("state after internal errors = %s, want open", state)
+	}
+}
@@ -0,0 +1,327 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+var _ MCPClient = &CachingClient{}
+
+// NotificationSource is implemented by clients that deliver server
+// notifications to registered handlers, such as Client and SupervisedClient
+type NotificationSource interface {
+	OnNotification(method string, fn NotificationHandlerFunc)
+}
+
+// CacheOption configures a CachingClient
+type CacheOption func(*CachingClient)
+
+// WithCacheTTL sets how long cached results are served before they are fetched
+// again. By default they are kept until the server reports a change or the
+// cache is invalidated.
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(c *CachingClient) {
+		c.ttl = ttl
+	}
+}
+
+// CachingClient wraps an MCPClient and keeps the full tool, prompt and resource
+// lists of the server in memory, so that listing them again costs no round
+// trip. Lists are fetched through every page on first use, and a request for
+// the first page is answered with the whole list and no cursor.
+//
+// Cached lists are dropped when the server sends the matching list_changed
+// notification, and resources read while subscribed through the CachingClient
+// are cached until the server sends notifications/resources/updated for them.
+// Notifications are only observed if the wrapped client is a
+// NotificationSource; otherwise rely on WithCacheTTL and Invalidate. The cache
+// is also dropped whenever Initialize is called.
+type CachingClient struct {
+	MCPClient
+	ttl time.Duration
+
+	tools     cachedList[mcp.Tool]
+	prompts   cachedList[mcp.Prompt]
+	resources cachedList[mcp.Resource]
+
+	mu           sync.Mutex
+	capabilities *mcp.ServerCapabilities
+	// reads holds the results of resources read while subscribed, by URI, and
+	// readGeneration is bumped whenever one of them may have become stale
+	subscribed     map[string]struct{}
+	reads          map[string]cachedRead
+	readGeneration uint64
+}
+
+// cachedRead holds a resource as read from the server, encoded so that every
+// caller is given its own copy
+type cachedRead struct {
+	encoded   []byte
+	fetchedAt time.Time
+}
+
+// NewCachingClient returns a CachingClient wrapping client, configured by opts
+func NewCachingClient(client MCPClient, opts ...CacheOption) *CachingClient {
+	c := &CachingClient{
+		MCPClient:  client,
+		subscribed: make(map[string]struct{}),
+		reads:      make(map[string]cachedRead),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if source, ok := client.(NotificationSource); ok {
+		source.OnNotification(mcp.MethodNotificationToolsListChanged, func(context.Context, string, json.RawMessage) {
+			c.tools.invalidate()
+		})
+		source.OnNotification(mcp.MethodNotificationPromptsListChanged, func(context.Context, string, json.RawMessage) {
+			c.prompts.invalidate()
+		})
+		source.OnNotification(mcp.MethodNotificationResourcesListChanged, func(context.Context, string, json.RawMessage) {
+			c.resources.invalidate()
+		})
+		source.OnNotification(mcp.MethodNotificationResourcesUpdated, func(ctx context.Context, method string, params json.RawMessage) {
+			var p struct {
+				URI string `json:"uri"`
+			}
+			if json.Unmarshal(params, &p) == nil {
+				c.invalidateRead(p.URI)
+			}
+		})
+	}
+	return c
+}
+
+// Invalidate drops every cached result
+func (c *CachingClient) Invalidate() {
+	c.tools.invalidate()
+	c.prompts.invalidate()
+	c.resources.invalidate()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reads = make(map[string]cachedRead)
+	c.readGeneration++
+}
+
+// Refresh drops every cached result and fetches again the lists the server
+// supports, according to the capabilities it returned from Initialize
+func (c *CachingClient) Refresh(ctx context.Context) error {
+	c.Invalidate()
+
+	c.mu.Lock()
+	capabilities := c.capabilities
+	c.mu.Unlock()
+	if capabilities == nil {
+		return nil
+	}
+
+	if capabilities.Tools != nil {
+		if _, err := c.ListTools(ctx, nil); err != nil {
+			return err
+		}
+	}
+	if capabilities.Prompts != nil {
+		if _, err := c.ListPrompts(ctx, nil); err != nil {
+			return err
+		}
+	}
+	if capabilities.Resources != nil {
+		if _, err := c.ListResources(ctx, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CachingClient) Initialize(
+	ctx context.Context,
+	capabilities mcp.ClientCapabilities,
+	clientInfo mcp.Implementation,
+	protocolVersion string,
+) (*mcp.InitializeResult, error) {
+	result, err := c.MCPClient.Initialize(ctx, capabilities, clientInfo, protocolVersion)
+	if err != nil {
+		return nil, err
+	}
+	c.Invalidate()
+
+	c.mu.Lock()
+	c.capabilities = &result.Capabilities
+	c.mu.Unlock()
+	return result, nil
+}
+
+func (c *CachingClient) ListTools(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error) {
+	if cursor != nil {
+		return c.MCPClient.ListTools(ctx, cursor)
+	}
+	tools, err := c.tools.get(ctx, c.ttl, func(ctx context.Context) ([]mcp.Tool, error) {
+		return collect(AllTools(ctx, c.MCPClient))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ListToolsResult{Tools: tools}, nil
+}
+
+func (c *CachingClient) ListPrompts(ctx context.Context, cursor *string) (*mcp.ListPromptsResult, error) {
+	if cursor != nil {
+		return c.MCPClient.ListPrompts(ctx, cursor)
+	}
+	prompts, err := c.prompts.get(ctx, c.ttl, func(ctx context.Context) ([]mcp.Prompt, error) {
+		return collect(AllPrompts(ctx, c.MCPClient))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ListPromptsResult{Prompts: prompts}, nil
+}
+
+func (c *CachingClient) ListResources(ctx context.Context, cursor *string) (*mcp.ListResourcesResult, error) {
+	if cursor != nil {
+		return c.MCPClient.ListResources(ctx, cursor)
+	}
+	resources, err := c.resources.get(ctx, c.ttl, func(ctx context.Context) ([]mcp.Resource, error) {
+		return collect(AllResources(ctx, c.MCPClient))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ListResourcesResult{Resources: resources}, nil
+}
+
+// ReadResource serves resources the client is subscribed to from the cache.
+// Other resources are always read from the server. Every caller is given its
+// own copy of a cached result, which it may modify.
+func (c *CachingClient) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	c.mu.Lock()
+	_, subscribed := c.subscribed[uri]
+	read, ok := c.reads[uri]
+	generation := c.readGeneration
+	c.mu.Unlock()
+	if ok && c.fresh(read.fetchedAt) {
+		var result mcp.ReadResourceResult
+		if err := json.Unmarshal(read.encoded, &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	result, err := c.MCPClient.ReadResource(ctx, uri)
+	if err != nil || !subscribed {
+		return result, err
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return result, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Only keep the result if nothing may have changed it since it was read
+	if _, ok := c.subscribed[uri]; ok && c.readGeneration == generation {
+		c.reads[uri] = cachedRead{encoded: encoded, fetchedAt: time.Now()}
+	}
+	return result, nil
+}
+
+func (c *CachingClient) SubscribeResource(ctx context.Context, uri string) error {
+	if err := c.MCPClient.SubscribeResource(ctx, uri); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribed[uri] = struct{}{}
+	return nil
+}
+
+func (c *CachingClient) UnsubscribeResource(ctx context.Context, uri string) error {
+	c.mu.Lock()
+	delete(c.subscribed, uri)
+	c.mu.Unlock()
+	c.invalidateRead(uri)
+	return c.MCPClient.UnsubscribeResource(ctx, uri)
+}
+
+func (c *CachingClient) invalidateRead(uri string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.reads, uri)
+	c.readGeneration++
+}
+
+func (c *CachingClient) fresh(fetchedAt time.Time) bool {
+	return c.ttl <= 0 || time.Since(fetchedAt) < c.ttl
+}
+
+// cachedList holds a list fetched through every page. Concurrent callers
+// missing the cache share a single fetch.
+type cachedList[T any] struct {
+	mu         sync.Mutex
+	items      []T
+	fetchedAt  time.Time
+	valid      bool
+	generation uint64
+	fetching   *listFetch[T]
+}
+
+type listFetch[T any] struct {
+	done  chan struct{}
+	items []T
+	err   error
+}
+
+func (l *cachedList[T]) get(ctx context.Context, ttl time.Duration, fetch func(ctx context.Context) ([]T, error)) ([]T, error) {
+	for {
+		l.mu.Lock()
+		if l.valid && (ttl <= 0 || time.Since(l.fetchedAt) < ttl) {
+			items := l.items
+			l.mu.Unlock()
+			return slices.Clone(items), nil
+		}
+		if f := l.fetching; f != nil {
+			l.mu.Unlock()
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// A fetch failing because its caller gave up is retried with ours
+			if ctx.Err() == nil && (errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) {
+				continue
+			}
+			return slices.Clone(f.items), f.err
+		}
+
+		f := &listFetch[T]{done: make(chan struct{})}
+		l.fetching = f
+		generation := l.generation
+		l.mu.Unlock()
+
+		f.items, f.err = fetch(ctx)
+
+		l.mu.Lock()
+		// A list invalidated while it was fetched may already be stale
+		if f.err == nil && l.generation == generation {
+			l.items, l.fetchedAt, l.valid = f.items, time.Now(), true
+		}
+		l.fetching = nil
+		l.mu.Unlock()
+		close(f.done)
+		return slices.Clone(f.items), f.err
+	}
+}
+
+func (l *cachedList[T]) invalidate() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items, l.valid = nil, false
+	l.generation++
+}
@@ -0,0 +1,186 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+func newCachingClient(t *testing.T, s *fakeServer, opts ...client.CacheOption) *client.CachingClient {
+	t.Helper()
+	c := client.NewCachingClient(s, opts...)
+	if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func listToolNames(t *testing.T, c client.MCPClient) []string {
+	t.Helper()
+	result, err := c.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if result.NextCursor != nil {
+		t.Errorf("next cursor = %q, want the whole list", *result.NextCursor)
+	}
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func assertCallCount(t *testing.T, s *fakeServer, method string, want int) {
+	t.Helper()
+	if got := s.callCount(method); got != want {
+		t.Errorf("%d calls to %s, want %d", got, method, want)
+	}
+}
+
+func TestCachingClientLists(t *testing.T) {
+	s := newFakeServer("a", "b", "c", "d", "e")
+	s.pageSize = 2
+	c := newCachingClient(t, s)
+
+	for i := 0; i < 3; i++ {
+		if names := listToolNames(t, c); len(names) != 5 {
+			t.Fatalf("tools = %v, want every page", names)
+		}
+	}
+	assertCallCount(t, s, mcp.MethodToolsList, 3)
+
+	// Later pages are requested from the server
+	cursor := "2"
+	c.ListTools(context.Background(), &cursor)
+	assertCallCount(t, s, mcp.MethodToolsList, 4)
+}
+
+func TestCachingClientListChanged(t *testing.T) {
+	s := newFakeServer("a")
+	c := newCachingClient(t, s)
+	if names := listToolNames(t, c); len(names) != 1 {
+		t.Fatalf("tools = %v", names)
+	}
+
+	s.mu.Lock()
+	s.tools = append(s.tools, mcp.Tool{Name: "b"})
+	s.mu.Unlock()
+	s.notify(mcp.MethodNotificationToolsListChanged, nil)
+	if names := listToolNames(t, c); len(names) != 2 {
+		t.Errorf("tools after list_changed = %v", names)
+	}
+	listToolNames(t, c)
+	assertCallCount(t, s, mcp.MethodToolsList, 2)
+}
+
+func TestCachingClientTTL(t *testing.T) {
+	s := newFakeServer("a")
+	c := newCachingClient(t, s, client.WithCacheTTL(20*time.Millisecond))
+
+	listToolNames(t, c)
+	listToolNames(t, c)
+	assertCallCount(t, s, mcp.MethodToolsList, 1)
+	time.Sleep(30 * time.Millisecond)
+	listToolNames(t, c)
+	assertCallCount(t, s, mcp.MethodToolsList, 2)
+}
+
+func TestCachingClientInvalidate(t *testing.T) {
+	s := newFakeServer("a")
+	c := newCachingClient(t, s)
+
+	listToolNames(t, c)
+	c.Invalidate()
+	listToolNames(t, c)
+	assertCallCount(t, s, mcp.MethodToolsList, 2)
+
+	if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	listToolNames(t, c)
+	assertCallCount(t, s, mcp.MethodToolsList, 3)
+}
+
+func TestCachingClientSharesFetch(t *testing.T) {
+	s := newFakeServer("a")
+	s.listDelay = 50 * time.Millisecond
+	c := newCachingClient(t, s)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.ListTools(context.Background(), nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	assertCallCount(t, s, mcp.MethodToolsList, 1)
+}
+
+func TestCachingClientSubscribedReads(t *testing.T) {
+	s := newFakeServer()
+	const uri = "file:///a.txt"
+	s.resources[uri] = "a"
+	c := newCachingClient(t, s)
+	read := func() {
+		t.Helper()
+		if _, err := c.ReadResource(context.Background(), uri); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Unsubscribed resources are always read from the server
+	read()
+	read()
+	assertCallCount(t, s, mcp.MethodResourcesRead, 2)
+
+	if err := c.SubscribeResource(context.Background(), uri); err != nil {
+		t.Fatal(err)
+	}
+	read()
+	read()
+	assertCallCount(t, s, mcp.MethodResourcesRead, 3)
+
+	s.notify(mcp.MethodNotificationResourcesUpdated, json.RawMessage(`{"uri":"`+uri+`"}`))
+	read()
+	assertCallCount(t, s, mcp.MethodResourcesRead, 4)
+
+	if err := c.UnsubscribeResource(context.Background(), uri); err != nil {
+		t.Fatal(err)
+	}
+	read()
+	read()
+	assertCallCount(t, s, mcp.MethodResourcesRead, 6)
+}
+
+func TestCachingClientReadsAreCopies(t *testing.T) {
+	s := newFakeServer()
+	const uri = "file:///a.txt"
+	s.resources[uri] = "a"
+	c := newCachingClient(t, s)
+	if err := c.SubscribeResource(context.Background(), uri); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := c.ReadResource(context.Background(), uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text := result.Contents[0].(mcp.TextResourceContents).Text; text != "a" || result.Meta != nil {
+			t.Fatalf("read %d = %q, %v after the previous result was modified", i, text, result.Meta)
+		}
+		result.Contents[0] = mcp.TextResourceContents{Uri: uri, Text: "modified"}
+		result.Meta = mcp.ReadResourceResultMeta{"modified": true}
+	}
+	assertCallCount(t, s, mcp.MethodResourcesRead, 1)
+}
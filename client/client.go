@@ -2,6 +2,12 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
 
 	"github.com/WePrompt/gomcp/mcp"
 )
@@ -28,3 +34,473 @@ type MCPClient interface {
 	ListTools(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error)
 	CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error)
 }
+
+var _ MCPClient = &Client{}
+
+// Client is an MCPClient that exchanges JSON-RPC messages with a server over a
+// Transport
+type Client struct {
+	transport   Transport
+	requestID   atomic.Int64
+	responses   sync.Map
+	initialized atomic.Bool
+	invoke      Invoker
+	notify      NotificationHandlerFunc
+
+	handlersMu           sync.RWMutex
+	notificationHandlers map[string][]NotificationHandlerFunc
+	requestHandlers      map[string]RequestHandlerFunc
+	// inbound holds the cancel functions of server requests being handled
+	inbound sync.Map
+
+	// ctx is cancelled once the connection ends, aborting server requests
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// done is closed when the transport stops delivering messages, after which
+	// err holds the reason
+	done chan struct{}
+	err  error
+
+	// protocolVersion holds the revision negotiated during initialization, as
+	// a string. It is read by the goroutines handling server requests.
+	protocolVersion atomic.Value
+}
+
+// NewClient returns a client talking to a server over transport, configured by
+// opts. The client takes ownership of the transport and closes it on Close.
+func NewClient(transport Transport, opts ...ClientOption) *Client {
+	options := newClientOptions(opts)
+	if options.recording != nil {
+		transport = NewRecordingTransport(transport, options.recording)
+	}
+	client := &Client{
+		transport:            transport,
+		notificationHandlers: make(map[string][]NotificationHandlerFunc),
+		requestHandlers:      make(map[string]RequestHandlerFunc),
+		done:                 make(chan struct{}),
+	}
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	invoke := client.roundTrip
+	if options.route != nil {
+		invoke = func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+			return options.route(client, ctx, method, params)
+		}
+	}
+	client.invoke = chainInterceptors(options.interceptors, invoke)
+	client.notify = chainNotificationInterceptors(options.notificationInterceptors, client.handleNotification)
+
+	go client.readMessages()
+
+	return client
+}
+
+// Close closes the transport. Requests still waiting for a response fail.
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
+
+// Done returns a channel that is closed once the connection to the server is
+// lost or closed
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the reason the connection ended, once Done is closed
+func (c *Client) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// readMessages delivers the messages received from the server: responses to
+// the requests waiting for them, notifications to the notification chain and
+// requests to their handlers
+func (c *Client) readMessages() {
+	defer close(c.done)
+	defer c.cancel()
+	for {
+		msg, err := c.transport.Receive()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = ErrConnectionClosed
+			}
+			c.err = err
+			return
+		}
+
+		var message struct {
+			Id     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(msg, &message); err != nil {
+			continue
+		}
+		if message.Method != "" {
+			if len(message.Id) == 0 || string(message.Id) == "null" {
+				c.notify(c.ctx, message.Method, message.Params)
+				continue
+			}
+			var id mcp.RequestID
+			if err := json.Unmarshal(message.Id, &id); err != nil {
+				continue
+			}
+			ctx, cancel := context.WithCancel(c.ctx)
+			c.inbound.Store(id, cancel)
+			go c.handleRequest(ctx, cancel, id, message.Method, message.Params)
+			continue
+		}
+
+		response := &mcp.JSONRPCResponse{}
+		if err := response.UnmarshalJSON(msg); err != nil {
+			continue
+		}
+
+		if ch, ok := c.responses.LoadAndDelete(response.Id); ok {
+			ch.(chan *mcp.JSONRPCResponse) <- response
+		}
+	}
+}
+
+func (c *Client) sendRequest(
+	ctx context.Context,
+	method string,
+	params interface{},
+) (json.RawMessage, error) {
+	if !c.initialized.Load() && method != "initialize" {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	// Convert params to json.RawMessage
+	var paramsRaw json.RawMessage
+	if params != nil {
+		paramBytes, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
+		}
+		paramsRaw = paramBytes
+	}
+
+	return c.invoke(ctx, method, paramsRaw)
+}
+
+// roundTrip writes a single request to the server and waits for its response.
+// It is the innermost Invoker of the interceptor chain.
+func (c *Client) roundTrip(
+	ctx context.Context,
+	method string,
+	params json.RawMessage,
+) (json.RawMessage, error) {
+	id := mcp.NewRequestID(c.requestID.Add(1))
+
+	request := mcp.JSONRPCRequest{
+		Id:      id,
+		Jsonrpc: mcp.JSONRPCVersion,
+		Method:  method,
+		Params:  params,
+	}
+
+	responseChan := make(chan *mcp.JSONRPCResponse, 1)
+	c.responses.Store(id, responseChan)
+	defer c.responses.Delete(id)
+
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	select {
+	case <-c.done:
+		return nil, c.err
+	default:
+	}
+	if err := c.transport.Send(ctx, requestBytes); err != nil {
+		return nil, fmt.Errorf("failed to write request: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		// Let the server stop working on a request nobody waits for. The
+		// initialize request must never be cancelled.
+		if method != mcp.MethodInitialize {
+			reason := ctx.Err().Error()
+			c.Notify(context.WithoutCancel(ctx), mcp.MethodNotificationCancelled, mcp.CancelledNotificationParams{
+				RequestId: id,
+				Reason:    &reason,
+			})
+		}
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, c.err
+	case response := <-responseChan:
+		if response.Error != nil {
+			return nil, response.Error
+		}
+		return response.Result, nil
+	}
+}
+
+func (c *Client) Initialize(
+	ctx context.Context,
+	capabilities mcp.ClientCapabilities,
+	clientInfo mcp.Implementation,
+	protocolVersion string,
+) (*mcp.InitializeResult, error) {
+	if protocolVersion == "" {
+		protocolVersion = mcp.LatestProtocolVersion
+	}
+
+	params := struct {
+		Capabilities    mcp.ClientCapabilities `json:"capabilities"`
+		ClientInfo      mcp.Implementation     `json:"clientInfo"`
+		ProtocolVersion string                 `json:"protocolVersion"`
+	}{
+		Capabilities:    mcp.ForProtocolVersion(capabilities, protocolVersion),
+		ClientInfo:      clientInfo,
+		ProtocolVersion: protocolVersion,
+	}
+
+	response, err := c.sendRequest(ctx, mcp.MethodInitialize, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.InitializeResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !mcp.IsSupportedProtocolVersion(result.ProtocolVersion) {
+		return nil, fmt.Errorf("unsupported protocol version: %s", result.ProtocolVersion)
+	}
+
+	c.protocolVersion.Store(result.ProtocolVersion)
+	c.initialized.Store(true)
+
+	if err := c.Notify(ctx, mcp.MethodNotificationInitialized, nil); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ProtocolVersion returns the protocol revision negotiated with the server, or
+// an empty string before Initialize has succeeded
+func (c *Client) ProtocolVersion() string {
+	version, _ := c.protocolVersion.Load().(string)
+	return version
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.sendRequest(ctx, mcp.MethodPing, nil)
+	return err
+}
+
+func (c *Client) ListResources(
+	ctx context.Context,
+	cursor *string,
+) (*mcp.ListResourcesResult, error) {
+	params := struct {
+		Cursor *string `json:"cursor,omitempty"`
+	}{
+		Cursor: cursor,
+	}
+
+	response, err := c.sendRequest(ctx, mcp.MethodResourcesList, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ListResourcesResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) ReadResource(
+	ctx context.Context,
+	uri string,
+) (*mcp.ReadResourceResult, error) {
+	params := struct {
+		URI string `json:"uri"`
+	}{
+		URI: uri,
+	}
+
+	response, err := c.sendRequest(ctx, mcp.MethodResourcesRead, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ReadResourceResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) SubscribeResource(ctx context.Context, uri string) error {
+	params := struct {
+		URI string `json:"uri"`
+	}{
+		URI: uri,
+	}
+
+	_, err := c.sendRequest(ctx, mcp.MethodResourcesSubscribe, params)
+	return err
+}
+
+func (c *Client) UnsubscribeResource(ctx context.Context, uri string) error {
+	params := struct {
+		URI string `json:"uri"`
+	}{
+		URI: uri,
+	}
+
+	_, err := c.sendRequest(ctx, mcp.MethodResourcesUnsubscribe, params)
+	return err
+}
+
+func (c *Client) ListPrompts(
+	ctx context.Context,
+	cursor *string,
+) (*mcp.ListPromptsResult, error) {
+	params := struct {
+		Cursor *string `json:"cursor,omitempty"`
+	}{
+		Cursor: cursor,
+	}
+
+	response, err := c.sendRequest(ctx, mcp.MethodPromptsList, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ListPromptsResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetPrompt(
+	ctx context.Context,
+	name string,
+	arguments map[string]string,
+) (*mcp.GetPromptResult, error) {
+	params := struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments,omitempty"`
+	}{
+		Name:      name,
+		Arguments: arguments,
+	}
+
+	response, err := c.sendRequest(ctx, mcp.MethodPromptsGet, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.GetPromptResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) ListTools(
+	ctx context.Context,
+	cursor *string,
+) (*mcp.ListToolsResult, error) {
+	params := struct {
+		Cursor *string `json:"cursor,omitempty"`
+	}{
+		Cursor: cursor,
+	}
+
+	response, err := c.sendRequest(ctx, mcp.MethodToolsList, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ListToolsResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) CallTool(
+	ctx context.Context,
+	name string,
+	arguments map[string]interface{},
+) (*mcp.CallToolResult, error) {
+	params := struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments,omitempty"`
+	}{
+		Name:      name,
+		Arguments: arguments,
+	}
+
+	response, err := c.sendRequest(ctx, mcp.MethodToolsCall, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) SetLoggingLevel(
+	ctx context.Context,
+	level mcp.LoggingLevel,
+) error {
+	params := struct {
+		Level mcp.LoggingLevel `json:"level"`
+	}{
+		Level: level,
+	}
+
+	_, err := c.sendRequest(ctx, mcp.MethodLoggingSetLevel, params)
+	return err
+}
+
+func (c *Client) Complete(
+	ctx context.Context,
+	ref interface{},
+	argument mcp.CompleteRequest,
+) (*mcp.CompleteResult, error) {
+	params := struct {
+		Ref      interface{}         `json:"ref"`
+		Argument mcp.CompleteRequest `json:"argument"`
+	}{
+		Ref:      ref,
+		Argument: argument,
+	}
+
+	response, err := c.sendRequest(ctx, mcp.MethodCompletionComplete, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.CompleteResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result, nil
+}
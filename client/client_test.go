@@ -0,0 +1,61 @@
+package client_test
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/mcptest"
+)
+
+func TestClientRequiresInitialize(t *testing.T) {
+	c := client.NewClient(mcptest.NewServer().Transport())
+	defer c.Close()
+	if _, err := c.ListTools(context.Background(), nil); err == nil {
+		t.Error("request before Initialize succeeded")
+	}
+	if version := c.ProtocolVersion(); version != "" {
+		t.Errorf("ProtocolVersion() before Initialize = %q", version)
+	}
+}
+
+// TestClientInitializeConcurrentUse is meant to be run with -race: wrappers
+// such as Manager and SupervisedClient use a client from several goroutines
+// while it is being initialized
+func TestClientInitializeConcurrentUse(t *testing.T) {
+	c := client.NewClient(mcptest.NewServer().Transport())
+	defer c.Close()
+
+	// Use the client until it is seen initialized
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for c.ProtocolVersion() == "" {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			c.Ping(context.Background())
+			runtime.Gosched()
+		}
+	}()
+
+	_, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, mcp.ProtocolVersion20250326)
+	if err != nil {
+		close(stop)
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if version := c.ProtocolVersion(); version != mcp.ProtocolVersion20250326 {
+		t.Errorf("ProtocolVersion() = %q", version)
+	}
+	if _, err := c.ListTools(context.Background(), nil); err != nil {
+		t.Errorf("request after Initialize: %v", err)
+	}
+}
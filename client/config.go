@@ -0,0 +1,436 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Transport types of a ServerConfig
+const (
+	TransportStdio = "stdio"
+	TransportSSE   = "sse"
+	TransportHTTP  = "http"
+)
+
+// Config lists the servers a host connects to, in the "mcpServers" format used
+// by desktop hosts, written as JSON or YAML:
+//
+//	{
+//	  "mcpServers": {
+//	    "files": {"command": "npx", "args": ["-y", "server-filesystem", "${HOME}"]},
+//	    "remote": {"type": "http", "url": "https://example.com/mcp", "headers": {"Authorization": "Bearer ${TOKEN}"}}
+//	  }
+//	}
+//
+// Other top-level keys are ignored, and other server keys are kept in Extra, so
+// the servers can be read from a host's own settings file.
+type Config struct {
+	Servers map[string]ServerConfig `json:"mcpServers" yaml:"mcpServers"`
+}
+
+// ServerConfig describes how to reach an MCP server, either by starting it as a
+// child process talking over stdio or by connecting to it over HTTP
+type ServerConfig struct {
+	// Type is one of TransportStdio, TransportSSE or TransportHTTP. It defaults
+	// to TransportStdio if Command is set and to TransportHTTP if URL is set.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	Command string            `json:"command,omitempty" yaml:"command,omitempty"`
+	Args    []string          `json:"args,omitempty" yaml:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	Cwd     string            `json:"cwd,omitempty" yaml:"cwd,omitempty"`
+
+	URL     string            `json:"url,omitempty" yaml:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Disabled servers are skipped by Manager.StartAll
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+
+	// Extra holds the keys of the server not listed above, such as the
+	// autoApprove or timeout settings of some hosts, as decoded from the file.
+	// Environment variables are not expanded in them.
+	Extra map[string]interface{} `json:"-" yaml:"-"`
+}
+
+// ConfigError reports an invalid configuration, with the position of the
+// offending value
+type ConfigError struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ConfigError) Error() string {
+	var position string
+	switch {
+	case e.File != "" && e.Column > 0:
+		position = fmt.Sprintf("%s:%d:%d: ", e.File, e.Line, e.Column)
+	case e.File != "" && e.Line > 0:
+		position = fmt.Sprintf("%s:%d: ", e.File, e.Line)
+	case e.File != "":
+		position = e.File + ": "
+	case e.Column > 0:
+		position = fmt.Sprintf("line %d, column %d: ", e.Line, e.Column)
+	case e.Line > 0:
+		position = fmt.Sprintf("line %d: ", e.Line)
+	}
+	return position + e.Msg
+}
+
+// LoadConfig reads and validates the configuration file at path, expanding
+// environment variables
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	config, err := ParseConfig(data)
+	var configErr *ConfigError
+	if errors.As(err, &configErr) {
+		configErr.File = path
+	}
+	return config, err
+}
+
+// ParseConfig parses and validates a JSON or YAML configuration, expanding
+// ${VAR}, ${VAR:-default} and $VAR references to environment variables in
+// commands, arguments, environment values, directories, URLs and headers.
+// Referencing an unset variable without a default is an error.
+func ParseConfig(data []byte) (*Config, error) {
+	return parseConfig(data, os.LookupEnv)
+}
+
+func parseConfig(data []byte, lookup func(string) (string, bool)) (*Config, error) {
+	// YAML parses JSON too, but JSON syntax errors are clearer from the JSON
+	// decoder
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var v interface{}
+		if err := json.Unmarshal(trimmed, &v); err != nil {
+			return nil, jsonConfigError(data, err)
+		}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, yamlConfigError(err)
+	}
+	if len(root.Content) == 0 {
+		return nil, &ConfigError{Msg: "config is empty"}
+	}
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil, nodeError(doc, "config must be an object")
+	}
+
+	servers := mappingValue(doc, "mcpServers")
+	if servers == nil {
+		return nil, nodeError(doc, "missing mcpServers")
+	}
+	if servers.Kind != yaml.MappingNode {
+		return nil, nodeError(servers, "mcpServers must be an object")
+	}
+
+	p := configParser{lookup: lookup}
+	config := &Config{Servers: make(map[string]ServerConfig, len(servers.Content)/2)}
+	for i := 0; i+1 < len(servers.Content); i += 2 {
+		name, value := servers.Content[i].Value, servers.Content[i+1]
+		if _, ok := config.Servers[name]; ok {
+			return nil, nodeError(servers.Content[i], fmt.Sprintf("duplicate server %q", name))
+		}
+		server, err := p.server(name, value)
+		if err != nil {
+			return nil, err
+		}
+		config.Servers[name] = server
+	}
+	return config, nil
+}
+
+type configParser struct {
+	lookup func(string) (string, bool)
+}
+
+func (p *configParser) server(name string, node *yaml.Node) (ServerConfig, error) {
+	var server ServerConfig
+	if node.Kind != yaml.MappingNode {
+		return server, nodeError(node, fmt.Sprintf("server %q must be an object", name))
+	}
+
+	fail := func(n *yaml.Node, format string, args ...interface{}) (ServerConfig, error) {
+		return ServerConfig{}, nodeError(n, fmt.Sprintf("server %q: ", name)+fmt.Sprintf(format, args...))
+	}
+
+	var typeNode *yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		var err error
+		switch key.Value {
+		case "type":
+			typeNode = value
+			server.Type, err = p.scalar(value, false)
+		case "command":
+			server.Command, err = p.scalar(value, true)
+		case "args":
+			server.Args, err = p.list(value)
+		case "env":
+			server.Env, err = p.mapping(value)
+		case "cwd":
+			server.Cwd, err = p.scalar(value, true)
+		case "url":
+			server.URL, err = p.scalar(value, true)
+		case "headers":
+			server.Headers, err = p.mapping(value)
+		case "disabled":
+			err = value.Decode(&server.Disabled)
+			if err != nil {
+				err = nodeError(value, "must be a boolean")
+			}
+		default:
+			var extra interface{}
+			if err = value.Decode(&extra); err == nil {
+				if server.Extra == nil {
+					server.Extra = make(map[string]interface{})
+				}
+				server.Extra[key.Value] = extra
+			}
+		}
+		if err != nil {
+			var configErr *ConfigError
+			if errors.As(err, &configErr) {
+				configErr.Msg = fmt.Sprintf("server %q: %s: %s", name, key.Value, configErr.Msg)
+			}
+			return ServerConfig{}, err
+		}
+	}
+
+	switch server.Type {
+	case "":
+		switch {
+		case server.Command != "":
+			server.Type = TransportStdio
+		case server.URL != "":
+			server.Type = TransportHTTP
+		default:
+			return fail(node, "either command or url is required")
+		}
+	case "streamable-http", "streamableHttp":
+		server.Type = TransportHTTP
+	case TransportStdio, TransportSSE, TransportHTTP:
+	default:
+		return fail(typeNode, "unknown type %q, expected %s, %s or %s", server.Type, TransportStdio, TransportSSE, TransportHTTP)
+	}
+
+	switch server.Type {
+	case TransportStdio:
+		if server.Command == "" {
+			return fail(node, "command is required for type %s", server.Type)
+		}
+	default:
+		if server.URL == "" {
+			return fail(node, "url is required for type %s", server.Type)
+		}
+	}
+	return server, nil
+}
+
+func (p *configParser) scalar(node *yaml.Node, expand bool) (string, error) {
+	// Numbers, booleans and other tagged scalars are not taken as strings, even
+	// though their text could be
+	if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!str" {
+		return "", nodeError(node, "must be a string")
+	}
+	if !expand {
+		return node.Value, nil
+	}
+	value, err := expandEnv(node.Value, p.lookup)
+	if err != nil {
+		return "", nodeError(node, err.Error())
+	}
+	return value, nil
+}
+
+func (p *configParser) list(node *yaml.Node) ([]string, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, nodeError(node, "must be a list of strings")
+	}
+	values := make([]string, 0, len(node.Content))
+	for _, item := range node.Content {
+		value, err := p.scalar(item, true)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (p *configParser) mapping(node *yaml.Node) (map[string]string, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nodeError(node, "must be an object of strings")
+	}
+	values := make(map[string]string, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		value, err := p.scalar(node.Content[i+1], true)
+		if err != nil {
+			return nil, err
+		}
+		values[node.Content[i].Value] = value
+	}
+	return values, nil
+}
+
+// expandEnv replaces ${VAR}, ${VAR:-default} and $VAR in s. $$ stands for a
+// literal $.
+func expandEnv(s string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		var name, fallback string
+		hasFallback := false
+		switch next := s[i+1]; {
+		case next == '$':
+			sb.WriteByte('$')
+			i++
+			continue
+		case next == '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference in %q", s)
+			}
+			name = s[i+2 : i+2+end]
+			name, fallback, hasFallback = strings.Cut(name, ":-")
+			i += 2 + end
+		case isNameByte(next, true):
+			j := i + 1
+			for j < len(s) && isNameByte(s[j], j == i+1) {
+				j++
+			}
+			name = s[i+1 : j]
+			i = j - 1
+		default:
+			sb.WriteByte('$')
+			continue
+		}
+
+		if name == "" {
+			return "", fmt.Errorf("empty variable reference in %q", s)
+		}
+		value, ok := lookup(name)
+		if !ok || (hasFallback && value == "") {
+			if !hasFallback {
+				return "", fmt.Errorf("environment variable %s is not set", name)
+			}
+			value = fallback
+		}
+		sb.WriteString(value)
+	}
+	return sb.String(), nil
+}
+
+func isNameByte(c byte, first bool) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || (!first && '0' <= c && c <= '9')
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func nodeError(node *yaml.Node, msg string) *ConfigError {
+	return &ConfigError{Line: node.Line, Column: node.Column, Msg: msg}
+}
+
+func jsonConfigError(data []byte, err error) *ConfigError {
+	// The offset is that of the byte following the offending one
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) && syntaxErr.Offset > 0 {
+		offset = syntaxErr.Offset - 1
+	}
+	// Offsets are relative to the trimmed data
+	offset += int64(len(data) - len(bytes.TrimLeft(data, " \t\r\n")))
+
+	line, column := 1, 1
+	for _, c := range data[:min(offset, int64(len(data)))] {
+		if c == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+	return &ConfigError{Line: line, Column: column, Msg: err.Error()}
+}
+
+var yamlLinePattern = regexp.MustCompile(`^yaml: line (\d+): `)
+
+func yamlConfigError(err error) *ConfigError {
+	msg := err.Error()
+	if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return &ConfigError{Line: line, Msg: strings.TrimPrefix(msg, m[0])}
+	}
+	return &ConfigError{Msg: strings.TrimPrefix(msg, "yaml: ")}
+}
+
+// NewClient creates a client for the server, configured by opts. Stdio servers
+// are started as child processes and SSE servers are connected to right away;
+// streamable HTTP servers are not contacted until the client is initialized.
+func (c ServerConfig) NewClient(opts ...ClientOption) (*Client, error) {
+	switch c.Type {
+	case TransportStdio, "":
+		processOpts := []ClientOption{WithDir(c.Cwd)}
+		for _, key := range sortedKeys(c.Env) {
+			processOpts = append(processOpts, WithEnv(key+"="+c.Env[key]))
+		}
+		stdio, err := NewStdioMCPClientWithOptions(c.Command, c.Args, append(processOpts, opts...)...)
+		if err != nil {
+			return nil, err
+		}
+		return stdio.Client, nil
+	case TransportSSE:
+		return NewSSEMCPClient(c.URL, append(c.headerOptions(), opts...)...)
+	case TransportHTTP:
+		return NewStreamableHTTPMCPClient(c.URL, append(c.headerOptions(), opts...)...)
+	default:
+		return nil, fmt.Errorf("unknown transport type: %s", c.Type)
+	}
+}
+
+func (c ServerConfig) headerOptions() []ClientOption {
+	var opts []ClientOption
+	for _, key := range sortedKeys(c.Headers) {
+		opts = append(opts, WithHeader(key, c.Headers[key]))
+	}
+	return opts
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
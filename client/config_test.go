@@ -0,0 +1,185 @@
+package client
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testLookup(name string) (string, bool) {
+	env := map[string]string{
+		"HOME":  "/home/test",
+		"TOKEN": "secret",
+		"EMPTY": "",
+	}
+	value, ok := env[name]
+	return value, ok
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   map[string]ServerConfig
+	}{
+		{
+			name: "json",
+			config: `{
+				"mcpServers": {
+					"files": {"command": "npx", "args": ["-y", "server", "${HOME}"], "env": {"KEY": "$TOKEN"}, "cwd": "/tmp"},
+					"remote": {"url": "https://example.com/mcp", "headers": {"Authorization": "Bearer ${TOKEN}"}},
+					"events": {"type": "sse", "url": "https://example.com/sse", "disabled": true}
+				}
+			}`,
+			want: map[string]ServerConfig{
+				"files":  {Type: TransportStdio, Command: "npx", Args: []string{"-y", "server", "/home/test"}, Env: map[string]string{"KEY": "secret"}, Cwd: "/tmp"},
+				"remote": {Type: TransportHTTP, URL: "https://example.com/mcp", Headers: map[string]string{"Authorization": "Bearer secret"}},
+				"events": {Type: TransportSSE, URL: "https://example.com/sse", Disabled: true},
+			},
+		},
+		{
+			name: "yaml",
+			config: `
+mcpServers:
+  files:
+    command: server
+    args: [--root, "${ROOT:-/srv}"]
+`,
+			want: map[string]ServerConfig{
+				"files": {Type: TransportStdio, Command: "server", Args: []string{"--root", "/srv"}},
+			},
+		},
+		{
+			name:   "streamable-http alias",
+			config: `{"mcpServers": {"remote": {"type": "streamable-http", "url": "https://example.com/mcp"}}}`,
+			want: map[string]ServerConfig{
+				"remote": {Type: TransportHTTP, URL: "https://example.com/mcp"},
+			},
+		},
+		{
+			name:   "host settings",
+			config: `{"theme": "dark", "mcpServers": {"files": {"command": "server", "autoApprove": ["read"], "timeout": 60, "note": "$UNSET"}}}`,
+			want: map[string]ServerConfig{
+				"files": {Type: TransportStdio, Command: "server", Extra: map[string]interface{}{
+					"autoApprove": []interface{}{"read"},
+					"timeout":     60,
+					"note":        "$UNSET",
+				}},
+			},
+		},
+		{
+			name:   "empty servers",
+			config: `{"mcpServers": {}}`,
+			want:   map[string]ServerConfig{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := parseConfig([]byte(tt.config), testLookup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(config.Servers, tt.want) {
+				t.Errorf("servers = %#v, want %#v", config.Servers, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{"empty", ``, "config is empty"},
+		{"not an object", `[]`, "line 1, column 1: config must be an object"},
+		{"no servers", `{"servers": {}}`, "missing mcpServers"},
+		{"servers not an object", `{"mcpServers": []}`, "mcpServers must be an object"},
+		{"server not an object", `{"mcpServers": {"a": "npx"}}`, `server "a" must be an object`},
+		{"duplicate server", "mcpServers:\n  a: {command: x}\n  a: {command: y}\n", `duplicate server "a"`},
+		{"no command or url", `{"mcpServers": {"a": {}}}`, `server "a": either command or url is required`},
+		{"unknown type", `{"mcpServers": {"a": {"type": "ws", "url": "x"}}}`, `line 1, column 31: server "a": unknown type "ws"`},
+		{"stdio without command", `{"mcpServers": {"a": {"type": "stdio", "url": "x"}}}`, "command is required for type stdio"},
+		{"http without url", `{"mcpServers": {"a": {"type": "http", "command": "x"}}}`, "url is required for type http"},
+		{"args not a list", `{"mcpServers": {"a": {"command": "x", "args": "y"}}}`, `server "a": args: must be a list of strings`},
+		{"env not an object", `{"mcpServers": {"a": {"command": "x", "env": ["y"]}}}`, `server "a": env: must be an object of strings`},
+		{"command not a string", `{"mcpServers": {"a": {"command": ["x"]}}}`, `server "a": command: must be a string`},
+		{"number as a string", `{"mcpServers": {"a": {"command": 123}}}`, `line 1, column 34: server "a": command: must be a string`},
+		{"boolean in args", `{"mcpServers": {"a": {"command": "x", "args": ["-v", true]}}}`, `line 1, column 54: server "a": args: must be a string`},
+		{"number in env", "mcpServers:\n  a:\n    command: x\n    env:\n      PORT: 8080\n", `line 5, column 13: server "a": env: must be a string`},
+		{"tagged scalar", "mcpServers:\n  a:\n    command: !custom x\n", `line 3, column 14: server "a": command: must be a string`},
+		{"disabled not a boolean", `{"mcpServers": {"a": {"command": "x", "disabled": "maybe"}}}`, `server "a": disabled: must be a boolean`},
+		{"unset variable", "mcpServers:\n  a:\n    command: x\n    args: [\"$MISSING\"]\n", `line 4, column 12: server "a": args: environment variable MISSING is not set`},
+		{"unset variable in header", `{"mcpServers": {"a": {"url": "x", "headers": {"A": "${MISSING}"}}}}`, "environment variable MISSING is not set"},
+		{"unterminated variable", `{"mcpServers": {"a": {"command": "${HOME"}}}`, "unterminated variable reference"},
+		{"empty variable", `{"mcpServers": {"a": {"command": "${}"}}}`, "empty variable reference"},
+		{"json syntax", "{\n  \"mcpServers\": {,}\n}", "line 2, column 18: invalid character ','"},
+		{"yaml syntax", "mcpServers:\n  a: [\n", "line 2:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConfig([]byte(tt.config), testLookup)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("error = %v, want a *ConfigError", err)
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"$HOME/bin", "/home/test/bin"},
+		{"${HOME}bin", "/home/testbin"},
+		{"${MISSING:-fallback}", "fallback"},
+		{"${EMPTY:-fallback}", "fallback"},
+		{"${HOME:-fallback}", "/home/test"},
+		{"${EMPTY}", ""},
+		{"$$HOME", "$HOME"},
+		{"cost: 5$", "cost: 5$"},
+		{"$1", "$1"},
+		{"$TOKEN_2", ""},
+	}
+	for _, tt := range tests {
+		got, err := expandEnv(tt.in, func(name string) (string, bool) {
+			if name == "TOKEN_2" {
+				return "", true
+			}
+			return testLookup(name)
+		})
+		if err != nil {
+			t.Errorf("expandEnv(%q): %v", tt.in, err)
+		} else if got != tt.want {
+			t.Errorf("expandEnv(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := expandEnv("$MISSING", testLookup); err == nil || !strings.Contains(err.Error(), "MISSING is not set") {
+		t.Errorf("unset variable: %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	if err := os.WriteFile(path, []byte(`{"mcpServers": {"a": {}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadConfig(path)
+	if err == nil || !strings.HasPrefix(err.Error(), path+":1:") {
+		t.Errorf("error = %v, want it to start with the file position", err)
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("missing file loaded")
+	}
+}
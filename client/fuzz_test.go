@@ -0,0 +1,145 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fuzzTransport delivers scripted server messages once the client sent its
+// first message, then reports the end of the connection
+type fuzzTransport struct {
+	messages [][]byte
+	started  chan struct{}
+	start    sync.Once
+
+	mu   sync.Mutex
+	sent []json.RawMessage
+}
+
+func newFuzzTransport(messages [][]byte) *fuzzTransport {
+	return &fuzzTransport{messages: messages, started: make(chan struct{})}
+}
+
+func (t *fuzzTransport) Send(ctx context.Context, message json.RawMessage) error {
+	t.mu.Lock()
+	t.sent = append(t.sent, append(json.RawMessage(nil), message...))
+	t.mu.Unlock()
+	t.start.Do(func() { close(t.started) })
+	return nil
+}
+
+func (t *fuzzTransport) Receive() (json.RawMessage, error) {
+	<-t.started
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.messages) == 0 {
+		return nil, io.EOF
+	}
+	message := t.messages[0]
+	t.messages = t.messages[1:]
+	return message, nil
+}
+
+func (t *fuzzTransport) Close() error {
+	t.start.Do(func() { close(t.started) })
+	return nil
+}
+
+func FuzzReadMessages(f *testing.F) {
+	for _, seed := range []string{
+		`{"jsonrpc":"2.0","id":1,"result":{}}`,
+		`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`,
+		`{"jsonrpc":"2.0","id":"1","result":{}}`,
+		"{\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\",\"params\":{\"level\":\"info\",\"data\":1}}\n{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}",
+		"{\"jsonrpc\":\"2.0\",\"id\":7,\"method\":\"ping\"}\n{\"jsonrpc\":\"2.0\",\"id\":8,\"method\":\"roots/list\"}",
+		"{\"jsonrpc\":\"2.0\",\"id\":7,\"method\":\"sampling/createMessage\"}\n{\"jsonrpc\":\"2.0\",\"method\":\"notifications/cancelled\",\"params\":{\"requestId\":7}}",
+		`{"jsonrpc":"2.0","id":null,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":1}`,
+		`[1, 2]`,
+		`{`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		transport := newFuzzTransport(bytes.Split(input, []byte("\n")))
+		c := NewClient(transport)
+		c.initialized.Store(true)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := c.Ping(ctx); errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("request still waiting after the connection ended")
+		}
+		c.Close()
+		<-c.Done()
+
+		transport.mu.Lock()
+		defer transport.mu.Unlock()
+		for _, message := range transport.sent {
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(message, &fields); err != nil || string(fields["jsonrpc"]) != `"2.0"` {
+				t.Fatalf("client sent %s, which is not a JSON-RPC message", message)
+			}
+			if _, isRequest := fields["method"]; isRequest {
+				continue
+			}
+			_, hasResult := fields["result"]
+			_, hasError := fields["error"]
+			if hasResult == hasError {
+				t.Fatalf("client sent response %s without exactly one of result and error", message)
+			}
+		}
+	})
+}
+
+func FuzzStdioTransportReceive(f *testing.F) {
+	f.Add([]byte("{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}\n"))
+	f.Add([]byte("\r\n\n  {}\r\n{\"a\":1}"))
+	f.Add([]byte("{}\n\n{}\n"))
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		transport := &StdioTransport{stdout: bufio.NewReader(bytes.NewReader(input))}
+		// The child exiting is only expected after Close
+		transport.closing.Store(true)
+
+		var messages [][]byte
+		for {
+			message, err := transport.Receive()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("failed to receive: %v", err)
+			}
+			if len(message) == 0 || bytes.ContainsRune(message, '\n') || !bytes.Equal(message, bytes.TrimSpace(message)) {
+				t.Fatalf("received a malformed message %q", message)
+			}
+			messages = append(messages, message)
+		}
+
+		// Every complete line holding more than whitespace is a message
+		var expected [][]byte
+		lines := bytes.Split(input, []byte("\n"))
+		for _, line := range lines[:len(lines)-1] {
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				expected = append(expected, line)
+			}
+		}
+		if len(messages) != len(expected) {
+			t.Fatalf("received %d messages from %d lines", len(messages), len(expected))
+		}
+		for i := range messages {
+			if !bytes.Equal(messages[i], expected[i]) {
+				t.Fatalf("message %d is %q instead of %q", i, messages[i], expected[i])
+			}
+		}
+	})
+}
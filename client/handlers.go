@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// RequestHandlerFunc answers a request sent by the server, such as
+// sampling/createMessage or roots/list. The returned result is marshalled into
+// the response. Returning an *mcp.JSONRPCErrorData reports that error to the
+// server as is; any other error is reported as an internal error.
+type RequestHandlerFunc func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// OnNotification registers fn to be called with every notification the server
+// sends for method, after the notification interceptors. Several handlers may be
+// registered for the same method and are called in registration order.
+//
+// Notifications are delivered one at a time, in the order they were received,
+// from the goroutine reading server messages: fn must not block on requests to
+// the server and should hand long-running work off to another goroutine.
+func (c *Client) OnNotification(method string, fn NotificationHandlerFunc) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+	c.notificationHandlers[method] = append(c.notificationHandlers[method], fn)
+}
+
+// OnRequest registers fn to answer the requests the server sends for method,
+// replacing any handler registered before. Requests are handled concurrently,
+// each with a context that is cancelled when the server cancels the request or
+// the connection ends.
+//
+// Requests for methods without a handler are answered with a method-not-found
+// error, except for ping, which is answered automatically.
+func (c *Client) OnRequest(method string, fn RequestHandlerFunc) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+	c.requestHandlers[method] = fn
+}
+
+// Notify sends a notification to the server, such as
+// notifications/roots/list_changed
+func (c *Client) Notify(ctx context.Context, method string, params interface{}) error {
+	notification := struct {
+		Jsonrpc string      `json:"jsonrpc"`
+		Method  string      `json:"method"`
+		Params  interface{} `json:"params,omitempty"`
+	}{
+		Jsonrpc: mcp.JSONRPCVersion,
+		Method:  method,
+		Params:  params,
+	}
+	notificationBytes, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	select {
+	case <-c.done:
+		return c.err
+	default:
+	}
+	if err := c.transport.Send(ctx, notificationBytes); err != nil {
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+	return nil
+}
+
+// handleNotification is the innermost handler of the notification interceptor
+// chain
+func (c *Client) handleNotification(ctx context.Context, method string, params json.RawMessage) {
+	if method == mcp.MethodNotificationCancelled {
+		var p struct {
+			RequestId mcp.RequestID `json:"requestId"`
+		}
+		if json.Unmarshal(params, &p) == nil {
+			if cancel, ok := c.inbound.Load(p.RequestId); ok {
+				cancel.(context.CancelFunc)()
+			}
+		}
+	}
+
+	c.handlersMu.RLock()
+	handlers := c.notificationHandlers[method]
+	c.handlersMu.RUnlock()
+	for _, handler := range handlers {
+		handler(ctx, method, params)
+	}
+}
+
+// handleRequest answers a request sent by the server. The cancel function of
+// ctx is stored in inbound by the caller, before the request is handled
+// concurrently, so that a cancellation read right after it finds it.
+func (c *Client) handleRequest(ctx context.Context, cancel context.CancelFunc, id mcp.RequestID, method string, params json.RawMessage) {
+	defer func() {
+		c.inbound.Delete(id)
+		cancel()
+	}()
+
+	c.handlersMu.RLock()
+	handler, ok := c.requestHandlers[method]
+	c.handlersMu.RUnlock()
+
+	response := mcp.JSONRPCResponse{
+		Jsonrpc: mcp.JSONRPCVersion,
+		Id:      id,
+	}
+	switch {
+	case ok:
+		result, err := handler(ctx, params)
+		if err == nil {
+			response.Result, err = marshalResult(mcp.ForProtocolVersion(result, c.ProtocolVersion()))
+		}
+		if err != nil {
+			var rpcErr *mcp.JSONRPCErrorData
+			if !errors.As(err, &rpcErr) {
+				rpcErr = mcp.NewError(mcp.ErrorCodeInternalError, err.Error())
+			}
+			response.Result = nil
+			response.Error = rpcErr
+		}
+	case method == mcp.MethodPing:
+		response.Result = json.RawMessage("{}")
+	default:
+		response.Error = mcp.NewError(mcp.ErrorCodeMethodNotFound, fmt.Sprintf("method not found: %s", method))
+	}
+
+	// A cancelled request must not be answered
+	if ctx.Err() != nil {
+		return
+	}
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	c.transport.Send(c.ctx, responseBytes)
+}
+
+func marshalResult(result interface{}) (json.RawMessage, error) {
+	if result == nil {
+		return json.RawMessage("{}"), nil
+	}
+	if raw, ok := result.(json.RawMessage); ok {
+		return raw, nil
+	}
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return resultBytes, nil
+}
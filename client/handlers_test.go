@@ -0,0 +1,208 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// scriptedTransport lets a test play the server: it writes the messages the
+// server sends and reads those the client sends
+type scriptedTransport struct {
+	toClient   chan json.RawMessage
+	fromClient chan json.RawMessage
+	closeOnce  sync.Once
+	closed     chan struct{}
+}
+
+func newScriptedTransport() *scriptedTransport {
+	return &scriptedTransport{
+		toClient:   make(chan json.RawMessage, 16),
+		fromClient: make(chan json.RawMessage, 16),
+		closed:     make(chan struct{}),
+	}
+}
+
+func (t *scriptedTransport) Send(ctx context.Context, message json.RawMessage) error {
+	select {
+	case t.fromClient <- message:
+		return nil
+	case <-t.closed:
+		return client.ErrConnectionClosed
+	}
+}
+
+func (t *scriptedTransport) Receive() (json.RawMessage, error) {
+	select {
+	case message := <-t.toClient:
+		return message, nil
+	case <-t.closed:
+		return nil, io.EOF
+	}
+}
+
+func (t *scriptedTransport) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+	return nil
+}
+
+// response waits for the next message the client sends and decodes it as a
+// response
+func (t *scriptedTransport) response(tb testing.TB) mcp.JSONRPCResponse {
+	tb.Helper()
+	select {
+	case message := <-t.fromClient:
+		var response mcp.JSONRPCResponse
+		if err := json.Unmarshal(message, &response); err != nil {
+			tb.Fatalf("failed to decode %s: %v", message, err)
+		}
+		return response
+	case <-time.After(5 * time.Second):
+		tb.Fatal("timed out waiting for a response")
+		return mcp.JSONRPCResponse{}
+	}
+}
+
+func newScriptedClient(t *testing.T) (*client.Client, *scriptedTransport) {
+	transport := newScriptedTransport()
+	c := client.NewClient(transport)
+	t.Cleanup(func() { c.Close() })
+	return c, transport
+}
+
+func TestOnRequest(t *testing.T) {
+	c, transport := newScriptedClient(t)
+	c.OnRequest("roots/list", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return mcp.ListRootsResult{Roots: []mcp.Root{{Uri: "file:///work"}}}, nil
+	})
+
+	transport.toClient <- json.RawMessage(`{"jsonrpc":"2.0","id":"srv-1","method":"roots/list"}`)
+	response := transport.response(t)
+	if response.Id != mcp.NewStringRequestID("srv-1") {
+		t.Errorf("id = %v, want the request's", response.Id)
+	}
+	var result mcp.ListRootsResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Roots) != 1 || result.Roots[0].Uri != "file:///work" {
+		t.Errorf("result = %s", response.Result)
+	}
+}
+
+func TestOnRequestErrors(t *testing.T) {
+	c, transport := newScriptedClient(t)
+	c.OnRequest("custom/rpc-error", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return nil, mcp.NewError(mcp.ErrorCodeInvalidParams, "bad params")
+	})
+	c.OnRequest("custom/error", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return nil, errors.New("failed")
+	})
+
+	tests := []struct {
+		method string
+		code   int
+	}{
+		{"custom/rpc-error", mcp.ErrorCodeInvalidParams},
+		{"custom/error", mcp.ErrorCodeInternalError},
+		{"custom/unknown", mcp.ErrorCodeMethodNotFound},
+	}
+	for _, tt := range tests {
+		transport.toClient <- json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"` + tt.method + `"}`)
+		response := transport.response(t)
+		if response.Error == nil || response.Error.Code != tt.code {
+			t.Errorf("%s: error = %+v, want code %d", tt.method, response.Error, tt.code)
+		}
+	}
+
+	// ping is answered without a handler
+	transport.toClient <- json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	if response := transport.response(t); response.Error != nil || response.Result == nil {
+		t.Errorf("ping response = %+v", response)
+	}
+}
+
+func TestOnRequestCancelled(t *testing.T) {
+	c, transport := newScriptedClient(t)
+	started, cancelled := make(chan struct{}), make(chan struct{})
+	c.OnRequest("custom/wait", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	})
+
+	transport.toClient <- json.RawMessage(`{"jsonrpc":"2.0","id":7,"method":"custom/wait"}`)
+	<-started
+	transport.toClient <- json.RawMessage(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7}}`)
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler not cancelled")
+	}
+
+	// A cancelled request is not answered: the next message the client sends
+	// answers the ping
+	transport.toClient <- json.RawMessage(`{"jsonrpc":"2.0","id":8,"method":"ping"}`)
+	if response := transport.response(t); response.Id != mcp.NewRequestID(8) {
+		t.Errorf("client answered the cancelled request: %+v", response)
+	}
+}
+
+func TestOnRequestCancelledRightAway(t *testing.T) {
+	c, transport := newScriptedClient(t)
+	cancelled := make(chan bool)
+	c.OnRequest("custom/wait", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		select {
+		case <-ctx.Done():
+			cancelled <- true
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			cancelled <- false
+			return struct{}{}, nil
+		}
+	})
+
+	// The cancellation is read right after the request it refers to, possibly
+	// before the handler started
+	for i := 0; i < 20; i++ {
+		transport.toClient <- json.RawMessage(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"custom/wait"}`, i))
+		transport.toClient <- json.RawMessage(fmt.Sprintf(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":%d}}`, i))
+		if !<-cancelled {
+			t.Fatalf("request %d not cancelled", i)
+		}
+	}
+}
+
+func TestOnNotificationOrder(t *testing.T) {
+	c, transport := newScriptedClient(t)
+	calls := make(chan string, 4)
+	for _, name := range []string{"first", "second"} {
+		c.OnNotification(mcp.MethodNotificationMessage, func(ctx context.Context, method string, params json.RawMessage) {
+			calls <- name
+		})
+	}
+
+	transport.toClient <- json.RawMessage(`{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"info","data":"hi"}}`)
+	var got []string
+	for len(got) < 2 {
+		select {
+		case name := <-calls:
+			got = append(got, name)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("handlers called: %v", got)
+		}
+	}
+	if !slices.Equal(got, []string{"first", "second"}) {
+		t.Errorf("handlers called in order %v", got)
+	}
+}
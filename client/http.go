@@ -0,0 +1,301 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrSessionExpired is returned when a server using the streamable HTTP
+// transport no longer recognizes the session, which must then be initialized
+// again with a new client
+var ErrSessionExpired = errors.New("session expired")
+
+const (
+	headerSessionID       = "Mcp-Session-Id"
+	headerProtocolVersion = "MCP-Protocol-Version"
+)
+
+// NewStreamableHTTPMCPClient returns a client talking to a server over the
+// streamable HTTP transport introduced in protocol revision 2025-03-26
+func NewStreamableHTTPMCPClient(url string, opts ...ClientOption) (*Client, error) {
+	transport, err := NewStreamableHTTPTransport(url, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(transport, opts...), nil
+}
+
+// StreamableHTTPTransport is a Transport using the streamable HTTP transport:
+// every message is POSTed to a single endpoint, which answers with JSON or with
+// an event stream carrying the response and related server messages. Once the
+// session is initialized, a GET stream is opened for messages the server sends
+// on its own initiative, if the server offers one.
+type StreamableHTTPTransport struct {
+	url        string
+	httpClient *http.Client
+	headers    http.Header
+
+	mu              sync.Mutex
+	sessionID       string
+	protocolVersion string
+	listening       bool
+
+	messages chan json.RawMessage
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+// NewStreamableHTTPTransport returns a transport to the endpoint at rawURL,
+// configured by the HTTP options among opts. It does not connect until the
+// first message is sent.
+func NewStreamableHTTPTransport(rawURL string, opts ...ClientOption) (*StreamableHTTPTransport, error) {
+	options := newClientOptions(opts)
+	if _, err := url.Parse(rawURL); err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StreamableHTTPTransport{
+		url:        rawURL,
+		httpClient: options.httpClient,
+		headers:    options.headers,
+		messages:   make(chan json.RawMessage, 16),
+		ctx:        ctx,
+		cancel:     cancel,
+	}, nil
+}
+
+func (t *StreamableHTTPTransport) newRequest(ctx context.Context, method string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, t.url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	copyHeaders(req.Header, t.headers)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionID != "" {
+		req.Header.Set(headerSessionID, t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set(headerProtocolVersion, t.protocolVersion)
+	}
+	return req, nil
+}
+
+func (t *StreamableHTTPTransport) Send(ctx context.Context, message json.RawMessage) error {
+	// A response may stream for longer than the caller waits for it, so the
+	// request is bound to the transport and only the wait for the response
+	// headers to the caller's context
+	reqCtx, cancel := context.WithCancel(t.ctx)
+	stop := context.AfterFunc(ctx, cancel)
+
+	req, err := t.newRequest(reqCtx, http.MethodPost, bytes.NewReader(message))
+	if err != nil {
+		cancel()
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := t.httpClient.Do(req)
+	if t.ctx.Err() != nil {
+		cancel()
+		if err == nil {
+			resp.Body.Close()
+		}
+		return ErrConnectionClosed
+	}
+	if !stop() {
+		cancel()
+		if err == nil {
+			resp.Body.Close()
+		}
+		return ctx.Err()
+	}
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	if sessionID := resp.Header.Get(headerSessionID); sessionID != "" {
+		t.mu.Lock()
+		t.sessionID = sessionID
+		t.mu.Unlock()
+	}
+
+	if err := checkStatus(resp); err != nil {
+		resp.Body.Close()
+		cancel()
+		return err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case resp.StatusCode == http.StatusAccepted || resp.ContentLength == 0:
+		resp.Body.Close()
+		cancel()
+	case mediaType == "text/event-stream":
+		go func() {
+			defer cancel()
+			defer resp.Body.Close()
+			t.readStream(resp.Body)
+		}()
+	default:
+		defer cancel()
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		t.deliverBody(body)
+	}
+	return nil
+}
+
+func checkStatus(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusNotFound && resp.Request.Header.Get(headerSessionID) != "":
+		return ErrSessionExpired
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (t *StreamableHTTPTransport) readStream(body io.Reader) {
+	readEvents(body, func(event sseEvent) bool {
+		if event.event == "" || event.event == "message" {
+			return t.deliverBody([]byte(event.data))
+		}
+		return true
+	})
+}
+
+// deliverBody queues a message, or each message of a batch, for Receive
+func (t *StreamableHTTPTransport) deliverBody(body []byte) bool {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return true
+	}
+	messages := []json.RawMessage{body}
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &messages); err != nil {
+			return true
+		}
+	}
+	for _, message := range messages {
+		t.observe(message)
+		select {
+		case t.messages <- message:
+		case <-t.ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// observe picks up the protocol version from the initialize response, which
+// must be sent as a header with every later request, and then opens the stream
+// for server-initiated messages
+func (t *StreamableHTTPTransport) observe(message json.RawMessage) {
+	t.mu.Lock()
+	known := t.protocolVersion != ""
+	t.mu.Unlock()
+	if known {
+		return
+	}
+
+	var response struct {
+		Result struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		} `json:"result"`
+	}
+	if json.Unmarshal(message, &response) != nil || response.Result.ProtocolVersion == "" {
+		return
+	}
+
+	t.mu.Lock()
+	t.protocolVersion = response.Result.ProtocolVersion
+	listen := !t.listening
+	t.listening = true
+	t.mu.Unlock()
+	if listen {
+		go t.listen()
+	}
+}
+
+// listen reads server-initiated messages from the GET stream, reconnecting
+// after interruptions, until the transport is closed or the server declines
+// to offer the stream
+func (t *StreamableHTTPTransport) listen() {
+	const retryDelay = time.Second
+	for t.ctx.Err() == nil {
+		req, err := t.newRequest(t.ctx, http.MethodGet, nil)
+		if err != nil {
+			return
+		}
+		req.Header.Set("Accept", "text/event-stream")
+
+		resp, err := t.httpClient.Do(req)
+		if err == nil {
+			if resp.StatusCode != http.StatusOK {
+				resp.Body.Close()
+				// 405 means the server does not offer the stream
+				return
+			}
+			t.readStream(resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-t.ctx.Done():
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+func (t *StreamableHTTPTransport) Receive() (json.RawMessage, error) {
+	select {
+	case message := <-t.messages:
+		return message, nil
+	case <-t.ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// Close terminates the session on the server, if one was established, and
+// aborts all open streams
+func (t *StreamableHTTPTransport) Close() error {
+	var err error
+	t.closeOnce.Do(func() {
+		t.mu.Lock()
+		sessionID := t.sessionID
+		t.mu.Unlock()
+
+		if sessionID != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			var req *http.Request
+			if req, err = t.newRequest(ctx, http.MethodDelete, nil); err == nil {
+				var resp *http.Response
+				if resp, err = t.httpClient.Do(req); err == nil {
+					resp.Body.Close()
+				}
+			}
+		}
+		t.cancel()
+	})
+	return err
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server"
+	"github.com/WePrompt/gomcp/server/handlers"
+)
+
+func newHTTPTestServer() *server.MCPServer {
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.NewTextContent("echo")}}, nil
+	})
+	return server.NewMCPServer(server.WithToolHandler(tools))
+}
+
+// handleMessage answers a JSON-RPC message with s, returning nil for
+// notifications
+func handleMessage(ctx context.Context, s *server.MCPServer, message []byte) json.RawMessage {
+	var request mcp.JSONRPCRequest
+	if err := json.Unmarshal(message, &request); err != nil || request.Id.IsNull() {
+		return nil
+	}
+	response := mcp.JSONRPCResponse{Jsonrpc: mcp.JSONRPCVersion, Id: request.Id}
+	result, err := s.Request(ctx, request.Method, request.Params)
+	var rpcErr *mcp.JSONRPCErrorData
+	switch {
+	case errors.As(err, &rpcErr):
+		response.Error = rpcErr
+	case err != nil:
+		response.Error = &mcp.JSONRPCErrorData{Code: mcp.ErrorCodeInternalError, Message: err.Error()}
+	case result == nil:
+		response.Result = json.RawMessage("{}")
+	default:
+		response.Result = result
+	}
+	b, _ := json.Marshal(response)
+	return b
+}
+
+func initializeClient(t *testing.T, c *Client) {
+	t.Helper()
+	if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, ""); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+}
+
+// streamableServer serves an MCPServer over the streamable HTTP transport,
+// recording the requests it receives
+type streamableServer struct {
+	mcp *server.MCPServer
+	// stream answers with an event stream carrying a notification before the
+	// response, rather than with JSON
+	stream bool
+
+	mu       sync.Mutex
+	sessions map[string]*server.Session
+	headers  []http.Header
+	deleted  []string
+}
+
+func newStreamableServer(t *testing.T, stream bool) (*streamableServer, *httptest.Server) {
+	s := &streamableServer{mcp: newHTTPTestServer(), stream: stream, sessions: make(map[string]*server.Session)}
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func (s *streamableServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(headerSessionID)
+	s.mu.Lock()
+	s.headers = append(s.headers, r.Header.Clone())
+	session, ok := s.sessions[id]
+	switch {
+	case r.Method == http.MethodDelete:
+		delete(s.sessions, id)
+		s.deleted = append(s.deleted, id)
+	case id == "":
+		id = fmt.Sprintf("session-%d", len(s.sessions)+1)
+		session = server.NewSession(id)
+		s.sessions[id] = session
+		ok = true
+	}
+	s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet:
+		// No stream for server-initiated messages
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	case r.Method == http.MethodDelete:
+		return
+	case !ok:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	response := handleMessage(server.ContextWithSession(r.Context(), session), s.mcp, body)
+	w.Header().Set(headerSessionID, id)
+	switch {
+	case response == nil:
+		w.WriteHeader(http.StatusAccepted)
+	case s.stream:
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", `{"jsonrpc":"2.0","method":"notifications/test"}`)
+		fmt.Fprintf(w, ": keep-alive\n\nevent: message\ndata: %s\n\n", response)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(response)
+	}
+}
+
+func (s *streamableServer) expireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]*server.Session)
+}
+
+func TestStreamableHTTP(t *testing.T) {
+	s, ts := newStreamableServer(t, false)
+	c, err := NewStreamableHTTPMCPClient(ts.URL, WithHeader("Authorization", "Bearer token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	initializeClient(t, c)
+
+	result, err := c.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(result.Tools) != 1 || result.Tools[0].Name != "echo" {
+		t.Errorf("tools = %+v", result.Tools)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headers[0].Get(headerSessionID) != "" || s.headers[0].Get(headerProtocolVersion) != "" {
+		t.Errorf("initialize sent session headers: %v", s.headers[0])
+	}
+	for i, header := range s.headers {
+		if header.Get("Authorization") != "Bearer token" {
+			t.Errorf("request %d: Authorization = %q", i, header.Get("Authorization"))
+		}
+		if i == 0 {
+			continue
+		}
+		if header.Get(headerSessionID) != "session-1" || header.Get(headerProtocolVersion) != mcp.LatestProtocolVersion {
+			t.Errorf("request %d: session %q, protocol version %q", i, header.Get(headerSessionID), header.Get(headerProtocolVersion))
+		}
+	}
+	if len(s.deleted) != 1 || s.deleted[0] != "session-1" {
+		t.Errorf("deleted sessions = %v, want the session terminated on Close", s.deleted)
+	}
+}
+
+func TestStreamableHTTPEventStream(t *testing.T) {
+	_, ts := newStreamableServer(t, true)
+	notified := make(chan struct{}, 8)
+	c, err := NewStreamableHTTPMCPClient(ts.URL, WithNotificationInterceptors(func(ctx context.Context, method string, params json.RawMessage, next NotificationHandlerFunc) {
+		if method == "notifications/test" {
+			notified <- struct{}{}
+		}
+		next(ctx, method, params)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	initializeClient(t, c)
+	if _, err := c.ListTools(context.Background(), nil); err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		t.Error("notification sent on the response stream not received")
+	}
+}
+
+func TestStreamableHTTPSessionExpired(t *testing.T) {
+	s, ts := newStreamableServer(t, false)
+	c, err := NewStreamableHTTPMCPClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	initializeClient(t, c)
+
+	s.expireSessions()
+	if _, err := c.ListTools(context.Background(), nil); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("error = %v, want %v", err, ErrSessionExpired)
+	}
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// answerInitialize returns an Invoker answering initialize with version, and
+// recording the version the client requested
+func answerInitialize(version string, requested *string) Invoker {
+	return func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		*requested = p.ProtocolVersion
+		return json.Marshal(map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{},
+			"serverInfo":      map[string]interface{}{"name": "test", "version": "1"},
+		})
+	}
+}
+
+// discardTransport drops the messages sent by a client whose requests are
+// answered by its invoke function
+type discardTransport struct{}
+
+func (discardTransport) Send(ctx context.Context, message json.RawMessage) error { return nil }
+func (discardTransport) Receive() (json.RawMessage, error)                       { return nil, io.EOF }
+func (discardTransport) Close() error                                            { return nil }
+
+func TestClientRejectsUnsupportedVersion(t *testing.T) {
+	// The server answers with a revision the client does not know
+	var requested string
+	c := &Client{transport: discardTransport{}, invoke: answerInitialize("2099-01-01", &requested)}
+	_, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, "")
+	if err == nil || !strings.Contains(err.Error(), "2099-01-01") {
+		t.Fatalf("Initialize error = %v, want an unsupported version", err)
+	}
+	if requested != mcp.LatestProtocolVersion {
+		t.Errorf("requested %q, want the latest revision by default", requested)
+	}
+	if version := c.ProtocolVersion(); version != "" {
+		t.Errorf("ProtocolVersion() = %q after a failed Initialize", version)
+	}
+}
+
+func TestClientNegotiatesOlderVersion(t *testing.T) {
+	var requested string
+	c := &Client{transport: discardTransport{}, invoke: answerInitialize(mcp.ProtocolVersion20241105, &requested)}
+	result, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, mcp.ProtocolVersion20241105)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requested != mcp.ProtocolVersion20241105 {
+		t.Errorf("requested %q, want %s", requested, mcp.ProtocolVersion20241105)
+	}
+	if result.ProtocolVersion != mcp.ProtocolVersion20241105 || c.ProtocolVersion() != mcp.ProtocolVersion20241105 {
+		t.Errorf("negotiated %s (client reports %s), want %s", result.ProtocolVersion, c.ProtocolVersion(), mcp.ProtocolVersion20241105)
+	}
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Invoker sends a request to the server and returns the raw result
+type Invoker func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error)
+
+// Interceptor wraps every outbound request sent by a client. It may inspect or
+// rewrite the method and params, inspect or replace the result, retry by calling
+// invoker more than once, or short-circuit by not calling it at all.
+type Interceptor func(ctx context.Context, method string, params json.RawMessage, invoker Invoker) (json.RawMessage, error)
+
+// NotificationHandlerFunc handles a notification received from the server
+type NotificationHandlerFunc func(ctx context.Context, method string, params json.RawMessage)
+
+// NotificationInterceptor wraps every inbound notification received by a client.
+// It may inspect or rewrite the notification, or drop it by not calling next.
+type NotificationInterceptor func(ctx context.Context, method string, params json.RawMessage, next NotificationHandlerFunc)
+
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+			return interceptor(ctx, method, params, next)
+		}
+	}
+	return invoker
+}
+
+func chainNotificationInterceptors(interceptors []NotificationInterceptor, handler NotificationHandlerFunc) NotificationHandlerFunc {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, method string, params json.RawMessage) {
+			interceptor(ctx, method, params, next)
+		}
+	}
+	return handler
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestInterceptorOrder(t *testing.T) {
+	var order []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, method string, params json.RawMessage, invoker Invoker) (json.RawMessage, error) {
+			order = append(order, name+" "+method)
+			result, err := invoker(ctx, method, params)
+			order = append(order, name+" done")
+			return result, err
+		}
+	}
+	options := newClientOptions([]ClientOption{WithInterceptors(record("first")), WithInterceptors(record("second"))})
+	invoke := chainInterceptors(options.interceptors, func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+		order = append(order, "server "+method)
+		return json.RawMessage(`{}`), nil
+	})
+
+	if _, err := invoke(context.Background(), "ping", nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"first ping", "second ping", "server ping", "second done", "first done"}
+	if !slices.Equal(order, want) {
+		t.Errorf("order = %q, want %q", order, want)
+	}
+}
+
+func TestInterceptorRewrites(t *testing.T) {
+	rewrite := func(ctx context.Context, method string, params json.RawMessage, invoker Invoker) (json.RawMessage, error) {
+		result, err := invoker(ctx, method, json.RawMessage(`{"name":"other"}`))
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(`{"rewritten":` + string(result) + `}`), nil
+	}
+	var sent string
+	invoke := chainInterceptors([]Interceptor{rewrite}, func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+		sent = string(params)
+		return json.RawMessage(`true`), nil
+	})
+
+	result, err := invoke(context.Background(), "tools/call", json.RawMessage(`{"name":"echo"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != `{"name":"other"}` {
+		t.Errorf("server received %s, want the rewritten params", sent)
+	}
+	if string(result) != `{"rewritten":true}` {
+		t.Errorf("result = %s, want the rewritten result", result)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	denied := errors.New("denied")
+	deny := func(ctx context.Context, method string, params json.RawMessage, invoker Invoker) (json.RawMessage, error) {
+		return nil, denied
+	}
+	called := false
+	invoke := chainInterceptors([]Interceptor{deny}, func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+		called = true
+		return nil, nil
+	})
+
+	if _, err := invoke(context.Background(), "tools/call", nil); !errors.Is(err, denied) {
+		t.Errorf("error = %v, want %v", err, denied)
+	}
+	if called {
+		t.Error("request sent although the interceptor returned early")
+	}
+}
+
+func TestNotificationInterceptor(t *testing.T) {
+	var received []string
+	drop := func(ctx context.Context, method string, params json.RawMessage, next NotificationHandlerFunc) {
+		if method == "notifications/tools/list_changed" {
+			return
+		}
+		next(ctx, method, params)
+	}
+	tag := func(ctx context.Context, method string, params json.RawMessage, next NotificationHandlerFunc) {
+		next(ctx, method, json.RawMessage(`{"tagged":true}`))
+	}
+	options := newClientOptions([]ClientOption{WithNotificationInterceptors(drop, tag)})
+	notify := chainNotificationInterceptors(options.notificationInterceptors, func(ctx context.Context, method string, params json.RawMessage) {
+		received = append(received, method+" "+string(params))
+	})
+
+	notify(context.Background(), "notifications/tools/list_changed", nil)
+	notify(context.Background(), "notifications/prompts/list_changed", nil)
+	want := []string{`notifications/prompts/list_changed {"tagged":true}`}
+	if !slices.Equal(received, want) {
+		t.Errorf("received %q, want %q", received, want)
+	}
+}
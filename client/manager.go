@@ -0,0 +1,496 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// DefaultSeparator joins server names to tool and prompt names in the catalog
+// of a Manager
+const DefaultSeparator = "__"
+
+// ManagerOption configures a Manager
+type ManagerOption func(*Manager)
+
+// WithSeparator sets the string joining server names to tool and prompt names
+func WithSeparator(separator string) ManagerOption {
+	return func(m *Manager) {
+		m.separator = separator
+	}
+}
+
+// WithClientOptions sets options applied to every client started by the manager
+func WithClientOptions(opts ...ClientOption) ManagerOption {
+	return func(m *Manager) {
+		m.clientOptions = append(m.clientOptions, opts...)
+	}
+}
+
+// WithServerInterceptors sets a function returning the interceptors of the
+// client started for each server, after those set by WithClientOptions. It
+// gives every server its own retry state and circuit breaker:
+//
+//	client.WithServerInterceptors(func(server string) []client.Interceptor {
+//		return []client.Interceptor{
+//			client.RetryInterceptor(client.DefaultRetryPolicy),
+//			client.NewCircuitBreaker(client.DefaultCircuitBreakerPolicy).Interceptor(),
+//		}
+//	})
+func WithServerInterceptors(fn func(server string) []Interceptor) ManagerOption {
+	return func(m *Manager) {
+		m.serverInterceptors = fn
+	}
+}
+
+// WithClientInfo sets the implementation info sent when initializing servers
+func WithClientInfo(info mcp.Implementation) ManagerOption {
+	return func(m *Manager) {
+		m.clientInfo = info
+	}
+}
+
+// WithClientCapabilities sets the capabilities sent when initializing servers
+func WithClientCapabilities(capabilities mcp.ClientCapabilities) ManagerOption {
+	return func(m *Manager) {
+		m.capabilities = capabilities
+	}
+}
+
+// WithCatalogChangedHandler sets a function called after the catalog entries of
+// a server have been refreshed
+func WithCatalogChangedHandler(fn func(server string)) ManagerOption {
+	return func(m *Manager) {
+		m.onCatalogChanged = fn
+	}
+}
+
+// WithRefreshErrorHandler sets a function called when refreshing the catalog
+// entries of a server in the background, after a list_changed notification,
+// fails. The entries of the server are left as they were.
+func WithRefreshErrorHandler(fn func(server string, err error)) ManagerOption {
+	return func(m *Manager) {
+		m.onRefreshError = fn
+	}
+}
+
+// Manager owns connections to several MCP servers and presents their tools,
+// prompts and resources as a single catalog. Tool and prompt names are
+// namespaced as "<server><separator><name>", and calls are routed to the server
+// the name belongs to. Resources keep their URIs and are routed by URI.
+type Manager struct {
+	separator          string
+	clientOptions      []ClientOption
+	serverInterceptors func(server string) []Interceptor
+	clientInfo         mcp.Implementation
+	capabilities       mcp.ClientCapabilities
+	onCatalogChanged   func(server string)
+	onRefreshError     func(server string, err error)
+
+	// ctx is cancelled on Close, stopping background refreshes
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.RWMutex
+	servers map[string]*managedServer
+}
+
+type managedServer struct {
+	client       MCPClient
+	capabilities mcp.ServerCapabilities
+	tools        []mcp.Tool
+	prompts      []mcp.Prompt
+	resources    []mcp.Resource
+}
+
+type catalogKind int
+
+const (
+	catalogTools catalogKind = 1 << iota
+	catalogPrompts
+	catalogResources
+
+	catalogAll = catalogTools | catalogPrompts | catalogResources
+)
+
+func NewManager(opts ...ManagerOption) *Manager {
+	m := &Manager{
+		separator: DefaultSeparator,
+		clientInfo: mcp.Implementation{
+			Name:    "gomcp-manager",
+			Version: "1.0.0",
+		},
+		servers: make(map[string]*managedServer),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	return m
+}
+
+// Start connects to the server described by config, starting it first if it
+// is a stdio server, initializes it and adds it to the catalog under name. The
+// catalog is refreshed whenever the server sends a list_changed notification.
+func (m *Manager) Start(ctx context.Context, name string, config ServerConfig) error {
+	if err := m.checkName(name); err != nil {
+		return err
+	}
+
+	opts := append(append([]ClientOption{}, m.clientOptions...), WithNotificationInterceptors(m.ListChangedInterceptor(name)))
+	if m.serverInterceptors != nil {
+		opts = append(opts, WithInterceptors(m.serverInterceptors(name)...))
+	}
+	client, err := config.NewClient(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to start server %s: %w", name, err)
+	}
+	if err := m.Add(ctx, name, client); err != nil {
+		client.Close()
+		return err
+	}
+	return nil
+}
+
+// StartAll starts every configured server that is not disabled concurrently.
+// Servers that start successfully stay in the catalog even if others fail.
+func (m *Manager) StartAll(ctx context.Context, servers map[string]ServerConfig) error {
+	var wg sync.WaitGroup
+	errs := make([]error, 0, len(servers))
+	var errsMu sync.Mutex
+	for name, config := range servers {
+		if config.Disabled {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.Start(ctx, name, config); err != nil {
+				errsMu.Lock()
+				errs = append(errs, err)
+				errsMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Add initializes an existing client and adds it to the catalog under name. The
+// manager takes ownership of the client and closes it on Remove or Close if it
+// implements io.Closer. To have its catalog refreshed on list_changed
+// notifications, the client must have been created with ListChangedInterceptor.
+// If Add fails, the client is not added and the caller keeps ownership of it.
+func (m *Manager) Add(ctx context.Context, name string, client MCPClient) error {
+	if err := m.checkName(name); err != nil {
+		return err
+	}
+
+	result, err := client.Initialize(ctx, m.capabilities, m.clientInfo, mcp.LatestProtocolVersion)
+	if err != nil {
+		return fmt.Errorf("failed to initialize server %s: %w", name, err)
+	}
+
+	m.mu.Lock()
+	if _, ok := m.servers[name]; ok {
+		m.mu.Unlock()
+		return fmt.Errorf("server %s already exists", name)
+	}
+	server := &managedServer{client: client, capabilities: result.Capabilities}
+	m.servers[name] = server
+	m.mu.Unlock()
+
+	if err := m.refresh(ctx, name, catalogAll); err != nil {
+		m.mu.Lock()
+		if m.servers[name] == server {
+			delete(m.servers, name)
+		}
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (m *Manager) checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("server name must not be empty")
+	}
+	if strings.Contains(name, m.separator) {
+		return fmt.Errorf("server name %s must not contain the separator %q", name, m.separator)
+	}
+	return nil
+}
+
+// Remove removes the named server from the catalog and closes its client
+func (m *Manager) Remove(name string) error {
+	m.mu.Lock()
+	server, ok := m.servers[name]
+	delete(m.servers, name)
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown server: %s", name)
+	}
+	return closeClient(server.client)
+}
+
+// Close closes every client and stops background refreshes
+func (m *Manager) Close() error {
+	m.cancel()
+
+	m.mu.Lock()
+	servers := m.servers
+	m.servers = make(map[string]*managedServer)
+	m.mu.Unlock()
+
+	var errs []error
+	for name, server := range servers {
+		if err := closeClient(server.client); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close server %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func closeClient(client MCPClient) error {
+	if closer, ok := client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Servers returns the names of the managed servers in sorted order
+func (m *Manager) Servers() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.servers))
+	for name := range m.servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Client returns the client of the named server
+func (m *Manager) Client(name string) (MCPClient, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	server, ok := m.servers[name]
+	if !ok {
+		return nil, false
+	}
+	return server.client, true
+}
+
+// Refresh reloads the tools, prompts and resources of the named server
+func (m *Manager) Refresh(ctx context.Context, name string) error {
+	return m.refresh(ctx, name, catalogAll)
+}
+
+func (m *Manager) refresh(ctx context.Context, name string, kinds catalogKind) error {
+	m.mu.RLock()
+	server, ok := m.servers[name]
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown server: %s", name)
+	}
+
+	var (
+		tools     []mcp.Tool
+		prompts   []mcp.Prompt
+		resources []mcp.Resource
+		err       error
+	)
+	if kinds&catalogTools != 0 && server.capabilities.Tools != nil {
+		if tools, err = collect(AllTools(ctx, server.client)); err != nil {
+			return fmt.Errorf("failed to list tools of server %s: %w", name, err)
+		}
+	}
+	if kinds&catalogPrompts != 0 && server.capabilities.Prompts != nil {
+		if prompts, err = collect(AllPrompts(ctx, server.client)); err != nil {
+			return fmt.Errorf("failed to list prompts of server %s: %w", name, err)
+		}
+	}
+	if kinds&catalogResources != 0 && server.capabilities.Resources != nil {
+		if resources, err = collect(AllResources(ctx, server.client)); err != nil {
+			return fmt.Errorf("failed to list resources of server %s: %w", name, err)
+		}
+	}
+
+	m.mu.Lock()
+	// The server may have been removed while listing
+	if m.servers[name] != server {
+		m.mu.Unlock()
+		return nil
+	}
+	if kinds&catalogTools != 0 {
+		server.tools = tools
+	}
+	if kinds&catalogPrompts != 0 {
+		server.prompts = prompts
+	}
+	if kinds&catalogResources != 0 {
+		server.resources = resources
+	}
+	m.mu.Unlock()
+
+	if m.onCatalogChanged != nil {
+		m.onCatalogChanged(name)
+	}
+	return nil
+}
+
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// ListChangedInterceptor returns a notification interceptor that refreshes the
+// catalog entries of the named server when it reports that its tools, prompts
+// or resources changed. Refreshes run in the background, since the notification
+// is delivered on the goroutine that reads the server's responses.
+func (m *Manager) ListChangedInterceptor(name string) NotificationInterceptor {
+	return func(ctx context.Context, method string, params json.RawMessage, next NotificationHandlerFunc) {
+		var kind catalogKind
+		switch method {
+		case mcp.MethodNotificationToolsListChanged:
+			kind = catalogTools
+		case mcp.MethodNotificationPromptsListChanged:
+			kind = catalogPrompts
+		case mcp.MethodNotificationResourcesListChanged:
+			kind = catalogResources
+		}
+		if kind != 0 {
+			go m.refreshInBackground(name, kind)
+		}
+		next(ctx, method, params)
+	}
+}
+
+func (m *Manager) refreshInBackground(name string, kinds catalogKind) {
+	err := m.refresh(m.ctx, name, kinds)
+	// Refreshes are expected to fail once the manager is closed
+	if err != nil && m.ctx.Err() == nil && m.onRefreshError != nil {
+		m.onRefreshError(name, err)
+	}
+}
+
+// Tools returns the tools of every server, with namespaced names, ordered by
+// server name
+func (m *Manager) Tools() []mcp.Tool {
+	var tools []mcp.Tool
+	m.each(func(name string, server *managedServer) {
+		for _, tool := range server.tools {
+			tool.Name = m.qualify(name, tool.Name)
+			tools = append(tools, tool)
+		}
+	})
+	return tools
+}
+
+// Prompts returns the prompts of every server, with namespaced names, ordered
+// by server name
+func (m *Manager) Prompts() []mcp.Prompt {
+	var prompts []mcp.Prompt
+	m.each(func(name string, server *managedServer) {
+		for _, prompt := range server.prompts {
+			prompt.Name = m.qualify(name, prompt.Name)
+			prompts = append(prompts, prompt)
+		}
+	})
+	return prompts
+}
+
+// Resources returns the resources of every server, with namespaced names and
+// their original URIs, ordered by server name
+func (m *Manager) Resources() []mcp.Resource {
+	var resources []mcp.Resource
+	m.each(func(name string, server *managedServer) {
+		for _, resource := range server.resources {
+			resource.Name = m.qualify(name, resource.Name)
+			resources = append(resources, resource)
+		}
+	})
+	return resources
+}
+
+func (m *Manager) each(fn func(name string, server *managedServer)) {
+	names := m.Servers()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, name := range names {
+		if server, ok := m.servers[name]; ok {
+			fn(name, server)
+		}
+	}
+}
+
+func (m *Manager) qualify(server, name string) string {
+	return server + m.separator + name
+}
+
+// resolve splits a namespaced name into the client of its server and the name
+// known to that server
+func (m *Manager) resolve(qualified string) (MCPClient, string, error) {
+	name, local, ok := strings.Cut(qualified, m.separator)
+	if !ok {
+		return nil, "", fmt.Errorf("name %s is not namespaced with a server", qualified)
+	}
+	client, ok := m.Client(name)
+	if !ok {
+		return nil, "", fmt.Errorf("unknown server: %s", name)
+	}
+	return client, local, nil
+}
+
+// CallTool calls a tool by its namespaced name
+func (m *Manager) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	client, tool, err := m.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return client.CallTool(ctx, tool, arguments)
+}
+
+// GetPrompt gets a prompt by its namespaced name
+func (m *Manager) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*mcp.GetPromptResult, error) {
+	client, prompt, err := m.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetPrompt(ctx, prompt, arguments)
+}
+
+// ReadResource reads a resource from the first server, by name, whose catalog
+// lists its URI
+func (m *Manager) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	var client MCPClient
+	m.each(func(name string, server *managedServer) {
+		if client != nil {
+			return
+		}
+		for _, resource := range server.resources {
+			if resource.Uri == uri {
+				client = server.client
+				return
+			}
+		}
+	})
+	if client == nil {
+		return nil, fmt.Errorf("unknown resource: %s", uri)
+	}
+	return client.ReadResource(ctx, uri)
+}
@@ -0,0 +1,325 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// fakeServer is an MCPClient standing in for a connected server offering tools
+// and resources, which counts the requests it receives. Calls to a tool return
+// its name as text.
+type fakeServer struct {
+	client.MCPClient
+
+	mu        sync.Mutex
+	tools     []mcp.Tool
+	resources map[string]string
+	// pageSize splits the tool list into pages when set
+	pageSize  int
+	listDelay time.Duration
+	listErr   error
+	calls     map[string]int
+	handlers  map[string][]client.NotificationHandlerFunc
+	closed    bool
+}
+
+func newFakeServer(tools ...string) *fakeServer {
+	s := &fakeServer{
+		resources: make(map[string]string),
+		calls:     make(map[string]int),
+		handlers:  make(map[string][]client.NotificationHandlerFunc),
+	}
+	for _, name := range tools {
+		s.tools = append(s.tools, mcp.Tool{Name: name})
+	}
+	return s
+}
+
+// callCount returns the number of requests received for method
+func (s *fakeServer) callCount(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func (s *fakeServer) OnNotification(method string, fn client.NotificationHandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = append(s.handlers[method], fn)
+}
+
+// notify delivers a notification from the server to the registered handlers
+func (s *fakeServer) notify(method string, params json.RawMessage) {
+	s.mu.Lock()
+	handlers := slices.Clone(s.handlers[method])
+	s.mu.Unlock()
+	for _, fn := range handlers {
+		fn(context.Background(), method, params)
+	}
+}
+
+func (s *fakeServer) Initialize(ctx context.Context, capabilities mcp.ClientCapabilities, clientInfo mcp.Implementation, protocolVersion string) (*mcp.InitializeResult, error) {
+	s.mu.Lock()
+	s.calls[mcp.MethodInitialize]++
+	s.mu.Unlock()
+	return &mcp.InitializeResult{
+		ProtocolVersion: protocolVersion,
+		Capabilities: mcp.ServerCapabilities{
+			Tools:     &mcp.ServerCapabilitiesTools{},
+			Resources: &mcp.ServerCapabilitiesResources{},
+		},
+	}, nil
+}
+
+func (s *fakeServer) ListTools(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error) {
+	s.mu.Lock()
+	s.calls[mcp.MethodToolsList]++
+	delay := s.listDelay
+	s.mu.Unlock()
+	time.Sleep(delay)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listErr != nil {
+		return nil, s.listErr
+	}
+	if s.pageSize == 0 {
+		return &mcp.ListToolsResult{Tools: slices.Clone(s.tools)}, nil
+	}
+	start := 0
+	if cursor != nil {
+		start, _ = strconv.Atoi(*cursor)
+	}
+	end := min(start+s.pageSize, len(s.tools))
+	result := &mcp.ListToolsResult{Tools: slices.Clone(s.tools[start:end])}
+	if end < len(s.tools) {
+		next := strconv.Itoa(end)
+		result.NextCursor = &next
+	}
+	return result, nil
+}
+
+func (s *fakeServer) ListResources(ctx context.Context, cursor *string) (*mcp.ListResourcesResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[mcp.MethodResourcesList]++
+	result := &mcp.ListResourcesResult{}
+	for uri := range s.resources {
+		result.Resources = append(result.Resources, mcp.Resource{Uri: uri, Name: uri})
+	}
+	return result, nil
+}
+
+func (s *fakeServer) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	result := &mcp.CallToolResult{}
+	result.AddTextContent(mcp.NewTextContent(name))
+	return result, nil
+}
+
+func (s *fakeServer) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[mcp.MethodResourcesRead]++
+	text, ok := s.resources[uri]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource: %s", uri)
+	}
+	return &mcp.ReadResourceResult{Contents: []mcp.ResourceContents{mcp.TextResourceContents{Uri: uri, Text: text}}}, nil
+}
+
+func (s *fakeServer) SubscribeResource(ctx context.Context, uri string) error {
+	return nil
+}
+
+func (s *fakeServer) UnsubscribeResource(ctx context.Context, uri string) error {
+	return nil
+}
+
+func (s *fakeServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func toolNames(m *client.Manager) []string {
+	var names []string
+	for _, tool := range m.Tools() {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestManagerCatalog(t *testing.T) {
+	m := client.NewManager()
+	defer m.Close()
+	a := newFakeServer("read", "write")
+	a.resources["test://a"] = "from a"
+	if err := m.Add(context.Background(), "a", a); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(context.Background(), "b", newFakeServer("read")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := toolNames(m), []string{"a__read", "a__write", "b__read"}; !slices.Equal(got, want) {
+		t.Errorf("Tools() = %v, want %v", got, want)
+	}
+	if got := m.Servers(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("Servers() = %v", got)
+	}
+
+	result, err := m.CallTool(context.Background(), "b__read", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "read" {
+		t.Errorf("CallTool routed to the wrong tool: %q", text)
+	}
+	contents, err := m.ReadResource(context.Background(), "test://a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := contents.Contents[0].(mcp.TextResourceContents).Text; text != "from a" {
+		t.Errorf("ReadResource = %q", text)
+	}
+
+	for _, name := range []string{"read", "c__read"} {
+		if _, err := m.CallTool(context.Background(), name, nil); err == nil {
+			t.Errorf("CallTool(%q) succeeded", name)
+		}
+	}
+	if _, err := m.ReadResource(context.Background(), "test://missing"); err == nil {
+		t.Error("ReadResource of an unknown URI succeeded")
+	}
+
+	if err := m.Remove("a"); err != nil {
+		t.Fatal(err)
+	}
+	if !a.closed {
+		t.Error("client of a removed server not closed")
+	}
+	if got := toolNames(m); !slices.Equal(got, []string{"b__read"}) {
+		t.Errorf("Tools() after Remove = %v", got)
+	}
+	if err := m.Remove("a"); err == nil {
+		t.Error("removing an unknown server succeeded")
+	}
+}
+
+func TestManagerNames(t *testing.T) {
+	m := client.NewManager()
+	defer m.Close()
+	for _, name := range []string{"", "a__b"} {
+		if err := m.Add(context.Background(), name, newFakeServer()); err == nil {
+			t.Errorf("server name %q accepted", name)
+		}
+	}
+	if err := m.Add(context.Background(), "a", newFakeServer()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(context.Background(), "a", newFakeServer()); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("duplicate server name: %v", err)
+	}
+}
+
+func TestManagerAddRefreshFailure(t *testing.T) {
+	m := client.NewManager()
+	defer m.Close()
+	s := newFakeServer("read")
+	s.listErr = errors.New("unavailable")
+
+	if err := m.Add(context.Background(), "a", s); err == nil {
+		t.Fatal("Add succeeded although the tools could not be listed")
+	}
+	if got := m.Servers(); len(got) != 0 {
+		t.Fatalf("failed server left in the catalog: %v", got)
+	}
+	if _, err := m.CallTool(context.Background(), "a__read", nil); err == nil {
+		t.Error("CallTool routed to a server that failed to be added")
+	}
+
+	// The name is free to be used again
+	s.mu.Lock()
+	s.listErr = nil
+	s.mu.Unlock()
+	if err := m.Add(context.Background(), "a", s); err != nil {
+		t.Fatalf("adding the server again: %v", err)
+	}
+	if got := toolNames(m); !slices.Equal(got, []string{"a__read"}) {
+		t.Errorf("Tools() = %v", got)
+	}
+}
+
+func TestManagerListChanged(t *testing.T) {
+	failed := make(chan error, 1)
+	m := client.NewManager(client.WithRefreshErrorHandler(func(server string, err error) {
+		select {
+		case failed <- err:
+		default:
+		}
+	}))
+	defer m.Close()
+	s := newFakeServer("read")
+	if err := m.Add(context.Background(), "a", s); err != nil {
+		t.Fatal(err)
+	}
+	listChanged := func() {
+		t.Helper()
+		forwarded := false
+		m.ListChangedInterceptor("a")(context.Background(), mcp.MethodNotificationToolsListChanged, nil, func(ctx context.Context, method string, params json.RawMessage) {
+			forwarded = true
+		})
+		if !forwarded {
+			t.Error("notification not passed on")
+		}
+	}
+
+	s.mu.Lock()
+	s.tools = append(s.tools, mcp.Tool{Name: "write"})
+	s.mu.Unlock()
+	listChanged()
+	waitFor(t, "catalog refreshed after tools/list_changed", func() bool {
+		return slices.Equal(toolNames(m), []string{"a__read", "a__write"})
+	})
+
+	// A failed background refresh is reported and keeps the previous entries
+	s.mu.Lock()
+	s.listErr = errors.New("unavailable")
+	s.mu.Unlock()
+	listChanged()
+	select {
+	case err := <-failed:
+		if !strings.Contains(err.Error(), "unavailable") {
+			t.Errorf("refresh error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed refresh not reported")
+	}
+	if got := toolNames(m); !slices.Equal(got, []string{"a__read", "a__write"}) {
+		t.Errorf("Tools() after a failed refresh = %v", got)
+	}
+}
+
+// waitFor polls cond until it holds, failing the test after 5 seconds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting: %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
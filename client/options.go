@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ClientOption configures an MCPClient implementation or its transport
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	interceptors             []Interceptor
+	notificationInterceptors []NotificationInterceptor
+	recording                io.Writer
+
+	// Child process settings, used by the stdio transport
+	env           []string
+	dir           string
+	stderr        io.Writer
+	stderrHandler func(line string)
+	commandCtx    context.Context
+	closeTimeout  time.Duration
+
+	// Supervision settings, used by SupervisedClient
+	restartPolicy *RestartPolicy
+	exitHandler   func(err error)
+	// route replaces the innermost invoker of a client, which sends a request
+	// over the client's own transport
+	route func(c *Client, ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error)
+
+	// HTTP settings, used by the SSE and streamable HTTP transports
+	httpClient *http.Client
+	headers    http.Header
+}
+
+func newClientOptions(opts []ClientOption) *clientOptions {
+	o := &clientOptions{
+		closeTimeout: DefaultCloseTimeout,
+		httpClient:   http.DefaultClient,
+		headers:      make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithInterceptors appends interceptors for outbound requests. The first
+// interceptor is the outermost one.
+func WithInterceptors(interceptors ...Interceptor) ClientOption {
+	return func(o *clientOptions) {
+		o.interceptors = append(o.interceptors, interceptors...)
+	}
+}
+
+// WithNotificationInterceptors appends interceptors for inbound notifications.
+// The first interceptor is the outermost one.
+func WithNotificationInterceptors(interceptors ...NotificationInterceptor) ClientOption {
+	return func(o *clientOptions) {
+		o.notificationInterceptors = append(o.notificationInterceptors, interceptors...)
+	}
+}
+
+// WithEnv adds "KEY=value" entries to the environment of a server started as a
+// child process, on top of the environment of the current process
+func WithEnv(env ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.env = append(o.env, env...)
+	}
+}
+
+// WithDir sets the working directory of a server started as a child process
+func WithDir(dir string) ClientOption {
+	return func(o *clientOptions) {
+		o.dir = dir
+	}
+}
+
+// WithStderr copies the stderr output of a server started as a child process to
+// w. By default it is discarded.
+func WithStderr(w io.Writer) ClientOption {
+	return func(o *clientOptions) {
+		o.stderr = w
+	}
+}
+
+// WithStderrHandler calls fn with every line a server started as a child
+// process writes to stderr, without the trailing newline. fn is called from a
+// single goroutine.
+func WithStderrHandler(fn func(line string)) ClientOption {
+	return func(o *clientOptions) {
+		o.stderrHandler = fn
+	}
+}
+
+// WithCommandContext kills a server started as a child process, along with
+// its process group, when ctx is done
+func WithCommandContext(ctx context.Context) ClientOption {
+	return func(o *clientOptions) {
+		o.commandCtx = ctx
+	}
+}
+
+// WithCloseTimeout sets how long Close waits for a server started as a child
+// process to exit at each step: after closing its stdin, and after asking it to
+// terminate, before killing it
+func WithCloseTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.closeTimeout = timeout
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to reach servers over HTTP
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithHeader adds a header sent with every HTTP request to the server, such as
+// an Authorization header
+func WithHeader(key, value string) ClientOption {
+	return func(o *clientOptions) {
+		o.headers.Add(key, value)
+	}
+}
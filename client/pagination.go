@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// AllTools returns an iterator over every tool offered by the server, following
+// pagination cursors transparently. Iteration stops after the first error,
+// which is yielded together with a zero Tool.
+func AllTools(ctx context.Context, c MCPClient) iter.Seq2[mcp.Tool, error] {
+	return paginate(ctx, func(ctx context.Context, cursor *string) ([]mcp.Tool, *string, error) {
+		result, err := c.ListTools(ctx, cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Tools, result.NextCursor, nil
+	})
+}
+
+// AllResources returns an iterator over every resource offered by the server,
+// following pagination cursors transparently. Iteration stops after the first
+// error, which is yielded together with a zero Resource.
+func AllResources(ctx context.Context, c MCPClient) iter.Seq2[mcp.Resource, error] {
+	return paginate(ctx, func(ctx context.Context, cursor *string) ([]mcp.Resource, *string, error) {
+		result, err := c.ListResources(ctx, cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Resources, result.NextCursor, nil
+	})
+}
+
+// AllPrompts returns an iterator over every prompt offered by the server,
+// following pagination cursors transparently. Iteration stops after the first
+// error, which is yielded together with a zero Prompt.
+func AllPrompts(ctx context.Context, c MCPClient) iter.Seq2[mcp.Prompt, error] {
+	return paginate(ctx, func(ctx context.Context, cursor *string) ([]mcp.Prompt, *string, error) {
+		result, err := c.ListPrompts(ctx, cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Prompts, result.NextCursor, nil
+	})
+}
+
+func paginate[T any](
+	ctx context.Context,
+	list func(ctx context.Context, cursor *string) ([]T, *string, error),
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		var cursor *string
+		for {
+			items, next, err := list(ctx, cursor)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if next == nil || *next == "" {
+				return
+			}
+			if cursor != nil && *next == *cursor {
+				yield(zero, fmt.Errorf("server returned the same cursor twice: %q", *next))
+				return
+			}
+			cursor = next
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// pagedClient is an MCPClient listing its tools two per page. Only ListTools
+// is implemented.
+type pagedClient struct {
+	client.MCPClient
+	tools []mcp.Tool
+	// fail fails every page but the first when set
+	fail error
+	// stuck always returns the same cursor when set
+	stuck bool
+	calls int
+}
+
+func (c *pagedClient) ListTools(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error) {
+	c.calls++
+	start := 0
+	if cursor != nil {
+		if c.fail != nil {
+			return nil, c.fail
+		}
+		start, _ = strconv.Atoi(*cursor)
+	}
+	result := &mcp.ListToolsResult{}
+	if c.stuck {
+		next := "same"
+		result.Tools = []mcp.Tool{{Name: "a"}}
+		result.NextCursor = &next
+		return result, nil
+	}
+	end := min(start+2, len(c.tools))
+	result.Tools = c.tools[start:end]
+	if end < len(c.tools) {
+		next := strconv.Itoa(end)
+		result.NextCursor = &next
+	}
+	return result, nil
+}
+
+func newPagedClient() *pagedClient {
+	c := &pagedClient{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		c.tools = append(c.tools, mcp.Tool{Name: name})
+	}
+	return c
+}
+
+func TestAllTools(t *testing.T) {
+	c := newPagedClient()
+
+	var names []string
+	for tool, err := range client.AllTools(context.Background(), c) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, tool.Name)
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; !slices.Equal(names, want) {
+		t.Errorf("tools = %v, want %v", names, want)
+	}
+	if c.calls != 3 {
+		t.Errorf("listed %d pages, want 3", c.calls)
+	}
+}
+
+func TestAllToolsStopsEarly(t *testing.T) {
+	c := newPagedClient()
+
+	for tool, err := range client.AllTools(context.Background(), c) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tool.Name == "a" {
+			break
+		}
+	}
+	if c.calls != 1 {
+		t.Errorf("listed %d pages, want 1", c.calls)
+	}
+}
+
+func TestAllToolsError(t *testing.T) {
+	failed := errors.New("invalid cursor")
+	c := newPagedClient()
+	c.fail = failed
+
+	var names []string
+	var errs []error
+	for tool, err := range client.AllTools(context.Background(), c) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names = append(names, tool.Name)
+	}
+	if !slices.Equal(names, []string{"a", "b"}) {
+		t.Errorf("tools = %v, want the first page", names)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], failed) {
+		t.Errorf("errors = %v, want a single %v", errs, failed)
+	}
+}
+
+func TestAllToolsRepeatedCursor(t *testing.T) {
+	c := &pagedClient{stuck: true}
+
+	var err error
+	count := 0
+	for _, e := range client.AllTools(context.Background(), c) {
+		if e != nil {
+			err = e
+			break
+		}
+		count++
+	}
+	if err == nil || !strings.Contains(err.Error(), "same cursor") {
+		t.Errorf("error = %v, want a repeated cursor error", err)
+	}
+	if count != 2 {
+		t.Errorf("yielded %d tools before the error, want 2", count)
+	}
+}
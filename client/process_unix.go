@@ -0,0 +1,41 @@
+//go:build !windows
+
+package client
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup makes the child the leader of a new process group
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// terminateProcessGroup asks every process in the child's group to exit
+func terminateProcessGroup(cmd *exec.Cmd) {
+	signalProcessGroup(cmd, syscall.SIGTERM)
+}
+
+// killProcessGroup kills every process in the child's group
+func killProcessGroup(cmd *exec.Cmd) {
+	signalProcessGroup(cmd, syscall.SIGKILL)
+}
+
+func signalProcessGroup(cmd *exec.Cmd, sig syscall.Signal) {
+	if cmd.Process == nil {
+		return
+	}
+	// The group ID equals the leader's PID; a negative PID signals the group
+	syscall.Kill(-cmd.Process.Pid, sig)
+}
+
+// killOrphans kills the processes left in the child's group after it exited.
+// The group outlives its leader as long as it has members, so its ID cannot
+// have been reused.
+func killOrphans(cmd *exec.Cmd) {
+	signalProcessGroup(cmd, syscall.SIGKILL)
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package client
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup makes the child the root of a new process group
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// terminateProcessGroup asks the child and its descendants to exit. Console
+// programs cannot be signalled gracefully, so this kills them like
+// killProcessGroup.
+func terminateProcessGroup(cmd *exec.Cmd) {
+	killProcessGroup(cmd)
+}
+
+// killProcessGroup kills the child and its descendants
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	if kill.Run() != nil {
+		cmd.Process.Kill()
+	}
+}
+
+// killOrphans does nothing: once the child has exited, its PID may have been
+// reused, so its former descendants cannot be found safely
+func killOrphans(cmd *exec.Cmd) {}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Direction tells whether a recorded message was sent to or received from the
+// server
+type Direction string
+
+const (
+	DirectionSend    Direction = "send"
+	DirectionReceive Direction = "receive"
+)
+
+// RecordedMessage is a single entry of a recording, stored as one line of JSON
+type RecordedMessage struct {
+	Time      time.Time       `json:"time"`
+	Direction Direction       `json:"direction"`
+	Message   json.RawMessage `json:"message"`
+}
+
+// WithRecording records every message the client exchanges with the server to
+// w, as described by RecordingTransport
+func WithRecording(w io.Writer) ClientOption {
+	return func(o *clientOptions) {
+		o.recording = w
+	}
+}
+
+// RecordingTransport is a Transport recording the messages exchanged over
+// another transport. Every message is written to a writer as a RecordedMessage
+// on its own line, forming a JSONL file that can be loaded with ReadRecording
+// and played back with NewReplayTransport or Verify.
+type RecordingTransport struct {
+	transport Transport
+
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// NewRecordingTransport returns a transport recording the messages exchanged
+// over transport to w. Failing to write to w does not affect the traffic; the
+// first write error is reported by Err.
+func NewRecordingTransport(transport Transport, w io.Writer) *RecordingTransport {
+	return &RecordingTransport{transport: transport, w: w}
+}
+
+// Send records the message before sending it, so that it is never recorded
+// after the response to it
+func (t *RecordingTransport) Send(ctx context.Context, message json.RawMessage) error {
+	t.record(DirectionSend, message)
+	return t.transport.Send(ctx, message)
+}
+
+func (t *RecordingTransport) Receive() (json.RawMessage, error) {
+	message, err := t.transport.Receive()
+	if err != nil {
+		return nil, err
+	}
+	t.record(DirectionReceive, message)
+	return message, nil
+}
+
+func (t *RecordingTransport) Close() error {
+	return t.transport.Close()
+}
+
+// Err returns the first error writing the recording, if any
+func (t *RecordingTransport) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *RecordingTransport) record(direction Direction, message json.RawMessage) {
+	// The message is compacted so that the recording stays one entry per line
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, message); err != nil {
+		compact.Reset()
+		quoted, _ := json.Marshal(string(message))
+		compact.Write(quoted)
+	}
+	line, err := json.Marshal(RecordedMessage{
+		Time:      time.Now().UTC(),
+		Direction: direction,
+		Message:   compact.Bytes(),
+	})
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return
+	}
+	if _, err := t.w.Write(line); err != nil {
+		t.err = fmt.Errorf("failed to write recording: %w", err)
+	}
+}
+
+// ReadRecording reads a recording written by a RecordingTransport
+func ReadRecording(r io.Reader) ([]RecordedMessage, error) {
+	var messages []RecordedMessage
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var message RecordedMessage
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			return nil, fmt.Errorf("failed to parse recording line %d: %w", line, err)
+		}
+		if message.Direction != DirectionSend && message.Direction != DirectionReceive {
+			return nil, fmt.Errorf("failed to parse recording line %d: unknown direction %q", line, message.Direction)
+		}
+		messages = append(messages, message)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return messages, nil
+}
+
+// LoadRecording reads the recording stored in the file at path
+func LoadRecording(path string) ([]RecordedMessage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+	return ReadRecording(f)
+}
@@ -0,0 +1,251 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server"
+	"github.com/WePrompt/gomcp/server/handlers"
+)
+
+// serverTransport is a Transport to an MCPServer in the same process, answering
+// each request as it is sent
+type serverTransport struct {
+	server    *server.MCPServer
+	responses chan json.RawMessage
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newServerTransport(s *server.MCPServer) *serverTransport {
+	return &serverTransport{server: s, responses: make(chan json.RawMessage, 16), closed: make(chan struct{})}
+}
+
+func (t *serverTransport) Send(ctx context.Context, message json.RawMessage) error {
+	var request struct {
+		Id     *mcp.RequestID  `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil {
+		return err
+	}
+	result, err := t.server.Request(ctx, request.Method, request.Params)
+	if request.Id == nil {
+		return nil
+	}
+	response := mcp.JSONRPCResponse{Jsonrpc: mcp.JSONRPCVersion, Id: *request.Id, Result: result}
+	var rpcErr *mcp.JSONRPCErrorData
+	switch {
+	case errors.As(err, &rpcErr):
+		response.Result = nil
+		response.Error = rpcErr
+	case err != nil:
+		response.Result = nil
+		response.Error = &mcp.JSONRPCErrorData{Code: mcp.ErrorCodeInternalError, Message: err.Error()}
+	case result == nil:
+		response.Result = json.RawMessage("{}")
+	}
+	b, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	t.responses <- b
+	return nil
+}
+
+func (t *serverTransport) Receive() (json.RawMessage, error) {
+	select {
+	case message := <-t.responses:
+		return message, nil
+	case <-t.closed:
+		return nil, io.EOF
+	}
+}
+
+func (t *serverTransport) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+	return nil
+}
+
+// newEchoServer returns a server whose echo tool answers with reply, or with
+// the text it is given if reply is empty
+func newEchoServer(reply string) *server.MCPServer {
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		text := reply
+		if text == "" {
+			text = fmt.Sprint(arguments["text"])
+		}
+		result := &mcp.CallToolResult{}
+		result.AddTextContent(mcp.NewTextContent(text))
+		return result, nil
+	})
+	return server.NewMCPServer(server.WithToolHandler(tools))
+}
+
+// record runs a short session against an echo server and returns its recording
+func record(t *testing.T) []client.RecordedMessage {
+	t.Helper()
+	var buf bytes.Buffer
+	c := client.NewClient(newServerTransport(newEchoServer("")), client.WithRecording(&buf))
+	defer c.Close()
+	if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListTools(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CallTool(context.Background(), "echo", map[string]interface{}{"text": "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	recording, err := client.ReadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return recording
+}
+
+func TestRecording(t *testing.T) {
+	recording := record(t)
+
+	// initialize, notifications/initialized, tools/list and tools/call, with
+	// the responses to the three requests
+	var directions []string
+	for _, entry := range recording {
+		directions = append(directions, string(entry.Direction))
+	}
+	want := "send,receive,send,send,receive,send,receive"
+	if got := strings.Join(directions, ","); got != want {
+		t.Fatalf("directions = %s, want %s", got, want)
+	}
+	if !bytes.Contains(recording[5].Message, []byte(`"method":"tools/call"`)) {
+		t.Errorf("message 5 = %s, want the tool call", recording[5].Message)
+	}
+	for _, entry := range recording {
+		if entry.Time.IsZero() {
+			t.Errorf("message %s has no time", entry.Message)
+		}
+	}
+}
+
+func TestReadRecordingErrors(t *testing.T) {
+	for _, input := range []string{
+		`{"direction":"send","message":{}}` + "\n" + `not json`,
+		`{"direction":"sideways","message":{}}`,
+	} {
+		if _, err := client.ReadRecording(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), "line") {
+			t.Errorf("ReadRecording(%q) error = %v, want the failing line", input, err)
+		}
+	}
+	recording, err := client.ReadRecording(strings.NewReader("\n" + `{"direction":"send","message":{}}` + "\n\n"))
+	if err != nil || len(recording) != 1 {
+		t.Errorf("ReadRecording skipping blank lines = %v, %v", recording, err)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	recording := record(t)
+	transport := client.NewReplayTransport(recording)
+	c := client.NewClient(transport)
+	defer c.Close()
+
+	if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	tools, err := c.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "echo" {
+		t.Errorf("tools = %+v", tools.Tools)
+	}
+	result, err := c.CallTool(context.Background(), "echo", map[string]interface{}{"text": "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Content) != 1 {
+		t.Errorf("content = %+v", result.Content)
+	}
+	if !transport.Done() {
+		t.Error("recording not played back entirely")
+	}
+
+	// Past the end of the recording, every message is a mismatch
+	var mismatch *client.ReplayMismatchError
+	if err := c.Ping(context.Background()); !errors.As(err, &mismatch) || mismatch.Expected != nil || mismatch.Index != len(recording) {
+		t.Errorf("Ping error = %v, want a mismatch after the end of the recording", err)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	recording := record(t)
+	c := client.NewClient(client.NewReplayTransport(recording))
+	defer c.Close()
+	if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// The recording lists tools before calling one
+	_, err := c.CallTool(context.Background(), "echo", map[string]interface{}{"text": "hi"})
+	var mismatch *client.ReplayMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("CallTool error = %v, want a mismatch", err)
+	}
+	if mismatch.Index != 3 || !bytes.Contains(mismatch.Expected, []byte(`"tools/list"`)) || !bytes.Contains(mismatch.Actual, []byte(`"tools/call"`)) {
+		t.Errorf("mismatch = %v", mismatch)
+	}
+}
+
+func TestReplayMessageMatcher(t *testing.T) {
+	recording := record(t)
+	// Only the methods are compared
+	methods := func(recorded, actual json.RawMessage) bool {
+		var a, b struct {
+			Method string `json:"method"`
+		}
+		json.Unmarshal(recorded, &a)
+		json.Unmarshal(actual, &b)
+		return a.Method == b.Method
+	}
+	c := client.NewClient(client.NewReplayTransport(recording, client.WithMessageMatcher(methods)))
+	defer c.Close()
+
+	if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "other", Version: "2.0.0"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListTools(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CallTool(context.Background(), "echo", map[string]interface{}{"text": "other"}); err != nil {
+		t.Errorf("CallTool with other arguments: %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	recording := record(t)
+	if err := client.Verify(context.Background(), newServerTransport(newEchoServer("")), recording); err != nil {
+		t.Errorf("Verify against the same server: %v", err)
+	}
+
+	// A server whose tool answers differently no longer matches
+	err := client.Verify(context.Background(), newServerTransport(newEchoServer("changed")), recording)
+	var mismatch *client.ReplayMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Verify error = %v, want a mismatch", err)
+	}
+	if mismatch.Index != 6 || !bytes.Contains(mismatch.Actual, []byte("changed")) {
+		t.Errorf("mismatch = %v", mismatch)
+	}
+}
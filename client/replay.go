@@ -0,0 +1,317 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// ReplayOption configures NewReplayTransport and Verify
+type ReplayOption func(*replayOptions)
+
+type replayOptions struct {
+	match func(recorded, actual json.RawMessage) bool
+}
+
+func newReplayOptions(opts []ReplayOption) *replayOptions {
+	o := &replayOptions{match: matchIgnoringRequestID}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithMessageMatcher sets the function deciding whether a live message matches
+// the recorded one, for instance to ignore fields holding timestamps. By
+// default messages must be equal as JSON values, except for the IDs of
+// requests sent by the client.
+func WithMessageMatcher(fn func(recorded, actual json.RawMessage) bool) ReplayOption {
+	return func(o *replayOptions) {
+		o.match = fn
+	}
+}
+
+// ReplayMismatchError reports a live message that differs from the recording
+type ReplayMismatchError struct {
+	// Index is the position of the expected message in the recording, or its
+	// length if the recording holds no further message
+	Index    int
+	Expected json.RawMessage
+	Actual   json.RawMessage
+}
+
+func (e *ReplayMismatchError) Error() string {
+	if e.Expected == nil {
+		return fmt.Sprintf("unexpected message after the end of the recording: %s", e.Actual)
+	}
+	return fmt.Sprintf("message %d does not match the recording: expected %s, got %s", e.Index, e.Expected, e.Actual)
+}
+
+// ReplayTransport is a Transport playing back a recording as a fake server.
+// Every message sent must match the next message the client sent in the
+// recording; the messages the server sent after it, up to the next message of
+// the client, are then delivered to Receive. Responses are given the IDs of
+// the live requests, so a client numbering its requests differently still
+// gets its answers.
+//
+// Messages must be sent in the recorded order, one at a time: a client that
+// waits for a response before sending its next request cannot replay a
+// recording in which it did not.
+type ReplayTransport struct {
+	recording []RecordedMessage
+	options   *replayOptions
+
+	mu   sync.Mutex
+	next int
+	// ids maps the IDs of recorded requests to the IDs of live ones
+	ids map[mcp.RequestID]mcp.RequestID
+
+	messages  chan json.RawMessage
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewReplayTransport returns a transport playing back recording
+func NewReplayTransport(recording []RecordedMessage, opts ...ReplayOption) *ReplayTransport {
+	t := &ReplayTransport{
+		recording: recording,
+		options:   newReplayOptions(opts),
+		ids:       make(map[mcp.RequestID]mcp.RequestID),
+		messages:  make(chan json.RawMessage, len(recording)),
+		closed:    make(chan struct{}),
+	}
+	t.deliver()
+	return t
+}
+
+func (t *ReplayTransport) Send(ctx context.Context, message json.RawMessage) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.next >= len(t.recording) {
+		return &ReplayMismatchError{Index: t.next, Actual: message}
+	}
+	recorded := t.recording[t.next].Message
+	if !t.options.match(recorded, message) {
+		return &ReplayMismatchError{Index: t.next, Expected: recorded, Actual: message}
+	}
+
+	recordedID, isRequest := requestID(recorded)
+	if liveID, ok := requestID(message); isRequest && ok {
+		t.ids[recordedID] = liveID
+	}
+	t.next++
+	t.deliver()
+	return nil
+}
+
+// deliver queues the server messages up to the next client message
+func (t *ReplayTransport) deliver() {
+	for ; t.next < len(t.recording) && t.recording[t.next].Direction == DirectionReceive; t.next++ {
+		message := t.recording[t.next].Message
+		if id, ok := responseID(message); ok {
+			if liveID, ok := t.ids[id]; ok {
+				message = replaceID(message, liveID)
+			}
+		}
+		t.messages <- message
+	}
+}
+
+// Receive returns the next server message of the recording. Once the
+// recording is exhausted, it blocks until the transport is closed.
+func (t *ReplayTransport) Receive() (json.RawMessage, error) {
+	select {
+	case message := <-t.messages:
+		return message, nil
+	case <-t.closed:
+		return nil, io.EOF
+	}
+}
+
+func (t *ReplayTransport) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+	return nil
+}
+
+// Done reports whether every message of the recording was played back
+func (t *ReplayTransport) Done() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.next >= len(t.recording)
+}
+
+// Verify plays the client side of recording against a live server reached
+// through transport, and checks that the server answers every request as
+// recorded. Requests are sent in the recorded order with their recorded IDs,
+// each once the previous one was answered. Notifications and requests sent by
+// the server are not compared; requests from the server are answered with a
+// method-not-found error. The transport is closed when Verify returns.
+func Verify(ctx context.Context, transport Transport, recording []RecordedMessage, opts ...ReplayOption) error {
+	options := newReplayOptions(opts)
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		transport.Close()
+	}()
+
+	received := make(chan json.RawMessage)
+	receiveErr := make(chan error, 1)
+	go func() {
+		for {
+			message, err := transport.Receive()
+			if err != nil {
+				receiveErr <- err
+				return
+			}
+			select {
+			case received <- message:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for i, entry := range recording {
+		if entry.Direction != DirectionSend {
+			continue
+		}
+		id, isRequest := requestID(entry.Message)
+		if !isRequest && !isNotification(entry.Message) {
+			// Responses to recorded server requests have no live counterpart
+			continue
+		}
+		if err := transport.Send(ctx, entry.Message); err != nil {
+			return fmt.Errorf("failed to send message %d: %w", i, err)
+		}
+		if !isRequest {
+			continue
+		}
+
+		index, expected := findResponse(recording[i+1:], id)
+		if expected == nil {
+			// The recording ended before the server answered
+			continue
+		}
+		index += i + 1
+
+		for {
+			var message json.RawMessage
+			select {
+			case message = <-received:
+			case err := <-receiveErr:
+				return fmt.Errorf("failed to receive response to message %d: %w", i, err)
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			if serverID, ok := requestID(message); ok {
+				refuseRequest(ctx, transport, serverID, message)
+				continue
+			}
+			if responseID, ok := responseID(message); !ok || responseID != id {
+				continue
+			}
+			if !options.match(expected, message) {
+				return &ReplayMismatchError{Index: index, Expected: expected, Actual: message}
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func findResponse(recording []RecordedMessage, id mcp.RequestID) (int, json.RawMessage) {
+	for i, entry := range recording {
+		if entry.Direction != DirectionReceive {
+			continue
+		}
+		if responseID, ok := responseID(entry.Message); ok && responseID == id {
+			return i, entry.Message
+		}
+	}
+	return 0, nil
+}
+
+func refuseRequest(ctx context.Context, transport Transport, id mcp.RequestID, request json.RawMessage) {
+	var p struct {
+		Method string `json:"method"`
+	}
+	json.Unmarshal(request, &p)
+	response, err := json.Marshal(mcp.JSONRPCResponse{
+		Jsonrpc: mcp.JSONRPCVersion,
+		Id:      id,
+		Error:   mcp.NewError(mcp.ErrorCodeMethodNotFound, fmt.Sprintf("method not found: %s", p.Method)),
+	})
+	if err == nil {
+		transport.Send(ctx, response)
+	}
+}
+
+type envelope struct {
+	Id     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+func parseEnvelope(message json.RawMessage) (envelope, mcp.RequestID, bool) {
+	var e envelope
+	var id mcp.RequestID
+	if json.Unmarshal(message, &e) != nil {
+		return e, id, false
+	}
+	hasID := len(e.Id) > 0 && string(e.Id) != "null" && json.Unmarshal(e.Id, &id) == nil
+	return e, id, hasID
+}
+
+// requestID returns the ID of a request
+func requestID(message json.RawMessage) (mcp.RequestID, bool) {
+	e, id, ok := parseEnvelope(message)
+	return id, ok && e.Method != ""
+}
+
+// responseID returns the ID of a response
+func responseID(message json.RawMessage) (mcp.RequestID, bool) {
+	e, id, ok := parseEnvelope(message)
+	return id, ok && e.Method == ""
+}
+
+func isNotification(message json.RawMessage) bool {
+	e, _, hasID := parseEnvelope(message)
+	return e.Method != "" && !hasID
+}
+
+func replaceID(message json.RawMessage, id mcp.RequestID) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(message, &fields) != nil {
+		return message
+	}
+	idBytes, err := json.Marshal(id)
+	if err != nil {
+		return message
+	}
+	fields["id"] = idBytes
+	replaced, err := json.Marshal(fields)
+	if err != nil {
+		return message
+	}
+	return replaced
+}
+
+// matchIgnoringRequestID compares messages as JSON values, ignoring the IDs of
+// requests
+func matchIgnoringRequestID(recorded, actual json.RawMessage) bool {
+	var a, b map[string]interface{}
+	if json.Unmarshal(recorded, &a) != nil || json.Unmarshal(actual, &b) != nil {
+		return false
+	}
+	if _, ok := a["method"]; ok {
+		delete(a, "id")
+		delete(b, "id")
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// RetryPolicy controls how RetryInterceptor retries failed requests
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a request is sent, including the
+	// first one. Values below 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. Each further retry
+	// waits Multiplier times longer, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// Jitter is the fraction, between 0 and 1, by which each delay is randomly
+	// shortened, so that clients failing together do not retry in lockstep
+	Jitter float64
+
+	// Retryable reports whether a failed request may succeed if sent again. It
+	// defaults to IsTransient.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy makes up to 3 attempts, waiting about 200ms and then
+// about 400ms between them
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 0; i < retry; i++ {
+		delay *= max(p.Multiplier, 1)
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			delay = float64(p.MaxBackoff)
+			break
+		}
+	}
+	if p.Jitter > 0 {
+		delay -= delay * min(p.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// IsTransient reports whether err is a failure to reach the server, such as a
+// dropped connection or an exited server, rather than an error returned by the
+// server, a cancellation by the caller or an open circuit
+func IsTransient(err error) bool {
+	var rpcErr *mcp.JSONRPCErrorData
+	switch {
+	case err == nil,
+		errors.As(err, &rpcErr),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, ErrConnectionClosed),
+		errors.Is(err, ErrSessionExpired),
+		errors.Is(err, ErrCircuitOpen):
+		return false
+	}
+	return true
+}
+
+// idempotentMethods may be sent again without changing the outcome
+var idempotentMethods = map[string]bool{
+	mcp.MethodPing:          true,
+	mcp.MethodResourcesList: true,
+	mcp.MethodResourcesRead: true,
+	mcp.MethodPromptsList:   true,
+	mcp.MethodPromptsGet:    true,
+	mcp.MethodToolsList:     true,
+}
+
+// RetryInterceptor returns an interceptor retrying failed requests according to
+// policy. Only idempotent requests are retried: ping, the list methods,
+// resources/read and prompts/get, as well as calls to tools annotated as
+// read-only or idempotent. Tool annotations are learned from the tools/list
+// results passing through the interceptor, so each client should be given its
+// own RetryInterceptor.
+//
+// When combined with a CircuitBreaker, the retry interceptor should come first,
+// so that every attempt is checked against the breaker.
+func RetryInterceptor(policy RetryPolicy) Interceptor {
+	if policy.Retryable == nil {
+		policy.Retryable = IsTransient
+	}
+	var (
+		mu         sync.RWMutex
+		idempotent = make(map[string]bool)
+	)
+
+	retryable := func(method string, params json.RawMessage) bool {
+		if method != mcp.MethodToolsCall {
+			return idempotentMethods[method]
+		}
+		var p struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(params, &p) != nil {
+			return false
+		}
+		mu.RLock()
+		defer mu.RUnlock()
+		return idempotent[p.Name]
+	}
+
+	return func(ctx context.Context, method string, params json.RawMessage, invoker Invoker) (json.RawMessage, error) {
+		canRetry := retryable(method, params)
+		for attempt := 1; ; attempt++ {
+			result, err := invoker(ctx, method, params)
+			if err == nil {
+				if method == mcp.MethodToolsList {
+					var list mcp.ListToolsResult
+					if json.Unmarshal(result, &list) == nil {
+						mu.Lock()
+						for _, tool := range list.Tools {
+							idempotent[tool.Name] = tool.Annotations.IsIdempotent()
+						}
+						mu.Unlock()
+					}
+				}
+				return result, nil
+			}
+			if !canRetry || attempt >= policy.MaxAttempts || !policy.Retryable(err) {
+				return nil, err
+			}
+
+			timer := time.NewTimer(policy.backoff(attempt - 1))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, err
+			}
+		}
+	}
+}
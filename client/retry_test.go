@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	Multiplier:     2,
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	intercept := RetryInterceptor(testRetryPolicy)
+
+	server := &scriptedInvoker{errs: []error{errUnreachable, errUnreachable}}
+	if _, err := intercept(context.Background(), mcp.MethodToolsList, nil, server.invoke); err != nil {
+		t.Errorf("ListTools: %v", err)
+	}
+	if n := server.count(); n != 3 {
+		t.Errorf("attempts = %d, want 3", n)
+	}
+
+	// Attempts are bounded by MaxAttempts
+	server = &scriptedInvoker{errs: []error{errUnreachable, errUnreachable, errUnreachable, errUnreachable}}
+	if _, err := intercept(context.Background(), mcp.MethodToolsList, nil, server.invoke); !errors.Is(err, errUnreachable) {
+		t.Errorf("error = %v, want the last failure", err)
+	}
+	if n := server.count(); n != 3 {
+		t.Errorf("attempts = %d, want 3", n)
+	}
+}
+
+func TestRetryOnlyRetryableErrors(t *testing.T) {
+	intercept := RetryInterceptor(testRetryPolicy)
+	for _, err := range []error{
+		mcp.NewError(mcp.ErrorCodeInternalError, "internal"),
+		context.DeadlineExceeded,
+		ErrConnectionClosed,
+		ErrCircuitOpen,
+	} {
+		server := &scriptedInvoker{errs: []error{err}}
+		intercept(context.Background(), mcp.MethodPing, nil, server.invoke)
+		if n := server.count(); n != 1 {
+			t.Errorf("%v: attempts = %d, want 1", err, n)
+		}
+	}
+}
+
+func TestRetryIdempotentToolCalls(t *testing.T) {
+	intercept := RetryInterceptor(testRetryPolicy)
+	call := func(name string) int {
+		params, _ := json.Marshal(map[string]string{"name": name})
+		server := &scriptedInvoker{errs: []error{errUnreachable}}
+		intercept(context.Background(), mcp.MethodToolsCall, params, server.invoke)
+		return server.count()
+	}
+
+	// Tools are not retried until tools/list told they are idempotent
+	if n := call("read"); n != 1 {
+		t.Errorf("attempts before tools/list = %d, want 1", n)
+	}
+
+	yes := true
+	list, _ := json.Marshal(mcp.ListToolsResult{Tools: []mcp.Tool{
+		{Name: "read", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: &yes}},
+		{Name: "write"},
+	}})
+	listed := func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+		return list, nil
+	}
+	if _, err := intercept(context.Background(), mcp.MethodToolsList, nil, listed); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := call("read"); n != 2 {
+		t.Errorf("attempts for a read-only tool = %d, want 2", n)
+	}
+	if n := call("write"); n != 1 {
+		t.Errorf("attempts for a non-idempotent tool = %d, want 1", n)
+	}
+}
+
+func TestRetryCancelledDuringBackoff(t *testing.T) {
+	intercept := RetryInterceptor(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	server := &scriptedInvoker{errs: []error{errUnreachable, errUnreachable}}
+	start := time.Now()
+	if _, err := intercept(ctx, mcp.MethodPing, nil, server.invoke); !errors.Is(err, errUnreachable) {
+		t.Errorf("error = %v, want the failure of the last attempt", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("returned after %v", elapsed)
+	}
+	if n := server.count(); n != 1 {
+		t.Errorf("attempts = %d, want 1", n)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2}
+	for retry, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		if got := policy.backoff(retry); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", retry, got, want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(0); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("backoff with jitter = %v, want between 50ms and 100ms", got)
+		}
+	}
+}
+
+func TestRetryWithBreaker(t *testing.T) {
+	// Every attempt of a retried request counts against the breaker
+	breaker, _ := newTestBreaker(time.Minute)
+	retry := RetryInterceptor(testRetryPolicy)
+	server := &scriptedInvoker{errs: []error{errUnreachable, errUnreachable, errUnreachable}}
+	invoke := chainInterceptors([]Interceptor{retry, breaker.Interceptor()}, server.invoke)
+
+	if _, err := invoke(context.Background(), mcp.MethodPing, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("error = %v, want %v once the breaker opened", err, ErrCircuitOpen)
+	}
+	if n := server.count(); n != 2 {
+		t.Errorf("attempts reaching the server = %d, want 2", n)
+	}
+}
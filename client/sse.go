@@ -0,0 +1,228 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// maxEventSize bounds the size of a single server-sent event
+const maxEventSize = 16 << 20
+
+// sseEvent is a single event of a text/event-stream
+type sseEvent struct {
+	event string
+	data  string
+	id    string
+}
+
+// readEvents calls fn for every event read from r until r ends or fn returns
+// false
+func readEvents(r io.Reader, fn func(sseEvent) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxEventSize)
+
+	var (
+		event sseEvent
+		data  []string
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				event.data = strings.Join(data, "\n")
+				if !fn(event) {
+					return nil
+				}
+			}
+			event, data = sseEvent{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			event.id = value
+		}
+	}
+	return scanner.Err()
+}
+
+// NewSSEMCPClient returns a client talking to a server over the HTTP with SSE
+// transport of protocol revision 2024-11-05
+func NewSSEMCPClient(url string, opts ...ClientOption) (*Client, error) {
+	transport, err := NewSSETransport(url, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(transport, opts...), nil
+}
+
+// SSETransport is a Transport using the HTTP with SSE transport of protocol
+// revision 2024-11-05: messages from the server arrive as events on a
+// long-lived GET stream, which first announces the endpoint messages to the
+// server must be POSTed to.
+type SSETransport struct {
+	httpClient *http.Client
+	headers    http.Header
+
+	endpoint      *url.URL
+	endpointReady chan struct{}
+
+	messages chan json.RawMessage
+	done     chan struct{}
+	err      error
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+// NewSSETransport opens the event stream at rawURL, configured by the HTTP
+// options among opts
+func NewSSETransport(rawURL string, opts ...ClientOption) (*SSETransport, error) {
+	options := newClientOptions(opts)
+	base, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base.String(), nil)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	copyHeaders(req.Header, options.headers)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := options.httpClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("failed to connect: server returned %s", resp.Status)
+	}
+
+	t := &SSETransport{
+		httpClient:    options.httpClient,
+		headers:       options.headers,
+		endpointReady: make(chan struct{}),
+		messages:      make(chan json.RawMessage, 16),
+		done:          make(chan struct{}),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+	go t.readStream(base, resp.Body)
+	return t, nil
+}
+
+func (t *SSETransport) readStream(base *url.URL, body io.ReadCloser) {
+	defer close(t.done)
+	defer body.Close()
+
+	err := readEvents(body, func(event sseEvent) bool {
+		switch event.event {
+		case "endpoint":
+			if t.endpoint != nil {
+				return true
+			}
+			endpoint, err := base.Parse(event.data)
+			if err != nil {
+				t.err = fmt.Errorf("invalid endpoint %q: %w", event.data, err)
+				return false
+			}
+			t.endpoint = endpoint
+			close(t.endpointReady)
+		case "", "message":
+			select {
+			case t.messages <- json.RawMessage(event.data):
+			case <-t.ctx.Done():
+				return false
+			}
+		}
+		return true
+	})
+	if t.err == nil {
+		t.err = err
+	}
+	if t.err == nil || t.ctx.Err() != nil {
+		t.err = io.EOF
+	}
+}
+
+func (t *SSETransport) Send(ctx context.Context, message json.RawMessage) error {
+	select {
+	case <-t.endpointReady:
+	case <-t.done:
+		return ErrConnectionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return post(ctx, t.httpClient, t.endpoint.String(), t.headers, message)
+}
+
+func (t *SSETransport) Receive() (json.RawMessage, error) {
+	select {
+	case message := <-t.messages:
+		return message, nil
+	case <-t.done:
+		select {
+		case message := <-t.messages:
+			return message, nil
+		default:
+			return nil, t.err
+		}
+	}
+}
+
+// Close closes the event stream
+func (t *SSETransport) Close() error {
+	t.closeOnce.Do(t.cancel)
+	return nil
+}
+
+// post sends a message that the server acknowledges without a body
+func post(ctx context.Context, httpClient *http.Client, url string, headers http.Header, message json.RawMessage) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(message)))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	copyHeaders(req.Header, headers)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return nil
+}
+
+func copyHeaders(dst, src http.Header) {
+	for key, values := range src {
+		for _, value := range values {
+			dst.Add(key, value)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server"
+)
+
+func TestReadEvents(t *testing.T) {
+	input := ": comment\n" +
+		"event: endpoint\ndata: /messages\n\n" +
+		"id: 7\ndata: first line\ndata:second line\n\n" +
+		"\n" +
+		"event: message\ndata: {}\n\n" +
+		"data: incomplete"
+	var events []sseEvent
+	err := readEvents(strings.NewReader(input), func(event sseEvent) bool {
+		events = append(events, event)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sseEvent{
+		{event: "endpoint", data: "/messages"},
+		{id: "7", data: "first line\nsecond line"},
+		{event: "message", data: "{}"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v, want %+v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+
+	count := 0
+	readEvents(strings.NewReader(input), func(event sseEvent) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("read %d events after fn returned false, want 1", count)
+	}
+}
+
+// sseServer serves an MCPServer over the HTTP with SSE transport, with a single
+// session
+type sseServer struct {
+	mcp      *server.MCPServer
+	session  *server.Session
+	messages chan json.RawMessage
+
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+func newSSEServer(t *testing.T) (*sseServer, *httptest.Server) {
+	s := &sseServer{
+		mcp:      newHTTPTestServer(),
+		session:  server.NewSession("sse"),
+		messages: make(chan json.RawMessage, 16),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /mcp/sse", s.stream)
+	mux.HandleFunc("POST /mcp/messages", s.post)
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func (s *sseServer) stream(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	// The endpoint is relative to the stream's URL
+	fmt.Fprint(w, "event: endpoint\ndata: messages?session=sse\n\n")
+	w.(http.Flusher).Flush()
+	for {
+		select {
+		case message := <-s.messages:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", message)
+			w.(http.Flusher).Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *sseServer) post(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.headers = append(s.headers, r.Header.Clone())
+	s.mu.Unlock()
+	if r.URL.Query().Get("session") != "sse" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	response := handleMessage(server.ContextWithSession(r.Context(), s.session), s.mcp, body)
+	if response != nil {
+		s.messages <- response
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestSSE(t *testing.T) {
+	s, ts := newSSEServer(t)
+	notified := make(chan struct{}, 1)
+	c, err := NewSSEMCPClient(ts.URL+"/mcp/sse",
+		WithHeader("Authorization", "Bearer token"),
+		WithNotificationInterceptors(func(ctx context.Context, method string, params json.RawMessage, next NotificationHandlerFunc) {
+			if method == mcp.MethodNotificationToolsListChanged {
+				notified <- struct{}{}
+			}
+			next(ctx, method, params)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	initializeClient(t, c)
+
+	result, err := c.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(result.Tools) != 1 || result.Tools[0].Name != "echo" {
+		t.Errorf("tools = %+v", result.Tools)
+	}
+
+	// Messages sent on the server's initiative arrive on the event stream
+	s.messages <- json.RawMessage(`{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`)
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		t.Error("server notification not received")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, header := range s.headers {
+		if header.Get("Authorization") != "Bearer token" {
+			t.Errorf("request %d: Authorization = %q", i, header.Get("Authorization"))
+		}
+	}
+}
+
+func TestSSEConnectError(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	if _, err := NewSSETransport(ts.URL); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("error = %v, want the server's status", err)
+	}
+}
@@ -2,392 +2,279 @@ package client
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"strings"
 	"sync"
 	"sync/atomic"
-
-	"github.com/WePrompt/gomcp/mcp"
+	"time"
 )
 
 var _ MCPClient = &StdioMCPClient{}
 
+// StdioMCPClient is a Client talking to a server started as a child process,
+// exchanging newline-delimited messages over its stdin and stdout
 type StdioMCPClient struct {
-	cmd         *exec.Cmd
-	stdin       io.WriteCloser
-	stdout      *bufio.Reader
-	requestID   atomic.Int64
-	responses   sync.Map
-	done        chan struct{}
-	initialized bool
+	*Client
 }
 
 func NewStdioMCPClient(
 	command string,
 	args ...string,
 ) (*StdioMCPClient, error) {
+	return NewStdioMCPClientWithOptions(command, args)
+}
+
+// NewStdioMCPClientWithOptions starts command as a child process and returns a
+// client talking to it over stdio, configured by opts.
+func NewStdioMCPClientWithOptions(
+	command string,
+	args []string,
+	opts ...ClientOption,
+) (*StdioMCPClient, error) {
+	transport, err := NewStdioTransport(command, args, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &StdioMCPClient{Client: NewClient(transport, opts...)}, nil
+}
+
+// DefaultCloseTimeout is how long Close waits for a child process to exit at
+// each step before escalating
+const DefaultCloseTimeout = 5 * time.Second
+
+// StdioTransport is a Transport to a server running as a child process. The
+// child runs in its own process group, so that Close can also stop the
+// processes it spawned.
+type StdioTransport struct {
+	cmd          *exec.Cmd
+	stdin        io.WriteCloser
+	stdout       *bufio.Reader
+	stdoutPipe   *os.File
+	closeTimeout time.Duration
+	stopContext  func() bool
+
+	// exited is closed once the child has exited, after which waitErr holds
+	// the result of waiting for it
+	exited  chan struct{}
+	waitErr error
+
+	// stderrDone is closed once all of the child's stderr has been copied
+	stderrDone chan struct{}
+
+	writeMu   sync.Mutex
+	closing   atomic.Bool
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewStdioTransport starts command as a child process, configured by the
+// process options among opts
+func NewStdioTransport(command string, args []string, opts ...ClientOption) (*StdioTransport, error) {
+	options := newClientOptions(opts)
 	cmd := exec.Command(command, args...)
+	cmd.Dir = options.dir
+	if len(options.env) > 0 {
+		cmd.Env = append(os.Environ(), options.env...)
+	}
+	setProcessGroup(cmd)
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
 	}
 
-	stdout, err := cmd.StdoutPipe()
+	// The pipes are created here rather than by exec, so that waiting for the
+	// child does not close them before everything it wrote has been read
+	stdoutReader, stdoutWriter, err := os.Pipe()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
+	cmd.Stdout = stdoutWriter
 
-	client := &StdioMCPClient{
-		cmd:    cmd,
-		stdin:  stdin,
-		stdout: bufio.NewReader(stdout),
-		done:   make(chan struct{}),
+	var stderrReader, stderrWriter *os.File
+	if options.stderr != nil || options.stderrHandler != nil {
+		if stderrReader, stderrWriter, err = os.Pipe(); err != nil {
+			stdoutReader.Close()
+			stdoutWriter.Close()
+			return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+		}
+		cmd.Stderr = stderrWriter
 	}
 
-	if err := cmd.Start(); err != nil {
+	err = cmd.Start()
+	stdoutWriter.Close()
+	if stderrWriter != nil {
+		stderrWriter.Close()
+	}
+	if err != nil {
+		stdoutReader.Close()
+		if stderrReader != nil {
+			stderrReader.Close()
+		}
 		return nil, fmt.Errorf("failed to start command: %w", err)
 	}
 
-	go client.readResponses()
-
-	return client, nil
-}
-
-func (c *StdioMCPClient) Close() error {
-	close(c.done)
-	if err := c.stdin.Close(); err != nil {
-		return fmt.Errorf("failed to close stdin: %w", err)
-	}
-	return c.cmd.Wait()
+	t := &StdioTransport{
+		cmd:          cmd,
+		stdin:        stdin,
+		stdout:       bufio.NewReader(stdoutReader),
+		stdoutPipe:   stdoutReader,
+		closeTimeout: options.closeTimeout,
+		stopContext:  func() bool { return false },
+		exited:       make(chan struct{}),
+		stderrDone:   make(chan struct{}),
+	}
+	go func() {
+		t.waitErr = cmd.Wait()
+		close(t.exited)
+	}()
+	if stderrReader != nil {
+		go func() {
+			defer close(t.stderrDone)
+			copyStderr(stderrReader, options.stderr, options.stderrHandler)
+		}()
+	} else {
+		close(t.stderrDone)
+	}
+	if options.commandCtx != nil {
+		t.stopContext = context.AfterFunc(options.commandCtx, func() {
+			killProcessGroup(cmd)
+		})
+	}
+	return t, nil
 }
 
-func (c *StdioMCPClient) readResponses() {
+func copyStderr(r io.ReadCloser, w io.Writer, handler func(line string)) {
+	defer r.Close()
+	reader := bufio.NewReader(r)
 	for {
-		select {
-		case <-c.done:
-			return
-		default:
-			line, err := c.stdout.ReadString('\n')
-			if err != nil {
-				if err != io.EOF {
-					fmt.Printf("Error reading response: %v\n", err)
-				}
-				return
-			}
-
-			response := &mcp.JSONRPCResponse{}
-			if err := response.UnmarshalJSON([]byte(line)); err != nil {
-				continue
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			if w != nil {
+				io.WriteString(w, line)
 			}
-
-			ch, ok := c.responses.Load(response.Id.(int64))
-
-			if ok {
-				if response.Error != nil {
-					ch.(chan json.RawMessage) <- nil // Signal error condition
-				} else {
-					ch.(chan json.RawMessage) <- response.Result
-				}
-				c.responses.Delete(response.Id.(int64))
+			if handler != nil {
+				handler(strings.TrimRight(line, "\r\n"))
 			}
 		}
-	}
-}
-
-func (c *StdioMCPClient) sendRequest(
-	ctx context.Context,
-	method string,
-	params interface{},
-) (json.RawMessage, error) {
-	if !c.initialized && method != "initialize" {
-		return nil, fmt.Errorf("client not initialized")
-	}
-
-	id := c.requestID.Add(1)
-
-	// Convert params to json.RawMessage
-	var paramsRaw json.RawMessage
-	if params != nil {
-		paramBytes, err := json.Marshal(params)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal params: %w", err)
-		}
-		paramsRaw = paramBytes
-	}
-
-	request := mcp.JSONRPCRequest{
-		Id:      id,
-		Jsonrpc: mcp.JSONRPCVersion,
-		Method:  method,
-		Params:  paramsRaw,
-	}
-
-	responseChan := make(chan json.RawMessage, 1)
-	c.responses.Store(id, responseChan)
-
-	requestBytes, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-	requestBytes = append(requestBytes, '\n')
-
-	if _, err := c.stdin.Write(requestBytes); err != nil {
-		return nil, fmt.Errorf("failed to write request: %w", err)
-	}
-
-	select {
-	case <-ctx.Done():
-		c.responses.Delete(id)
-		return nil, ctx.Err()
-	case response := <-responseChan:
-		if response == nil {
-			return nil, fmt.Errorf("request failed")
+			return
 		}
-		return response, nil
 	}
 }
 
-func (c *StdioMCPClient) Initialize(
-	ctx context.Context,
-	capabilities mcp.ClientCapabilities,
-	clientInfo mcp.Implementation,
-	protocolVersion string,
-) (*mcp.InitializeResult, error) {
-	params := struct {
-		Capabilities    mcp.ClientCapabilities `json:"capabilities"`
-		ClientInfo      mcp.Implementation     `json:"clientInfo"`
-		ProtocolVersion string                 `json:"protocolVersion"`
-	}{
-		Capabilities:    capabilities,
-		ClientInfo:      clientInfo,
-		ProtocolVersion: protocolVersion,
-	}
-
-	response, err := c.sendRequest(ctx, mcp.MethodInitialize, params)
-	if err != nil {
-		return nil, err
-	}
-
-	var result mcp.InitializeResult
-	if err := json.Unmarshal(response, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	c.initialized = true
-	return &result, nil
-}
-
-func (c *StdioMCPClient) Ping(ctx context.Context) error {
-	_, err := c.sendRequest(ctx, mcp.MethodPing, nil)
-	return err
-}
-
-func (c *StdioMCPClient) ListResources(
-	ctx context.Context,
-	cursor *string,
-) (*mcp.ListResourcesResult, error) {
-	params := struct {
-		Cursor *string `json:"cursor,omitempty"`
-	}{
-		Cursor: cursor,
-	}
-
-	response, err := c.sendRequest(ctx, mcp.MethodResourcesList, params)
-	if err != nil {
-		return nil, err
-	}
-
-	var result mcp.ListResourcesResult
-	if err := json.Unmarshal(response, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return &result, nil
+// Pid returns the process ID of the child
+func (t *StdioTransport) Pid() int {
+	return t.cmd.Process.Pid
 }
 
-func (c *StdioMCPClient) ReadResource(
-	ctx context.Context,
-	uri string,
-) (*mcp.ReadResourceResult, error) {
-	params := struct {
-		URI string `json:"uri"`
-	}{
-		URI: uri,
-	}
-
-	response, err := c.sendRequest(ctx, mcp.MethodResourcesRead, params)
-	if err != nil {
-		return nil, err
-	}
-
-	var result mcp.ReadResourceResult
-	if err := json.Unmarshal(response, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return &result, nil
+// Exited returns a channel that is closed once the child has exited
+func (t *StdioTransport) Exited() <-chan struct{} {
+	return t.exited
 }
 
-func (c *StdioMCPClient) SubscribeResource(ctx context.Context, uri string) error {
-	params := struct {
-		URI string `json:"uri"`
-	}{
-		URI: uri,
+// ExitErr returns the error reported by waiting for the child, such as an
+// *exec.ExitError for a non-zero exit status, once Exited is closed
+func (t *StdioTransport) ExitErr() error {
+	select {
+	case <-t.exited:
+		return t.waitErr
+	default:
+		return nil
 	}
-
-	_, err := c.sendRequest(ctx, mcp.MethodResourcesSubscribe, params)
-	return err
 }
 
-func (c *StdioMCPClient) UnsubscribeResource(ctx context.Context, uri string) error {
-	params := struct {
-		URI string `json:"uri"`
-	}{
-		URI: uri,
-	}
-
-	_, err := c.sendRequest(ctx, mcp.MethodResourcesUnsubscribe, params)
+func (t *StdioTransport) Send(ctx context.Context, message json.RawMessage) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	line := make([]byte, 0, len(message)+1)
+	line = append(append(line, message...), '\n')
+	_, err := t.stdin.Write(line)
 	return err
 }
 
-func (c *StdioMCPClient) ListPrompts(
-	ctx context.Context,
-	cursor *string,
-) (*mcp.ListPromptsResult, error) {
-	params := struct {
-		Cursor *string `json:"cursor,omitempty"`
-	}{
-		Cursor: cursor,
-	}
-
-	response, err := c.sendRequest(ctx, mcp.MethodPromptsList, params)
-	if err != nil {
-		return nil, err
-	}
-
-	var result mcp.ListPromptsResult
-	if err := json.Unmarshal(response, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return &result, nil
-}
-
-func (c *StdioMCPClient) GetPrompt(
-	ctx context.Context,
-	name string,
-	arguments map[string]string,
-) (*mcp.GetPromptResult, error) {
-	params := struct {
-		Name      string            `json:"name"`
-		Arguments map[string]string `json:"arguments,omitempty"`
-	}{
-		Name:      name,
-		Arguments: arguments,
-	}
-
-	response, err := c.sendRequest(ctx, mcp.MethodPromptsGet, params)
-	if err != nil {
-		return nil, err
-	}
-
-	var result mcp.GetPromptResult
-	if err := json.Unmarshal(response, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return &result, nil
-}
-
-func (c *StdioMCPClient) ListTools(
-	ctx context.Context,
-	cursor *string,
-) (*mcp.ListToolsResult, error) {
-	params := struct {
-		Cursor *string `json:"cursor,omitempty"`
-	}{
-		Cursor: cursor,
-	}
-
-	response, err := c.sendRequest(ctx, mcp.MethodToolsList, params)
-	if err != nil {
-		return nil, err
-	}
-
-	var result mcp.ListToolsResult
-	if err := json.Unmarshal(response, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+func (t *StdioTransport) Receive() (json.RawMessage, error) {
+	for {
+		line, err := t.stdout.ReadBytes('\n')
+		if err != nil {
+			if t.closing.Load() {
+				return nil, io.EOF
+			}
+			return nil, t.exitError()
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			return line, nil
+		}
 	}
-
-	return &result, nil
 }
 
-func (c *StdioMCPClient) CallTool(
-	ctx context.Context,
-	name string,
-	arguments map[string]interface{},
-) (*mcp.CallToolResult, error) {
-	params := struct {
-		Name      string                 `json:"name"`
-		Arguments map[string]interface{} `json:"arguments,omitempty"`
-	}{
-		Name:      name,
-		Arguments: arguments,
+// exitError describes the child closing its stdout without being asked to.
+// That normally means it exited, so its exit status is waited for briefly.
+func (t *StdioTransport) exitError() error {
+	timer := time.NewTimer(t.closeTimeout)
+	defer timer.Stop()
+	select {
+	case <-t.exited:
+	case <-timer.C:
 	}
-
-	response, err := c.sendRequest(ctx, mcp.MethodToolsCall, params)
-	if err != nil {
-		return nil, err
+	if t.closing.Load() {
+		return io.EOF
 	}
-
-	var result mcp.CallToolResult
-	if err := json.Unmarshal(response, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := t.ExitErr(); err != nil {
+		return fmt.Errorf("%w: %w", ErrServerExited, err)
 	}
-
-	return &result, nil
+	return ErrServerExited
 }
 
-func (c *StdioMCPClient) SetLoggingLevel(
-	ctx context.Context,
-	level mcp.LoggingLevel,
-) error {
-	params := struct {
-		Level mcp.LoggingLevel `json:"level"`
-	}{
-		Level: level,
-	}
+// Close shuts the child down gracefully by closing its stdin. If it has not
+// exited after the close timeout, it is asked to terminate, and if it still
+// has not exited after another timeout, it is killed. Processes left in its
+// process group are killed once it has exited, and the remaining stderr output
+// is delivered before Close returns. Close returns the child's exit error if it
+// exited on its own, and nil if it had to be stopped.
+func (t *StdioTransport) Close() error {
+	t.closeOnce.Do(func() {
+		t.closing.Store(true)
+		t.stopContext()
+		t.stdin.Close()
+
+		if t.waitExit() {
+			t.closeErr = t.waitErr
+		} else {
+			terminateProcessGroup(t.cmd)
+			if !t.waitExit() {
+				killProcessGroup(t.cmd)
+				<-t.exited
+			}
+		}
+		killOrphans(t.cmd)
+		t.stdoutPipe.Close()
 
-	_, err := c.sendRequest(ctx, mcp.MethodLoggingSetLevel, params)
-	return err
+		// Processes that left the group may still hold stderr open
+		timer := time.NewTimer(t.closeTimeout)
+		defer timer.Stop()
+		select {
+		case <-t.stderrDone:
+		case <-timer.C:
+		}
+	})
+	return t.closeErr
 }
 
-func (c *StdioMCPClient) Complete(
-	ctx context.Context,
-	ref interface{},
-	argument mcp.CompleteRequest,
-) (*mcp.CompleteResult, error) {
-	params := struct {
-		Ref      interface{}         `json:"ref"`
-		Argument mcp.CompleteRequest `json:"argument"`
-	}{
-		Ref:      ref,
-		Argument: argument,
-	}
-
-	response, err := c.sendRequest(ctx, mcp.MethodCompletionComplete, params)
-	if err != nil {
-		return nil, err
-	}
-
-	var result mcp.CompleteResult
-	if err := json.Unmarshal(response, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+// waitExit waits up to the close timeout for the child to exit
+func (t *StdioTransport) waitExit() bool {
+	timer := time.NewTimer(t.closeTimeout)
+	defer timer.Stop()
+	select {
+	case <-t.exited:
+		return true
+	case <-timer.C:
+		return false
 	}
-
-	return &result, nil
 }
@@ -0,0 +1,174 @@
+//go:build !windows
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/client"
+)
+
+func startShell(t *testing.T, script string, opts ...client.ClientOption) *client.StdioTransport {
+	t.Helper()
+	transport, err := client.NewStdioTransport("sh", []string{"-c", script}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { transport.Close() })
+	return transport
+}
+
+func TestStdioProcessOptions(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		mu    sync.Mutex
+		lines []string
+	)
+	var stderr bytes.Buffer
+	transport := startShell(t, `echo "$GOMCP_VALUE"; pwd; cat`,
+		client.WithEnv("GOMCP_VALUE=from option"),
+		client.WithDir(dir),
+		client.WithStderr(&stderr),
+		client.WithStderrHandler(func(line string) {
+			mu.Lock()
+			defer mu.Unlock()
+			lines = append(lines, line)
+		}),
+	)
+
+	for _, want := range []string{"from option", dir} {
+		message, err := transport.Receive()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(message) != want {
+			t.Errorf("output = %q, want %q", message, want)
+		}
+	}
+	if err := transport.Send(context.Background(), []byte(`{"echo":true}`)); err != nil {
+		t.Fatal(err)
+	}
+	if message, err := transport.Receive(); err != nil || string(message) != `{"echo":true}` {
+		t.Errorf("Receive() = %q, %v", message, err)
+	}
+	// Closing stdin is enough for the child to exit
+	if err := transport.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	transport = startShell(t, `echo first >&2; printf 'last' >&2`,
+		client.WithStderr(&stderr),
+		client.WithStderrHandler(func(line string) {
+			mu.Lock()
+			defer mu.Unlock()
+			lines = append(lines, line)
+		}),
+	)
+	<-transport.Exited()
+	transport.Close()
+	// The remaining stderr output is delivered before Close returns
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"first", "last"}; strings.Join(lines, ",") != strings.Join(want, ",") {
+		t.Errorf("stderr lines = %q, want %q", lines, want)
+	}
+	if stderr.String() != "first\nlast" {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestStdioExitError(t *testing.T) {
+	transport := startShell(t, `exit 3`)
+	_, err := transport.Receive()
+	if !errors.Is(err, client.ErrServerExited) {
+		t.Fatalf("Receive error = %v, want %v", err, client.ErrServerExited)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(transport.ExitErr(), &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("ExitErr() = %v, want exit status 3", transport.ExitErr())
+	}
+}
+
+func TestStdioCloseEscalates(t *testing.T) {
+	// The child ignores both its closed stdin and SIGTERM
+	transport := startShell(t, `trap "" TERM; while :; do sleep 0.05; done`,
+		client.WithCloseTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	if err := transport.Close(); err != nil {
+		t.Errorf("Close: %v, want nil for a child that had to be stopped", err)
+	}
+	select {
+	case <-transport.Exited():
+	default:
+		t.Error("child still running after Close")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Close took %v", elapsed)
+	}
+}
+
+func TestStdioCloseKillsOrphans(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("process state is read from /proc")
+	}
+	// The child spawns a grandchild in its process group and exits when its
+	// stdin is closed
+	transport := startShell(t, `sleep 30 & echo $!; exec cat`)
+	message, err := transport.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := strconv.Atoi(string(message))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := transport.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for running(pid) {
+		if time.Now().After(deadline) {
+			t.Fatalf("grandchild %d still running after Close", pid)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// running reports whether pid names a live process, not counting zombies that
+// wait to be reaped
+func running(pid int) bool {
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return false
+	}
+	// The state follows the parenthesized command name
+	_, rest, _ := strings.Cut(string(stat), ") ")
+	return !strings.HasPrefix(rest, "Z")
+}
+
+func TestStdioCommandContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	transport := startShell(t, `trap "" TERM; exec sleep 30`, client.WithCommandContext(ctx))
+	cancel()
+	select {
+	case <-transport.Exited():
+	case <-time.After(5 * time.Second):
+		t.Fatal("child not killed when its context was cancelled")
+	}
+}
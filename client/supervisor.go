@@ -0,0 +1,501 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+var _ MCPClient = &SupervisedClient{}
+
+// RestartPolicy controls how a SupervisedClient restarts its server
+type RestartPolicy struct {
+	// MaxRestarts is the number of consecutive restarts attempted before giving
+	// up. Zero disables restarts and a negative value allows any number.
+	MaxRestarts int
+
+	// InitialBackoff is the delay before the first restart. Each further
+	// consecutive restart waits Multiplier times longer, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// ResetAfter is how long a server must run for its restarts to no longer
+	// count as consecutive
+	ResetAfter time.Duration
+}
+
+// DefaultRestartPolicy is the policy of supervised clients created without
+// WithRestartPolicy
+var DefaultRestartPolicy = RestartPolicy{
+	MaxRestarts:    5,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+	Multiplier:     2,
+	ResetAfter:     time.Minute,
+}
+
+func (p RestartPolicy) backoff(restarts int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 0; i < restarts; i++ {
+		delay *= max(p.Multiplier, 1)
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+	return time.Duration(delay)
+}
+
+// restartTimeout bounds re-initializing a restarted server
+const restartTimeout = 30 * time.Second
+
+// WithRestartPolicy sets the restart policy of a supervised client
+func WithRestartPolicy(policy RestartPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.restartPolicy = &policy
+	}
+}
+
+// WithExitHandler sets a function called with the reason whenever the server of
+// a supervised client exits unexpectedly, before it is restarted
+func WithExitHandler(fn func(err error)) ClientOption {
+	return func(o *clientOptions) {
+		o.exitHandler = fn
+	}
+}
+
+// SupervisedClient is an MCPClient talking to a server started as a child
+// process, which is restarted with backoff whenever it exits unexpectedly.
+//
+// Requests in flight when the server exits fail with an error wrapping
+// ErrServerExited; they are not retried, since they may have had effects,
+// unless a RetryInterceptor is installed, whose retries reach the restarted
+// server.
+// Requests made while the server restarts wait for it. Once restarted, the
+// server is initialized with the parameters of the last successful Initialize
+// call, and resource subscriptions, the logging level and the notification and
+// request handlers are restored.
+type SupervisedClient struct {
+	command string
+	args    []string
+	opts    []ClientOption
+	policy  RestartPolicy
+	onExit  func(err error)
+
+	mu sync.Mutex
+	// current is nil while the server restarts; ready is closed once it is set
+	current *StdioMCPClient
+	ready   chan struct{}
+	// err is set once the supervisor gave up restarting
+	err error
+	// ctx is cancelled by Close, stopping the supervision and any restart
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	initParams    *initParams
+	subscriptions map[string]struct{}
+	loggingLevel  *mcp.LoggingLevel
+	// registrations replay the handlers registered so far on a restarted server
+	registrations []func(c *Client)
+}
+
+type initParams struct {
+	capabilities    mcp.ClientCapabilities
+	clientInfo      mcp.Implementation
+	protocolVersion string
+}
+
+// NewSupervisedClient starts command as a supervised child process, configured
+// by opts
+func NewSupervisedClient(command string, args []string, opts ...ClientOption) (*SupervisedClient, error) {
+	options := newClientOptions(opts)
+	s := &SupervisedClient{
+		command:       command,
+		args:          args,
+		opts:          opts,
+		policy:        DefaultRestartPolicy,
+		onExit:        options.exitHandler,
+		ready:         make(chan struct{}),
+		subscriptions: make(map[string]struct{}),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	if options.restartPolicy != nil {
+		s.policy = *options.restartPolicy
+	}
+	s.opts = append(append([]ClientOption{}, opts...), func(o *clientOptions) {
+		o.route = s.route
+	})
+
+	c, err := NewStdioMCPClientWithOptions(command, args, s.opts...)
+	if err != nil {
+		s.cancel()
+		return nil, err
+	}
+	s.current = c
+	close(s.ready)
+	go s.supervise(c)
+	return s, nil
+}
+
+// client waits until a running server is available
+func (s *SupervisedClient) client(ctx context.Context) (*StdioMCPClient, error) {
+	for {
+		s.mu.Lock()
+		current, ready, err := s.current, s.ready, s.err
+		s.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		if current != nil {
+			return current, nil
+		}
+
+		select {
+		case <-ready:
+		case <-s.ctx.Done():
+			return nil, ErrConnectionClosed
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (s *SupervisedClient) supervise(c *StdioMCPClient) {
+	restarts := 0
+	started := time.Now()
+	for {
+		select {
+		case <-c.Done():
+		case <-s.ctx.Done():
+			return
+		}
+
+		s.mu.Lock()
+		if s.ctx.Err() != nil {
+			s.mu.Unlock()
+			return
+		}
+		s.current = nil
+		s.ready = make(chan struct{})
+		s.mu.Unlock()
+
+		reason := c.Err()
+		c.Close()
+		if s.onExit != nil {
+			s.onExit(reason)
+		}
+		if s.policy.ResetAfter > 0 && time.Since(started) >= s.policy.ResetAfter {
+			restarts = 0
+		}
+
+		for {
+			if s.policy.MaxRestarts >= 0 && restarts >= s.policy.MaxRestarts {
+				if !errors.Is(reason, ErrServerExited) {
+					reason = fmt.Errorf("%w: %w", ErrServerExited, reason)
+				}
+				s.giveUp(fmt.Errorf("giving up after %d restarts: %w", restarts, reason))
+				return
+			}
+			timer := time.NewTimer(s.policy.backoff(restarts))
+			restarts++
+			select {
+			case <-timer.C:
+			case <-s.ctx.Done():
+				timer.Stop()
+				return
+			}
+
+			next, err := s.restart()
+			if err == nil {
+				c, started = next, time.Now()
+				break
+			}
+			reason = err
+		}
+	}
+}
+
+// route sends a request over the transport of c, or over the transport of the
+// server replacing it if c exited, so that interceptors retrying a request
+// reach the restarted server
+func (s *SupervisedClient) route(c *Client, ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+	select {
+	case <-c.Done():
+		current, err := s.client(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c = current.Client
+	default:
+	}
+	return c.roundTrip(ctx, method, params)
+}
+
+// restart starts a new server and restores the state of the previous one
+func (s *SupervisedClient) restart() (*StdioMCPClient, error) {
+	c, err := NewStdioMCPClientWithOptions(s.command, s.args, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	registered := len(s.registrations)
+	for _, register := range s.registrations {
+		register(c.Client)
+	}
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(s.ctx, restartTimeout)
+	defer cancel()
+	if err := s.restore(ctx, c); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.Err() != nil {
+		c.Close()
+		return nil, ErrConnectionClosed
+	}
+	// Catch up with the handlers registered while the server was restored
+	for _, register := range s.registrations[registered:] {
+		register(c.Client)
+	}
+	s.current = c
+	close(s.ready)
+	return c, nil
+}
+
+func (s *SupervisedClient) restore(ctx context.Context, c *StdioMCPClient) error {
+	s.mu.Lock()
+	params := s.initParams
+	level := s.loggingLevel
+	uris := make([]string, 0, len(s.subscriptions))
+	for uri := range s.subscriptions {
+		uris = append(uris, uri)
+	}
+	s.mu.Unlock()
+
+	if params == nil {
+		return nil
+	}
+	if _, err := c.Initialize(ctx, params.capabilities, params.clientInfo, params.protocolVersion); err != nil {
+		return fmt.Errorf("failed to initialize restarted server: %w", err)
+	}
+	if level != nil {
+		if err := c.SetLoggingLevel(ctx, *level); err != nil {
+			return fmt.Errorf("failed to restore logging level: %w", err)
+		}
+	}
+	for _, uri := range uris {
+		if err := c.SubscribeResource(ctx, uri); err != nil {
+			return fmt.Errorf("failed to restore subscription to %s: %w", uri, err)
+		}
+	}
+	return nil
+}
+
+func (s *SupervisedClient) giveUp(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	close(s.ready)
+}
+
+// Close stops supervising and closes the server, aborting any restart in
+// progress
+func (s *SupervisedClient) Close() error {
+	s.mu.Lock()
+	if s.ctx.Err() != nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.cancel()
+	current := s.current
+	s.current = nil
+	s.mu.Unlock()
+
+	if current != nil {
+		return current.Close()
+	}
+	return nil
+}
+
+// ProtocolVersion returns the protocol revision negotiated with the running
+// server, or an empty string if it is not initialized or not running
+func (s *SupervisedClient) ProtocolVersion() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current == nil {
+		return ""
+	}
+	return s.current.ProtocolVersion()
+}
+
+// OnNotification registers fn for the notifications the server sends for
+// method, as Client.OnNotification does, on the running server and every
+// restarted one
+func (s *SupervisedClient) OnNotification(method string, fn NotificationHandlerFunc) {
+	s.register(func(c *Client) { c.OnNotification(method, fn) })
+}
+
+// OnRequest registers fn to answer the requests the server sends for method, as
+// Client.OnRequest does, on the running server and every restarted one
+func (s *SupervisedClient) OnRequest(method string, fn RequestHandlerFunc) {
+	s.register(func(c *Client) { c.OnRequest(method, fn) })
+}
+
+func (s *SupervisedClient) register(register func(c *Client)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.registrations = append(s.registrations, register)
+	if s.current != nil {
+		register(s.current.Client)
+	}
+}
+
+// Notify sends a notification to the running server, waiting for it if it is
+// restarting
+func (s *SupervisedClient) Notify(ctx context.Context, method string, params interface{}) error {
+	c, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+	return c.Notify(ctx, method, params)
+}
+
+func (s *SupervisedClient) Initialize(
+	ctx context.Context,
+	capabilities mcp.ClientCapabilities,
+	clientInfo mcp.Implementation,
+	protocolVersion string,
+) (*mcp.InitializeResult, error) {
+	c, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.Initialize(ctx, capabilities, clientInfo, protocolVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.initParams = &initParams{
+		capabilities:    capabilities,
+		clientInfo:      clientInfo,
+		protocolVersion: protocolVersion,
+	}
+	s.mu.Unlock()
+	return result, nil
+}
+
+func (s *SupervisedClient) Ping(ctx context.Context) error {
+	c, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+	return c.Ping(ctx)
+}
+
+func (s *SupervisedClient) SetLoggingLevel(ctx context.Context, level mcp.LoggingLevel) error {
+	c, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.SetLoggingLevel(ctx, level); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.loggingLevel = &level
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *SupervisedClient) Complete(ctx context.Context, ref interface{}, argument mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	c, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.Complete(ctx, ref, argument)
+}
+
+func (s *SupervisedClient) ListResources(ctx context.Context, cursor *string) (*mcp.ListResourcesResult, error) {
+	c, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.ListResources(ctx, cursor)
+}
+
+func (s *SupervisedClient) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	c, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.ReadResource(ctx, uri)
+}
+
+func (s *SupervisedClient) SubscribeResource(ctx context.Context, uri string) error {
+	c, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.SubscribeResource(ctx, uri); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.subscriptions[uri] = struct{}{}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *SupervisedClient) UnsubscribeResource(ctx context.Context, uri string) error {
+	c, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.UnsubscribeResource(ctx, uri); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.subscriptions, uri)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *SupervisedClient) ListPrompts(ctx context.Context, cursor *string) (*mcp.ListPromptsResult, error) {
+	c, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.ListPrompts(ctx, cursor)
+}
+
+func (s *SupervisedClient) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*mcp.GetPromptResult, error) {
+	c, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetPrompt(ctx, name, arguments)
+}
+
+func (s *SupervisedClient) ListTools(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error) {
+	c, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.ListTools(ctx, cursor)
+}
+
+func (s *SupervisedClient) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	c, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.CallTool(ctx, name, arguments)
+}
@@ -0,0 +1,152 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server"
+	"github.com/WePrompt/gomcp/server/handlers"
+)
+
+// The test binary doubles as the server of supervised clients when
+// GOMCP_TEST_SERVER names a directory counting its starts
+func TestMain(m *testing.M) {
+	if dir := os.Getenv("GOMCP_TEST_SERVER"); dir != "" {
+		runTestServer(dir, os.Getenv("GOMCP_TEST_RESTARTED") == "hang")
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runTestServer serves an exit tool stopping the process. If hang is set,
+// restarted servers never answer, and record that they were stopped once their
+// stdin is closed.
+func runTestServer(dir string, hang bool) {
+	start := recordStart(dir)
+	if hang && start > 1 {
+		io.Copy(io.Discard, os.Stdin)
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("stopped-%d", start)), nil, 0o644)
+		os.Exit(0)
+	}
+
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{Name: "exit"}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		os.Exit(1)
+		return nil, nil
+	})
+	tools.Register(mcp.Tool{Name: "start"}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		result := &mcp.CallToolResult{}
+		result.AddTextContent(mcp.NewTextContent(fmt.Sprint(start)))
+		return result, nil
+	})
+	s := server.NewMCPServer(server.WithToolHandler(tools))
+	if err := server.NewStdioServer(*s).Serve(); err != nil {
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+// recordStart counts a start of the server in dir and returns the count
+func recordStart(dir string) int {
+	f, err := os.OpenFile(filepath.Join(dir, "starts"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		os.Exit(3)
+	}
+	defer f.Close()
+	f.Write([]byte{'.'})
+	info, err := f.Stat()
+	if err != nil {
+		os.Exit(3)
+	}
+	return int(info.Size())
+}
+
+func starts(dir string) int {
+	info, err := os.Stat(filepath.Join(dir, "starts"))
+	if err != nil {
+		return 0
+	}
+	return int(info.Size())
+}
+
+func newSupervisedClient(t *testing.T, dir string, opts ...client.ClientOption) *client.SupervisedClient {
+	t.Helper()
+	opts = append([]client.ClientOption{client.WithEnv("GOMCP_TEST_SERVER=" + dir)}, opts...)
+	s, err := client.NewSupervisedClient(os.Args[0], []string{"-test.run=^$"}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	if _, err := s.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1"}, mcp.LatestProtocolVersion); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSupervisedClientRestart(t *testing.T) {
+	exits := make(chan error, 1)
+	s := newSupervisedClient(t, t.TempDir(),
+		client.WithRestartPolicy(client.RestartPolicy{MaxRestarts: 1, InitialBackoff: time.Millisecond}),
+		client.WithExitHandler(func(err error) { exits <- err }),
+	)
+	ctx := context.Background()
+
+	if _, err := s.CallTool(ctx, "exit", nil); !errors.Is(err, client.ErrServerExited) {
+		t.Fatalf("request in flight when the server exited: %v", err)
+	}
+	select {
+	case <-exits:
+	case <-time.After(5 * time.Second):
+		t.Fatal("exit handler not called")
+	}
+
+	// The restarted server is initialized again before requests reach it
+	result, err := s.CallTool(ctx, "start", nil)
+	if err != nil {
+		t.Fatalf("request to the restarted server: %v", err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "2" {
+		t.Errorf("request reached start %s of the server, want 2", text)
+	}
+	if s.ProtocolVersion() != mcp.LatestProtocolVersion {
+		t.Errorf("ProtocolVersion() = %q after restart", s.ProtocolVersion())
+	}
+}
+
+func TestSupervisedClientGivesUp(t *testing.T) {
+	s := newSupervisedClient(t, t.TempDir(), client.WithRestartPolicy(client.RestartPolicy{MaxRestarts: 0}))
+	ctx := context.Background()
+	s.CallTool(ctx, "exit", nil)
+
+	waitFor(t, "supervisor gave up", func() bool {
+		return errors.Is(s.Ping(ctx), client.ErrServerExited)
+	})
+}
+
+func TestSupervisedClientCloseDuringRestart(t *testing.T) {
+	dir := t.TempDir()
+	s := newSupervisedClient(t, dir,
+		client.WithEnv("GOMCP_TEST_RESTARTED=hang"),
+		client.WithRestartPolicy(client.RestartPolicy{MaxRestarts: 1, InitialBackoff: time.Millisecond}),
+	)
+	s.CallTool(context.Background(), "exit", nil)
+
+	// The restarted server never answers, so restoring its state blocks until
+	// Close aborts it
+	waitFor(t, "server restarted", func() bool { return starts(dir) == 2 })
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "restarted server stopped by Close", func() bool {
+		_, err := os.Stat(filepath.Join(dir, "stopped-2"))
+		return err == nil
+	})
+}
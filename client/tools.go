@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// ToolFilter selects tools, typically by their annotations. Annotations are
+// hints supplied by the server, so only rely on them for trusted servers.
+type ToolFilter func(tool mcp.Tool) bool
+
+// ReadOnly selects tools that declare they do not modify their environment
+func ReadOnly(tool mcp.Tool) bool {
+	return tool.Annotations.IsReadOnly()
+}
+
+// NonDestructive selects tools that declare they perform no destructive
+// updates, including read-only tools
+func NonDestructive(tool mcp.Tool) bool {
+	return !tool.Annotations.IsDestructive()
+}
+
+// Idempotent selects tools that declare repeated calls with the same arguments
+// have no additional effect, including read-only tools
+func Idempotent(tool mcp.Tool) bool {
+	return tool.Annotations.IsIdempotent()
+}
+
+// ClosedWorld selects tools that declare they do not interact with external
+// entities
+func ClosedWorld(tool mcp.Tool) bool {
+	return !tool.Annotations.IsOpenWorld()
+}
+
+// FilterTools returns the tools selected by every filter, in their original order
+func FilterTools(tools []mcp.Tool, filters ...ToolFilter) []mcp.Tool {
+	var selected []mcp.Tool
+	for _, tool := range tools {
+		if matchesAll(tool, filters) {
+			selected = append(selected, tool)
+		}
+	}
+	return selected
+}
+
+// ListToolsMatching fetches every page of the server's tools and returns those
+// selected by every filter
+func ListToolsMatching(ctx context.Context, c MCPClient, filters ...ToolFilter) ([]mcp.Tool, error) {
+	var selected []mcp.Tool
+	for tool, err := range AllTools(ctx, c) {
+		if err != nil {
+			return nil, err
+		}
+		if matchesAll(tool, filters) {
+			selected = append(selected, tool)
+		}
+	}
+	return selected, nil
+}
+
+func matchesAll(tool mcp.Tool, filters []ToolFilter) bool {
+	for _, filter := range filters {
+		if !filter(tool) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package client_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+func annotatedTools() []mcp.Tool {
+	yes, no := true, false
+	return []mcp.Tool{
+		{Name: "read", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: &yes, OpenWorldHint: &no}},
+		{Name: "append", Annotations: &mcp.ToolAnnotations{DestructiveHint: &no}},
+		{Name: "delete", Annotations: &mcp.ToolAnnotations{IdempotentHint: &yes, OpenWorldHint: &no}},
+		{Name: "unannotated"},
+	}
+}
+
+func TestFilterTools(t *testing.T) {
+	tools := annotatedTools()
+	tests := []struct {
+		name    string
+		filters []client.ToolFilter
+		want    []string
+	}{
+		{"none", nil, []string{"read", "append", "delete", "unannotated"}},
+		{"read-only", []client.ToolFilter{client.ReadOnly}, []string{"read"}},
+		{"non-destructive", []client.ToolFilter{client.NonDestructive}, []string{"read", "append"}},
+		{"idempotent", []client.ToolFilter{client.Idempotent}, []string{"read", "delete"}},
+		{"closed world", []client.ToolFilter{client.ClosedWorld}, []string{"read", "delete"}},
+		{"all of", []client.ToolFilter{client.Idempotent, client.NonDestructive}, []string{"read"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, tool := range client.FilterTools(tools, tt.filters...) {
+				names = append(names, tool.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("tools = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestListToolsMatching(t *testing.T) {
+	c := &pagedClient{tools: annotatedTools()}
+
+	tools, err := client.ListToolsMatching(context.Background(), c, client.NonDestructive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	if !slices.Equal(names, []string{"read", "append"}) {
+		t.Errorf("tools = %v, want the non-destructive tools of every page", names)
+	}
+}
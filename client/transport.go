@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// ErrConnectionClosed is returned by requests that cannot complete because the
+// connection to the server was closed
+var ErrConnectionClosed = errors.New("connection closed")
+
+// ErrServerExited is returned by requests that cannot complete because a server
+// running as a child process exited. The error returned usually also wraps the
+// child's exit error, such as an *exec.ExitError.
+var ErrServerExited = errors.New("server exited")
+
+// Transport carries JSON-RPC messages between a client and a server
+type Transport interface {
+	// Send delivers a single message to the server. It may be called
+	// concurrently.
+	Send(ctx context.Context, message json.RawMessage) error
+
+	// Receive blocks until the next message from the server is available. It
+	// returns io.EOF once the transport is closed. It is only called from one
+	// goroutine at a time.
+	Receive() (json.RawMessage, error)
+
+	// Close releases the connection and unblocks Receive
+	Close() error
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/WePrompt/gomcp/jsonschema"
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// ValidateToolArguments checks arguments against the tool's inputSchema, so that
+// invalid calls can be caught before they are sent. It returns
+// jsonschema.ValidationErrors describing each offending field if the arguments
+// are invalid.
+func ValidateToolArguments(tool mcp.Tool, arguments map[string]interface{}) error {
+	schemaBytes, err := json.Marshal(tool.InputSchema)
+	if err != nil {
+		return fmt.Errorf("failed to marshal input schema: %w", err)
+	}
+	schema, err := jsonschema.Compile(schemaBytes)
+	if err != nil {
+		return fmt.Errorf("invalid input schema for tool %s: %w", tool.Name, err)
+	}
+
+	// Round-trip the arguments so they hold the same types a server decodes
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	argumentBytes, err := json.Marshal(arguments)
+	if err != nil {
+		return fmt.Errorf("failed to marshal arguments: %w", err)
+	}
+	return schema.ValidateJSON(argumentBytes)
+}
+
+// ValidateToolOutput checks the structured content of a tool result against the
+// tool's outputSchema. Results of tools without an output schema and error
+// results are always valid.
+func ValidateToolOutput(tool mcp.Tool, result *mcp.CallToolResult) error {
+	if tool.OutputSchema == nil || result.IsError {
+		return nil
+	}
+	if result.StructuredContent == nil {
+		return fmt.Errorf("tool %s declares an output schema but returned no structured content", tool.Name)
+	}
+
+	schemaBytes, err := json.Marshal(tool.OutputSchema)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output schema: %w", err)
+	}
+	schema, err := jsonschema.Compile(schemaBytes)
+	if err != nil {
+		return fmt.Errorf("invalid output schema for tool %s: %w", tool.Name, err)
+	}
+
+	// Round-trip the structured content, which may have been built in Go rather
+	// than decoded from a response
+	contentBytes, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+	return schema.ValidateJSON(contentBytes)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/WePrompt/gomcp/jsonschema"
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+func TestValidateToolArguments(t *testing.T) {
+	tool := mcp.Tool{
+		Name: "echo",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: mcp.ToolInputSchemaProperties{"count": map[string]interface{}{"type": "integer"}},
+		},
+	}
+
+	// Go integers validate as the JSON integers a server decodes
+	if err := ValidateToolArguments(tool, map[string]interface{}{"count": 3}); err != nil {
+		t.Errorf("valid arguments: %v", err)
+	}
+	if err := ValidateToolArguments(tool, nil); err != nil {
+		t.Errorf("no arguments: %v", err)
+	}
+	var errs jsonschema.ValidationErrors
+	if err := ValidateToolArguments(tool, map[string]interface{}{"count": "3"}); !errors.As(err, &errs) || len(errs) != 1 {
+		t.Errorf("invalid arguments = %v, want a single validation error", err)
+	}
+}
+
+func TestValidateToolOutput(t *testing.T) {
+	tool := mcp.Tool{
+		Name: "count",
+		OutputSchema: &mcp.ToolOutputSchema{
+			Type: "object",
+			Properties: mcp.ToolInputSchemaProperties{
+				"count": map[string]interface{}{"type": "integer"},
+				"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+			Required: []string{"count"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		result  *mcp.CallToolResult
+		invalid bool
+	}{
+		{"go values", &mcp.CallToolResult{StructuredContent: map[string]interface{}{"count": 3, "tags": []string{"a"}}}, false},
+		{"decoded values", &mcp.CallToolResult{StructuredContent: map[string]interface{}{"count": float64(3)}}, false},
+		{"wrong type", &mcp.CallToolResult{StructuredContent: map[string]interface{}{"count": "3"}}, true},
+		{"missing property", &mcp.CallToolResult{StructuredContent: map[string]interface{}{}}, true},
+		{"error result", &mcp.CallToolResult{IsError: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateToolOutput(tool, tt.result)
+			var errs jsonschema.ValidationErrors
+			if tt.invalid != errors.As(err, &errs) {
+				t.Errorf("ValidateToolOutput = %v, want invalid %v", err, tt.invalid)
+			}
+			if !tt.invalid && err != nil {
+				t.Errorf("ValidateToolOutput = %v", err)
+			}
+		})
+	}
+
+	if err := ValidateToolOutput(tool, &mcp.CallToolResult{}); err == nil {
+		t.Error("missing structured content accepted")
+	}
+	if err := ValidateToolOutput(mcp.Tool{Name: "free"}, &mcp.CallToolResult{}); err != nil {
+		t.Errorf("tool without output schema: %v", err)
+	}
+}
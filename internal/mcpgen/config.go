@@ -0,0 +1,78 @@
+package main
+
+// goType is a Go type written by hand in the target package
+type goType struct {
+	name string
+
+	// pointer is set if optional values of the type are held by pointer
+	pointer bool
+}
+
+// handWritten maps the definitions implemented by hand in package mcp to the
+// types standing for them. The generator emits no code for these definitions.
+var handWritten = map[string]goType{
+	// RequestID and ProgressToken keep the form, string or integer, they were
+	// given in
+	"RequestId":     {name: "RequestID", pointer: true},
+	"ProgressToken": {name: "ProgressToken", pointer: true},
+
+	// The JSON-RPC envelopes leave params and result undecoded, and a single
+	// response type carries either a result or an error
+	"JSONRPCRequest":  {name: "JSONRPCRequest", pointer: true},
+	"JSONRPCResponse": {name: "JSONRPCResponse", pointer: true},
+	"JSONRPCError":    {name: "JSONRPCResponse", pointer: true},
+
+	// ResourceContents is the base of the text and blob resource contents.
+	// The hand-written interface of that name stands for either of them.
+	"ResourceContents": {name: "ResourceContents"},
+}
+
+// fieldTypes overrides the type of single properties, keyed by the name of
+// the generated struct and the name of the property
+var fieldTypes = map[string]goType{
+	// Tool schemas keep every JSON Schema keyword, not only the ones declared
+	// by the MCP schema
+	"Tool.inputSchema":  {name: "ToolInputSchema", pointer: true},
+	"Tool.outputSchema": {name: "ToolOutputSchema", pointer: true},
+
+	// Structured content is validated by package jsonschema, which expects
+	// the plain maps produced by encoding/json
+	"CallToolResult.structuredContent": {name: "map[string]interface{}"},
+
+	// Resource sizes are counted in bytes and may exceed the range of int on
+	// 32-bit platforms
+	"Resource.size":     {name: "int64", pointer: true},
+	"ResourceLink.size": {name: "int64", pointer: true},
+}
+
+// union is a set of definitions represented by a hand-written interface,
+// decoded by looking at the JSON object
+type union struct {
+	name string
+
+	// definition is the name of the definition of the union in the schema,
+	// if it has one. Properties may also list alternatives inline.
+	definition   string
+	alternatives []string
+
+	// decode and decodeList are the functions decoding a single value and a
+	// list of values of the union
+	decode     string
+	decodeList string
+}
+
+var unions = []union{
+	{
+		name:         "Content",
+		definition:   "ContentBlock",
+		alternatives: []string{"TextContent", "ImageContent", "AudioContent", "ResourceLink", "EmbeddedResource"},
+		decode:       "UnmarshalContent",
+		decodeList:   "unmarshalContents",
+	},
+	{
+		name:         "ResourceContents",
+		alternatives: []string{"TextResourceContents", "BlobResourceContents"},
+		decode:       "UnmarshalResourceContents",
+		decodeList:   "unmarshalResourceContentsList",
+	},
+}
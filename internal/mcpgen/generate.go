@@ -0,0 +1,574 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type declKind int
+
+const (
+	// kindType is a named type with an underlying map, basic or interface type
+	kindType declKind = iota
+	kindStruct
+	kindEnum
+)
+
+// decl is a named type to generate
+type decl struct {
+	name string
+	doc  string
+	kind declKind
+
+	underlying string
+	fields     []*field
+	additional bool
+	enum       []string
+
+	// nested holds the types declared inline in the schema of this one, which
+	// are emitted right after it
+	nested []*decl
+}
+
+type field struct {
+	name     string
+	property string
+	doc      string
+	typ      string
+	required bool
+	pointer  bool
+
+	// since is the protocol revision that added the property to its object,
+	// if the object already existed in the revision before
+	since string
+
+	// union is set if the field holds a hand-written union, decoded as a list
+	// if list is set
+	union *union
+	list  bool
+
+	minimum *float64
+	maximum *float64
+}
+
+// fieldType is the Go type generated for the schema of a property
+type fieldType struct {
+	expr string
+
+	// pointer is set if optional values are held by pointer
+	pointer bool
+
+	union *union
+	list  bool
+}
+
+// revision is the schema of a revision of the protocol
+type revision struct {
+	version string
+	schema  *Schema
+}
+
+type generator struct {
+	schema *Schema
+
+	// revisions are ordered from the first to the latest, whose schema is
+	// the one generated
+	revisions []revision
+}
+
+// generate returns the Go source of the types defined by the latest revision
+func generate(revisions []revision, pkg, source string) ([]byte, error) {
+	schema := revisions[len(revisions)-1].schema
+	g := &generator{schema: schema, revisions: revisions}
+
+	names := make([]string, 0, len(schema.Definitions))
+	for name := range schema.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var decls []*decl
+	for _, name := range names {
+		d, err := g.define(name, schema.Definitions[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s: %w", name, err)
+		}
+		if d != nil {
+			decls = append(decls, d)
+		}
+	}
+
+	var body bytes.Buffer
+	var usesReflect bool
+	for _, d := range decls {
+		usesReflect = writeDecl(&body, d) || usesReflect
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by mcpgen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+	src.WriteString("import (\n\t\"encoding/json\"\n\t\"fmt\"\n")
+	if usesReflect {
+		src.WriteString("\t\"reflect\"\n")
+	}
+	src.WriteString(")\n")
+	src.Write(body.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// define returns the declaration generated for a definition of the schema, or
+// nil if the definition is implemented by hand
+func (g *generator) define(name string, s *Schema) (*decl, error) {
+	if _, ok := handWritten[name]; ok {
+		return nil, nil
+	}
+	for _, u := range unions {
+		if u.definition == name {
+			return nil, nil
+		}
+	}
+
+	switch t := s.Type.single(); {
+	case t == "object":
+		return g.object(name, "definitions/"+name, s)
+	case t == "string" && len(s.Enum) > 0:
+		return &decl{name: name, doc: s.Description, kind: kindEnum, enum: s.Enum}, nil
+	case t == "string" || t == "integer" || t == "number" || t == "boolean":
+		ft, err := g.typeOf(s, name, "definitions/"+name, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &decl{name: name, doc: s.Description, underlying: ft.expr}, nil
+	default:
+		return &decl{name: name, doc: s.Description, underlying: "interface{}"}, nil
+	}
+}
+
+// object returns the declaration of an object schema found at path: a struct
+// if it declares properties, a map otherwise
+func (g *generator) object(name, path string, s *Schema) (*decl, error) {
+	d := &decl{name: name, doc: s.Description}
+	if s.Properties == nil {
+		value, err := g.valueType(s.AdditionalProperties, name+"Value", path+"/additionalProperties", d)
+		if err != nil {
+			return nil, err
+		}
+		d.underlying = "map[string]" + value
+		return d, nil
+	}
+
+	d.kind = kindStruct
+	d.additional = s.AdditionalProperties != nil && !s.AdditionalProperties.never
+	properties := make([]string, 0, len(s.Properties))
+	for property := range s.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	for _, property := range properties {
+		p := s.Properties[property]
+		f := &field{
+			name:     goName(property),
+			property: property,
+			doc:      p.Description,
+			required: s.isRequired(property),
+			since:    g.since(path, property),
+			minimum:  p.Minimum,
+			maximum:  p.Maximum,
+		}
+		ft, err := g.fieldType(name, property, path+"/properties/"+property, p, d)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", property, err)
+		}
+		f.typ, f.union, f.list = ft.expr, ft.union, ft.list
+		if !f.required && ft.pointer {
+			f.typ = "*" + f.typ
+			f.pointer = true
+		}
+		d.fields = append(d.fields, f)
+	}
+	return d, nil
+}
+
+func (g *generator) fieldType(owner, property, path string, s *Schema, d *decl) (fieldType, error) {
+	if t, ok := fieldTypes[owner+"."+property]; ok {
+		return fieldType{expr: t.name, pointer: t.pointer}, nil
+	}
+	return g.typeOf(s, owner+goName(property), path, d)
+}
+
+// since returns the revision that added a property to the object at path, by
+// looking for the earliest revision from which the property is always
+// defined. Properties of objects new in that revision are not tagged: the
+// fields referring to the object are.
+func (g *generator) since(path, property string) string {
+	for i := len(g.revisions) - 1; i > 0; i-- {
+		previous := g.revisions[i-1].schema
+		if previous.lookup(path+"/properties/"+property) != nil {
+			continue
+		}
+		if previous.lookup(path) != nil {
+			return g.revisions[i].version
+		}
+		return ""
+	}
+	return ""
+}
+
+// typeOf returns the Go type of the schema found at path. Objects and enums
+// declared inline become types with the given name, nested in owner.
+func (g *generator) typeOf(s *Schema, name, path string, owner *decl) (fieldType, error) {
+	if s.Ref != "" {
+		return g.refType(s.Ref)
+	}
+	if len(s.AnyOf) > 0 {
+		if u := matchUnion(s.AnyOf); u != nil {
+			return fieldType{expr: u.name, union: u}, nil
+		}
+		return fieldType{expr: "interface{}"}, nil
+	}
+
+	switch s.Type.single() {
+	case "string":
+		if len(s.Enum) > 0 && owner != nil {
+			owner.nested = append(owner.nested, &decl{name: name, doc: s.Description, kind: kindEnum, enum: s.Enum})
+			return fieldType{expr: name, pointer: true}, nil
+		}
+		return fieldType{expr: "string", pointer: true}, nil
+	case "integer":
+		return fieldType{expr: "int", pointer: true}, nil
+	case "number":
+		return fieldType{expr: "float64", pointer: true}, nil
+	case "boolean":
+		// Without a default, false and absent mean the same
+		return fieldType{expr: "bool", pointer: len(s.Default) > 0}, nil
+	case "array":
+		if s.Items == nil {
+			return fieldType{expr: "[]interface{}"}, nil
+		}
+		item, err := g.typeOf(s.Items, name+"Elem", path+"/items", owner)
+		if err != nil {
+			return fieldType{}, err
+		}
+		return fieldType{expr: "[]" + item.expr, union: item.union, list: item.union != nil}, nil
+	case "object":
+		if owner == nil {
+			return fieldType{}, fmt.Errorf("inline object %s has no enclosing type", name)
+		}
+		d, err := g.object(name, path, s)
+		if err != nil {
+			return fieldType{}, err
+		}
+		owner.nested = append(owner.nested, d)
+		return fieldType{expr: name, pointer: d.kind == kindStruct}, nil
+	}
+	return fieldType{expr: "interface{}"}, nil
+}
+
+// valueType returns the type of the values of a map. Maps of maps are not
+// named.
+func (g *generator) valueType(s *Schema, name, path string, owner *decl) (string, error) {
+	if s == nil {
+		return "interface{}", nil
+	}
+	if s.Type.single() == "object" && s.Properties == nil && s.Ref == "" {
+		value, err := g.valueType(s.AdditionalProperties, name+"Value", path+"/additionalProperties", owner)
+		return "map[string]" + value, err
+	}
+	ft, err := g.typeOf(s, name, path, owner)
+	return ft.expr, err
+}
+
+func (g *generator) refType(ref string) (fieldType, error) {
+	definition := strings.TrimPrefix(ref, "#/definitions/")
+	if t, ok := handWritten[definition]; ok {
+		return fieldType{expr: t.name, pointer: t.pointer}, nil
+	}
+	for i := range unions {
+		if unions[i].definition == definition {
+			return fieldType{expr: unions[i].name, union: &unions[i]}, nil
+		}
+	}
+
+	target, name, err := g.schema.resolve(ref)
+	if err != nil {
+		return fieldType{}, err
+	}
+	switch t := target.Type.single(); {
+	case t == "object":
+		return fieldType{expr: name, pointer: target.Properties != nil}, nil
+	case t == "array" || t == "":
+		return fieldType{expr: name}, nil
+	default:
+		return fieldType{expr: name, pointer: true}, nil
+	}
+}
+
+// matchUnion returns the union whose alternatives include all the given
+// schemas, which must be references
+func matchUnion(alternatives []*Schema) *union {
+	for i := range unions {
+		u := &unions[i]
+		matches := true
+		for _, s := range alternatives {
+			name := strings.TrimPrefix(s.Ref, "#/definitions/")
+			if s.Ref == "" || !contains(u.alternatives, name) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return u
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// goName returns the exported Go name of a JSON name, such as Meta for _meta
+func goName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// writeDecl writes a declaration, its nested declarations and its methods. It
+// reports whether the code uses package reflect.
+func writeDecl(w *bytes.Buffer, d *decl) bool {
+	usesReflect := d.kind == kindEnum
+	w.WriteString("\n")
+	writeComment(w, "", d.doc, 80)
+	switch d.kind {
+	case kindType:
+		fmt.Fprintf(w, "type %s %s\n", d.name, d.underlying)
+	case kindStruct:
+		writeStruct(w, d)
+	case kindEnum:
+		writeEnum(w, d)
+	}
+	for _, n := range d.nested {
+		usesReflect = writeDecl(w, n) || usesReflect
+	}
+	if d.kind == kindStruct {
+		writeUnmarshal(w, d)
+	}
+	return usesReflect
+}
+
+func writeStruct(w *bytes.Buffer, d *decl) {
+	if len(d.fields) == 0 && !d.additional {
+		fmt.Fprintf(w, "type %s struct{}\n", d.name)
+		return
+	}
+	fmt.Fprintf(w, "type %s struct {\n", d.name)
+	for i, f := range d.fields {
+		if i > 0 {
+			w.WriteString("\n")
+		}
+		doc := f.doc
+		if doc == "" {
+			doc = fmt.Sprintf("%s corresponds to the JSON schema field %q.", f.name, f.property)
+		}
+		writeComment(w, "\t", doc, 79)
+		tag := f.property
+		if !f.required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(w, "\t%s %s `json:\"%s\" yaml:\"%s\" mapstructure:\"%s\"", f.name, f.typ, tag, tag, tag)
+		if f.since != "" {
+			fmt.Fprintf(w, " mcp:\"since=%s\"", f.since)
+		}
+		w.WriteString("`\n")
+	}
+	if d.additional {
+		w.WriteString("\n\tAdditionalProperties interface{} `mapstructure:\",remain\"`\n")
+	}
+	w.WriteString("}\n")
+}
+
+func writeEnum(w *bytes.Buffer, d *decl) {
+	fmt.Fprintf(w, "type %s string\n\n", d.name)
+
+	values := append([]string(nil), d.enum...)
+	sort.Slice(values, func(i, j int) bool { return goName(values[i]) < goName(values[j]) })
+	for _, v := range values {
+		fmt.Fprintf(w, "const %s%s %s = %q\n", d.name, goName(v), d.name, v)
+	}
+
+	fmt.Fprintf(w, "\nvar enumValues_%s = []interface{}{\n", d.name)
+	for _, v := range d.enum {
+		fmt.Fprintf(w, "\t%q,\n", v)
+	}
+	w.WriteString("}\n\n")
+
+	fmt.Fprintf(w, `// UnmarshalJSON implements json.Unmarshaler.
+func (j *%[1]s) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_%[1]s {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %%#v): %%#v", enumValues_%[1]s, v)
+	}
+	*j = %[1]s(v)
+	return nil
+}
+`, d.name)
+}
+
+// writeUnmarshal writes the UnmarshalJSON method of a struct checking its
+// required fields and bounds and decoding its unions, if it has any
+func writeUnmarshal(w *bytes.Buffer, d *decl) {
+	var required, unions, bounded []*field
+	for _, f := range d.fields {
+		if f.required {
+			required = append(required, f)
+		}
+		if f.union != nil {
+			unions = append(unions, f)
+		}
+		if f.minimum != nil || f.maximum != nil {
+			bounded = append(bounded, f)
+		}
+	}
+	if len(required) == 0 && len(unions) == 0 && len(bounded) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n// UnmarshalJSON implements json.Unmarshaler.\nfunc (j *%s) UnmarshalJSON(b []byte) error {\n", d.name)
+	if len(required) > 0 {
+		w.WriteString("\tvar raw map[string]interface{}\n\tif err := json.Unmarshal(b, &raw); err != nil {\n\t\treturn err\n\t}\n")
+		for _, f := range required {
+			fmt.Fprintf(w, "\tif _, ok := raw[%q]; !ok {\n\t\treturn fmt.Errorf(\"field %s in %s: required\")\n\t}\n", f.property, f.property, d.name)
+		}
+	}
+
+	fmt.Fprintf(w, "\ttype Plain %s\n", d.name)
+	result := "plain"
+	if len(unions) == 0 {
+		w.WriteString("\tvar plain Plain\n")
+	} else {
+		result = "plain.Plain"
+		w.WriteString("\tvar plain struct {\n\t\tPlain\n")
+		for _, f := range unions {
+			raw := "json.RawMessage"
+			if f.list {
+				raw = "[]json.RawMessage"
+			}
+			fmt.Fprintf(w, "\t\t%s %s `json:%q`\n", f.name, raw, f.property)
+		}
+		w.WriteString("\t}\n")
+	}
+	w.WriteString("\tif err := json.Unmarshal(b, &plain); err != nil {\n\t\treturn err\n\t}\n")
+
+	for _, f := range unions {
+		v := localName(f.name)
+		if f.list {
+			fmt.Fprintf(w, "\t%s, err := %s(plain.%s)\n", v, f.union.decodeList, f.name)
+			fmt.Fprintf(w, "\tif err != nil {\n\t\treturn fmt.Errorf(\"field %s in %s: %%w\", err)\n\t}\n", f.property, d.name)
+			fmt.Fprintf(w, "\tplain.Plain.%s = %s\n", f.name, v)
+			continue
+		}
+		fmt.Fprintf(w, "\tif !isNull(plain.%s) {\n", f.name)
+		fmt.Fprintf(w, "\t\t%s, err := %s(plain.%s)\n", v, f.union.decode, f.name)
+		fmt.Fprintf(w, "\t\tif err != nil {\n\t\t\treturn fmt.Errorf(\"field %s in %s: %%w\", err)\n\t\t}\n", f.property, d.name)
+		fmt.Fprintf(w, "\t\tplain.Plain.%s = %s\n\t}\n", f.name, v)
+	}
+
+	for _, f := range bounded {
+		value, guard := "plain."+f.name, ""
+		if f.pointer {
+			value, guard = "*plain."+f.name, "plain."+f.name+" != nil && "
+		}
+		if f.maximum != nil {
+			limit := formatNumber(*f.maximum)
+			fmt.Fprintf(w, "\tif %s%s < %s {\n\t\treturn fmt.Errorf(\"field %%s: must be <= %%v\", %q, %s)\n\t}\n", guard, limit, value, f.property, limit)
+		}
+		if f.minimum != nil {
+			limit := formatNumber(*f.minimum)
+			fmt.Fprintf(w, "\tif %s%s > %s {\n\t\treturn fmt.Errorf(\"field %%s: must be >= %%v\", %q, %s)\n\t}\n", guard, limit, value, f.property, limit)
+		}
+	}
+
+	fmt.Fprintf(w, "\t*j = %s(%s)\n\treturn nil\n}\n", d.name, result)
+}
+
+// localName returns the name of a local variable holding a field
+func localName(field string) string {
+	r := []rune(field)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// writeComment writes text as a line comment wrapped at the given width, not
+// counting the indentation and comment marker
+func writeComment(w *bytes.Buffer, indent, text string, width int) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" {
+			fmt.Fprintf(w, "%s//\n", indent)
+			continue
+		}
+		for _, l := range wrap(line, width) {
+			fmt.Fprintf(w, "%s// %s\n", indent, l)
+		}
+	}
+}
+
+// wrap splits a line at spaces into lines no longer than width, where
+// possible
+func wrap(line string, width int) []string {
+	words := strings.Split(line, " ")
+	var lines []string
+	current := words[0]
+	for _, word := range words[1:] {
+		if len(current)+1+len(word) > width && strings.TrimSpace(current) != "" {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current += " " + word
+	}
+	return append(lines, current)
+}
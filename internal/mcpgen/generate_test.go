@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func parseRevisions(t *testing.T, schemas ...string) []revision {
+	t.Helper()
+	versions := []string{"2024-11-05", "2025-03-26", "2025-06-18"}
+	var revisions []revision
+	for i, s := range schemas {
+		var schema Schema
+		if err := json.Unmarshal([]byte(s), &schema); err != nil {
+			t.Fatal(err)
+		}
+		revisions = append(revisions, revision{version: versions[i], schema: &schema})
+	}
+	return revisions
+}
+
+func TestSince(t *testing.T) {
+	g := &generator{revisions: parseRevisions(t,
+		`{"definitions": {
+			"Tool": {"type": "object", "properties": {"name": {"type": "string"}, "removed": {"type": "string"}}},
+			"Note": {"type": "object", "properties": {"params": {"type": "object", "properties": {"progress": {"type": "number"}}}}}
+		}}`,
+		`{"definitions": {
+			"Tool": {"type": "object", "properties": {"name": {"type": "string"}, "annotations": {"type": "object"}}},
+			"Note": {"type": "object", "properties": {"params": {"type": "object", "properties": {"progress": {"type": "number"}, "message": {"type": "string"}}}}},
+			"Audio": {"type": "object", "properties": {"data": {"type": "string"}}}
+		}}`,
+		`{"definitions": {
+			"Tool": {"type": "object", "properties": {"name": {"type": "string"}, "annotations": {"type": "object"}, "removed": {"type": "string"}, "title": {"type": "string"}}},
+			"Note": {"type": "object", "properties": {"params": {"type": "object", "properties": {"progress": {"type": "number"}, "message": {"type": "string"}}}}},
+			"Audio": {"type": "object", "properties": {"data": {"type": "string"}, "_meta": {"type": "object"}}},
+			"Link": {"type": "object", "properties": {"uri": {"type": "string"}}}
+		}}`,
+	)}
+
+	tests := []struct {
+		path, property, want string
+	}{
+		{"definitions/Tool", "name", ""},
+		{"definitions/Tool", "annotations", "2025-03-26"},
+		{"definitions/Tool", "title", "2025-06-18"},
+		// Dropped by a revision in between, so only defined since the latest
+		{"definitions/Tool", "removed", "2025-06-18"},
+		{"definitions/Note/properties/params", "progress", ""},
+		{"definitions/Note/properties/params", "message", "2025-03-26"},
+		// New objects are not tagged, but properties added to them later are
+		{"definitions/Audio", "data", ""},
+		{"definitions/Audio", "_meta", "2025-06-18"},
+		{"definitions/Link", "uri", ""},
+	}
+	for _, tt := range tests {
+		if got := g.since(tt.path, tt.property); got != tt.want {
+			t.Errorf("since(%s, %s) = %q, want %q", tt.path, tt.property, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateTagsFields(t *testing.T) {
+	revisions := parseRevisions(t,
+		`{"definitions": {"Tool": {"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}}}`,
+		`{"definitions": {"Tool": {"type": "object", "properties": {"name": {"type": "string"}, "title": {"type": "string"}}, "required": ["name"]}}}`,
+	)
+	src, err := generate(revisions, "mcp", "schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Name string `json:\"name\" yaml:\"name\" mapstructure:\"name\"`",
+		"Title *string `json:\"title,omitempty\" yaml:\"title,omitempty\" mapstructure:\"title,omitempty\" mcp:\"since=2025-03-26\"`",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code does not contain %s:\n%s", want, src)
+		}
+	}
+}
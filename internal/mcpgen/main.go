@@ -0,0 +1,72 @@
+// Command mcpgen generates the protocol types of package mcp from the JSON
+// schemas of the MCP specification. It is run by go generate in package mcp:
+//
+//	go run ../internal/mcpgen -schema schema -out types.go
+//
+// The schema directory holds the schema.json of every supported revision of
+// the protocol, in a directory named after the revision. The types are
+// generated from the latest revision, and the fields it added to earlier
+// objects are tagged with the revision that introduced them, found by
+// comparing the schemas.
+//
+// Definitions that need custom JSON handling, such as request IDs and the
+// content unions, are implemented by hand in package mcp and listed in
+// config.go; the generated code refers to them instead of declaring them.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+func main() {
+	schemaDir := flag.String("schema", "schema", "directory of the JSON schemas of each revision")
+	out := flag.String("out", "types.go", "path of the generated Go file")
+	pkg := flag.String("package", "mcp", "package of the generated Go file")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("mcpgen: ")
+	if err := run(*schemaDir, *out, *pkg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(schemaDir, out, pkg string) error {
+	paths, err := filepath.Glob(filepath.Join(schemaDir, "*", "schema.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list schemas: %w", err)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no schema found in %s", schemaDir)
+	}
+	// Revisions are dates, so they sort in order
+	sort.Strings(paths)
+
+	revisions := make([]revision, 0, len(paths))
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read schema: %w", err)
+		}
+		var schema Schema
+		if err := json.Unmarshal(b, &schema); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		revisions = append(revisions, revision{version: filepath.Base(filepath.Dir(path)), schema: &schema})
+	}
+
+	src, err := generate(revisions, pkg, filepath.ToSlash(paths[len(paths)-1]))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	return nil
+}
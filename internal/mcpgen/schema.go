@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema (draft-07) used by the MCP schema
+type Schema struct {
+	Ref         string             `json:"$ref"`
+	Description string             `json:"description"`
+	Type        typeList           `json:"type"`
+	Const       interface{}        `json:"const"`
+	Enum        []string           `json:"enum"`
+	Default     json.RawMessage    `json:"default"`
+	Minimum     *float64           `json:"minimum"`
+	Maximum     *float64           `json:"maximum"`
+	Properties  map[string]*Schema `json:"properties"`
+	Required    []string           `json:"required"`
+	Items       *Schema            `json:"items"`
+	AnyOf       []*Schema          `json:"anyOf"`
+
+	// AdditionalProperties is nil if the keyword is absent
+	AdditionalProperties *Schema `json:"additionalProperties"`
+
+	Definitions map[string]*Schema `json:"definitions"`
+
+	// never is set for the boolean schema false
+	never bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting boolean schemas.
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	switch string(bytes.TrimSpace(b)) {
+	case "true":
+		*s = Schema{}
+		return nil
+	case "false":
+		*s = Schema{never: true}
+		return nil
+	}
+	type Plain Schema
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*s = Schema(plain)
+	return nil
+}
+
+func (s *Schema) isRequired(property string) bool {
+	for _, name := range s.Required {
+		if name == property {
+			return true
+		}
+	}
+	return false
+}
+
+// typeList is the value of the type keyword, either a single type name or a
+// list of them
+type typeList []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *typeList) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*t = typeList{name}
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(b, &names); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings: %s", b)
+	}
+	*t = names
+	return nil
+}
+
+// single returns the type name if exactly one is given
+func (t typeList) single() string {
+	if len(t) == 1 {
+		return t[0]
+	}
+	return ""
+}
+
+// resolve returns the schema designated by a $ref of the form
+// #/definitions/<name>[/properties/<name>...], along with the name of the Go
+// type generated for it
+func (s *Schema) resolve(ref string) (*Schema, string, error) {
+	parts := strings.Split(strings.TrimPrefix(ref, "#/"), "/")
+	if len(parts) < 2 || parts[0] != "definitions" || len(parts)%2 != 0 {
+		return nil, "", fmt.Errorf("unsupported reference %q", ref)
+	}
+	target, ok := s.Definitions[parts[1]]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown definition in reference %q", ref)
+	}
+	name := parts[1]
+	for i := 2; i < len(parts); i += 2 {
+		if parts[i] != "properties" {
+			return nil, "", fmt.Errorf("unsupported reference %q", ref)
+		}
+		if target, ok = target.Properties[parts[i+1]]; !ok {
+			return nil, "", fmt.Errorf("unknown property in reference %q", ref)
+		}
+		name += goName(parts[i+1])
+	}
+	return target, name, nil
+}
+
+// lookup returns the schema at a path of keywords and names below s, such as
+// definitions/Tool/properties/title, or nil if there is none. References are
+// not followed.
+func (s *Schema) lookup(path string) *Schema {
+	parts := strings.Split(path, "/")
+	for i := 0; s != nil && i < len(parts); i++ {
+		switch parts[i] {
+		case "items":
+			s = s.Items
+			continue
+		case "additionalProperties":
+			s = s.AdditionalProperties
+			continue
+		}
+		if i+1 == len(parts) {
+			return nil
+		}
+		switch parts[i] {
+		case "definitions":
+			s = s.Definitions[parts[i+1]]
+		case "properties":
+			s = s.Properties[parts[i+1]]
+		default:
+			return nil
+		}
+		i++
+	}
+	return s
+}
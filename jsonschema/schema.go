@@ -0,0 +1,379 @@
+// Package jsonschema implements validation against the subset of JSON Schema
+// (draft 2020-12) that MCP servers use to describe tool inputs and outputs:
+// types, enum and const, numeric and length bounds, patterns, nested objects
+// and arrays, the allOf, anyOf, oneOf and not combinators, and $ref to
+// subschemas of the same document, such as those under $defs. Annotation
+// keywords such as title, description, default and format are accepted and
+// ignored, and so are patterns that RE2 cannot compile.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	// never is set for the boolean schema false, which rejects every instance
+	never bool
+
+	types    []string
+	enum     []interface{}
+	constant *interface{}
+
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	minProperties        *int
+	maxProperties        *int
+
+	items       *Schema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+
+	// ref is the schema named by $ref, resolved once the whole document is
+	// compiled
+	ref        *Schema
+	refPointer string
+}
+
+var validTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"number":  true,
+	"integer": true,
+	"string":  true,
+}
+
+// Compile parses a JSON Schema document
+func Compile(schema []byte) (*Schema, error) {
+	doc := &document{schemas: make(map[string]*Schema)}
+	s, err := doc.compile(schema, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.resolve(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MustCompile is like Compile but panics if the schema cannot be parsed
+func MustCompile(schema []byte) *Schema {
+	s, err := Compile(schema)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// document holds every schema of a document by JSON Pointer, so that $ref can
+// be resolved after compilation, including references to enclosing schemas
+type document struct {
+	schemas map[string]*Schema
+	refs    []*Schema
+}
+
+func (d *document) compile(b []byte, path string) (*Schema, error) {
+	s, err := d.compileSchema(b, path)
+	if err != nil {
+		return nil, err
+	}
+	d.schemas[path] = s
+	return s, nil
+}
+
+func (d *document) compileSchema(b []byte, path string) (*Schema, error) {
+	b = bytes.TrimSpace(b)
+	switch string(b) {
+	case "true":
+		return &Schema{}, nil
+	case "false":
+		return &Schema{never: true}, nil
+	}
+
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(b, &keywords); err != nil {
+		return nil, fmt.Errorf("schema %s: must be an object or a boolean", pointer(path))
+	}
+
+	s := &Schema{}
+	c := compiler{doc: d, target: s, keywords: keywords, path: path}
+	if raw, ok := keywords["$ref"]; ok {
+		var ref string
+		if c.decode("$ref", raw, &ref) {
+			if !strings.HasPrefix(ref, "#") {
+				c.fail("$ref", fmt.Errorf("only references within the same document are supported, got %q", ref))
+			}
+			s.refPointer = strings.TrimPrefix(ref, "#")
+			d.refs = append(d.refs, s)
+		}
+	}
+	c.schemaMap("$defs")
+	c.schemaMap("definitions")
+
+	c.types("type")
+	c.any("enum", &s.enum)
+	if raw, ok := keywords["const"]; ok {
+		var v interface{}
+		c.decode("const", raw, &v)
+		s.constant = &v
+	}
+
+	s.properties = c.schemaMap("properties")
+	c.any("required", &s.required)
+	s.additionalProperties = c.schema("additionalProperties")
+	s.minProperties = c.integer("minProperties")
+	s.maxProperties = c.integer("maxProperties")
+
+	s.items = c.schema("items")
+	s.minItems = c.integer("minItems")
+	s.maxItems = c.integer("maxItems")
+	c.any("uniqueItems", &s.uniqueItems)
+
+	s.minimum = c.number("minimum")
+	s.maximum = c.number("maximum")
+	s.exclusiveMinimum = c.number("exclusiveMinimum")
+	s.exclusiveMaximum = c.number("exclusiveMaximum")
+	s.multipleOf = c.number("multipleOf")
+
+	s.minLength = c.integer("minLength")
+	s.maxLength = c.integer("maxLength")
+	if raw, ok := keywords["pattern"]; ok {
+		var pattern string
+		if c.decode("pattern", raw, &pattern) {
+			// Patterns are ECMA-262 regular expressions. Those using features
+			// RE2 lacks, such as lookarounds and backreferences, are not
+			// checked rather than making the whole schema unusable.
+			if re, err := regexp.Compile(pattern); err == nil {
+				s.pattern = re
+			}
+		}
+	}
+
+	s.allOf = c.schemaList("allOf")
+	s.anyOf = c.schemaList("anyOf")
+	s.oneOf = c.schemaList("oneOf")
+	s.not = c.schema("not")
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	return s, nil
+}
+
+// resolve links every $ref to its target and rejects references that would
+// make validation loop, applying schemas to the same value forever
+func (d *document) resolve() error {
+	for _, s := range d.refs {
+		target, ok := d.schemas[s.refPointer]
+		if !ok {
+			return fmt.Errorf("schema: unresolved $ref %q", pointer(s.refPointer))
+		}
+		s.ref = target
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[*Schema]int)
+	var visit func(s *Schema) bool
+	visit = func(s *Schema) bool {
+		switch state[s] {
+		case visiting:
+			return false
+		case visited:
+			return true
+		}
+		state[s] = visiting
+		for _, next := range s.inPlace() {
+			if !visit(next) {
+				return false
+			}
+		}
+		state[s] = visited
+		return true
+	}
+	for _, s := range d.refs {
+		if !visit(s) {
+			return fmt.Errorf("schema: circular $ref %q", pointer(s.refPointer))
+		}
+	}
+	return nil
+}
+
+// inPlace returns the subschemas applied to the same value as s itself
+func (s *Schema) inPlace() []*Schema {
+	var schemas []*Schema
+	if s.ref != nil {
+		schemas = append(schemas, s.ref)
+	}
+	schemas = append(schemas, s.allOf...)
+	schemas = append(schemas, s.anyOf...)
+	schemas = append(schemas, s.oneOf...)
+	if s.not != nil {
+		schemas = append(schemas, s.not)
+	}
+	return schemas
+}
+
+// compiler collects the first error encountered while compiling one schema object
+type compiler struct {
+	doc      *document
+	target   *Schema
+	keywords map[string]json.RawMessage
+	path     string
+	err      error
+}
+
+func (c *compiler) fail(keyword string, err error) {
+	if c.err == nil {
+		c.err = fmt.Errorf("schema %s: keyword %s: %w", pointer(c.path), keyword, err)
+	}
+}
+
+func (c *compiler) decode(keyword string, raw json.RawMessage, v interface{}) bool {
+	if err := json.Unmarshal(raw, v); err != nil {
+		c.fail(keyword, err)
+		return false
+	}
+	return true
+}
+
+func (c *compiler) any(keyword string, v interface{}) {
+	if raw, ok := c.keywords[keyword]; ok {
+		c.decode(keyword, raw, v)
+	}
+}
+
+func (c *compiler) types(keyword string) {
+	raw, ok := c.keywords[keyword]
+	if !ok {
+		return
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		c.target.types = []string{single}
+	} else if !c.decode(keyword, raw, &c.target.types) {
+		return
+	}
+	for _, t := range c.target.types {
+		if !validTypes[t] {
+			c.fail(keyword, fmt.Errorf("unknown type %q", t))
+		}
+	}
+}
+
+func (c *compiler) number(keyword string) *float64 {
+	raw, ok := c.keywords[keyword]
+	if !ok {
+		return nil
+	}
+	var n float64
+	if !c.decode(keyword, raw, &n) {
+		return nil
+	}
+	return &n
+}
+
+func (c *compiler) integer(keyword string) *int {
+	raw, ok := c.keywords[keyword]
+	if !ok {
+		return nil
+	}
+	var n int
+	if !c.decode(keyword, raw, &n) {
+		return nil
+	}
+	return &n
+}
+
+func (c *compiler) schema(keyword string) *Schema {
+	raw, ok := c.keywords[keyword]
+	if !ok {
+		return nil
+	}
+	s, err := c.doc.compile(raw, c.path+"/"+keyword)
+	if err != nil {
+		if c.err == nil {
+			c.err = err
+		}
+		return nil
+	}
+	return s
+}
+
+func (c *compiler) schemaList(keyword string) []*Schema {
+	raw, ok := c.keywords[keyword]
+	if !ok {
+		return nil
+	}
+	var list []json.RawMessage
+	if !c.decode(keyword, raw, &list) {
+		return nil
+	}
+	schemas := make([]*Schema, 0, len(list))
+	for i, item := range list {
+		s, err := c.doc.compile(item, fmt.Sprintf("%s/%s/%d", c.path, keyword, i))
+		if err != nil {
+			if c.err == nil {
+				c.err = err
+			}
+			return nil
+		}
+		schemas = append(schemas, s)
+	}
+	return schemas
+}
+
+func (c *compiler) schemaMap(keyword string) map[string]*Schema {
+	raw, ok := c.keywords[keyword]
+	if !ok {
+		return nil
+	}
+	var m map[string]json.RawMessage
+	if !c.decode(keyword, raw, &m) {
+		return nil
+	}
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	schemas := make(map[string]*Schema, len(m))
+	for _, name := range names {
+		s, err := c.doc.compile(m[name], c.path+"/"+keyword+"/"+escape(name))
+		if err != nil {
+			if c.err == nil {
+				c.err = err
+			}
+			return nil
+		}
+		schemas[name] = s
+	}
+	return schemas
+}
@@ -0,0 +1,70 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		err    string
+	}{
+		{"not a schema", `1`, "schema #: must be an object or a boolean"},
+		{"malformed", `{`, "schema #: must be an object or a boolean"},
+		{"unknown type", `{"type": "text"}`, `schema #: keyword type: unknown type "text"`},
+		{"type not a string", `{"type": 1}`, "schema #: keyword type:"},
+		{"required not a list", `{"required": "a"}`, "schema #: keyword required:"},
+		{"bound not a number", `{"minimum": "1"}`, "schema #: keyword minimum:"},
+		{"length not an integer", `{"maxLength": 1.5}`, "schema #: keyword maxLength:"},
+		{"nested error path", `{"properties": {"a": {"items": {"type": "text"}}}}`, `schema #/properties/a/items: keyword type: unknown type "text"`},
+		{"list error path", `{"anyOf": [{}, {"type": 2}]}`, "schema #/anyOf/1: keyword type:"},
+		{"external $ref", `{"$ref": "https://example.com/schema"}`, "only references within the same document are supported"},
+		{"unresolved $ref", `{"$ref": "#/$defs/missing"}`, `schema: unresolved $ref "#/$defs/missing"`},
+		{"circular $ref", `{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"allOf": [{"$ref": "#/$defs/a"}]}}, "$ref": "#/$defs/a"}`, "schema: circular $ref"},
+		{"self $ref", `{"$ref": "#"}`, `schema: circular $ref "#"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile([]byte(tt.schema))
+			if err == nil {
+				t.Fatalf("Compile(%s) succeeded", tt.schema)
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Compile(%s) = %q, want it to contain %q", tt.schema, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCompileRecursiveRef(t *testing.T) {
+	// References through properties apply to nested values, so they do not loop
+	schema := `{"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}}}}, "$ref": "#/$defs/node"}`
+	if _, err := Compile([]byte(schema)); err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+}
+
+func TestMustCompilePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustCompile did not panic on an invalid schema")
+		}
+	}()
+	MustCompile([]byte(`{"type": "text"}`))
+}
+
+func TestCompileUnsupportedPattern(t *testing.T) {
+	// Lookarounds are valid ECMA-262 but not RE2, so the pattern is not checked
+	schema, err := Compile([]byte(`{"type": "string", "pattern": "^(?!admin)", "minLength": 2}`))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if err := schema.Validate("admin"); err != nil {
+		t.Errorf("unsupported pattern was checked: %v", err)
+	}
+	if err := schema.Validate("a"); err == nil {
+		t.Error("other keywords of the schema were not checked")
+	}
+}
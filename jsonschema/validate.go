@@ -0,0 +1,319 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError describes a single way in which an instance violates a schema
+type ValidationError struct {
+	// Path is the JSON Pointer of the offending value within the instance,
+	// empty for the instance itself
+	Path string
+
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors lists every violation found while validating an instance
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validate checks instance against the schema. The instance must be made of the
+// values produced by encoding/json when decoding into an interface{}: nil,
+// bool, float64, string, []interface{} and map[string]interface{}. It returns
+// nil if the instance is valid and ValidationErrors otherwise.
+func (s *Schema) Validate(instance interface{}) error {
+	var errs ValidationErrors
+	s.validate(instance, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ValidateJSON decodes data and checks it against the schema
+func (s *Schema) ValidateJSON(data []byte) error {
+	var instance interface{}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return fmt.Errorf("failed to parse instance: %w", err)
+	}
+	return s.Validate(instance)
+}
+
+func (s *Schema) validate(v interface{}, path string, errs *ValidationErrors) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.never {
+		fail("no value is allowed here")
+		return
+	}
+
+	if s.ref != nil {
+		s.ref.validate(v, path, errs)
+	}
+
+	if len(s.types) > 0 && !s.matchesType(v) {
+		fail("expected %s, got %s", strings.Join(s.types, " or "), typeOf(v))
+		return
+	}
+
+	if s.enum != nil {
+		found := false
+		for _, e := range s.enum {
+			if equal(v, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %s", encode(s.enum))
+		}
+	}
+	if s.constant != nil && !equal(v, *s.constant) {
+		fail("must be %s", encode(*s.constant))
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		s.validateObject(v, path, errs)
+	case []interface{}:
+		s.validateArray(v, path, errs)
+	case float64:
+		s.validateNumber(v, fail)
+	case string:
+		s.validateString(v, fail)
+	}
+
+	for _, sub := range s.allOf {
+		sub.validate(v, path, errs)
+	}
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.matches(v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("must match at least one of the anyOf schemas")
+		}
+	}
+	if len(s.oneOf) > 0 {
+		matches := 0
+		for _, sub := range s.oneOf {
+			if sub.matches(v) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			fail("must match exactly one of the oneOf schemas, matched %d", matches)
+		}
+	}
+	if s.not != nil && s.not.matches(v) {
+		fail("must not match the not schema")
+	}
+}
+
+func (s *Schema) matches(v interface{}) bool {
+	var errs ValidationErrors
+	s.validate(v, "", &errs)
+	return len(errs) == 0
+}
+
+func (s *Schema) matchesType(v interface{}) bool {
+	actual := typeOf(v)
+	for _, t := range s.types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, path string, errs *ValidationErrors) {
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, &ValidationError{
+				Path:    path + "/" + escape(name),
+				Message: "is required",
+			})
+		}
+	}
+	if s.minProperties != nil && len(obj) < *s.minProperties {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf("must have at least %d properties", *s.minProperties)})
+	}
+	if s.maxProperties != nil && len(obj) > *s.maxProperties {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf("must have at most %d properties", *s.maxProperties)})
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyPath := path + "/" + escape(name)
+		if sub, ok := s.properties[name]; ok {
+			sub.validate(obj[name], propertyPath, errs)
+		} else if s.additionalProperties != nil {
+			if s.additionalProperties.never {
+				*errs = append(*errs, &ValidationError{Path: propertyPath, Message: "is not allowed"})
+			} else {
+				s.additionalProperties.validate(obj[name], propertyPath, errs)
+			}
+		}
+	}
+}
+
+func (s *Schema) validateArray(arr []interface{}, path string, errs *ValidationErrors) {
+	if s.minItems != nil && len(arr) < *s.minItems {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf("must have at least %d items", *s.minItems)})
+	}
+	if s.maxItems != nil && len(arr) > *s.maxItems {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf("must have at most %d items", *s.maxItems)})
+	}
+	if s.uniqueItems {
+	outer:
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if equal(arr[i], arr[j]) {
+					*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf("items %d and %d must be unique", i, j)})
+					break outer
+				}
+			}
+		}
+	}
+	if s.items != nil {
+		for i, item := range arr {
+			s.items.validate(item, fmt.Sprintf("%s/%d", path, i), errs)
+		}
+	}
+}
+
+func (s *Schema) validateNumber(n float64, fail func(string, ...interface{})) {
+	if s.minimum != nil && n < *s.minimum {
+		fail("must be >= %v", *s.minimum)
+	}
+	if s.maximum != nil && n > *s.maximum {
+		fail("must be <= %v", *s.maximum)
+	}
+	if s.exclusiveMinimum != nil && n <= *s.exclusiveMinimum {
+		fail("must be > %v", *s.exclusiveMinimum)
+	}
+	if s.exclusiveMaximum != nil && n >= *s.exclusiveMaximum {
+		fail("must be < %v", *s.exclusiveMaximum)
+	}
+	if s.multipleOf != nil && *s.multipleOf > 0 {
+		if q := n / *s.multipleOf; q != math.Trunc(q) {
+			fail("must be a multiple of %v", *s.multipleOf)
+		}
+	}
+}
+
+func (s *Schema) validateString(str string, fail func(string, ...interface{})) {
+	length := utf8.RuneCountInString(str)
+	if s.minLength != nil && length < *s.minLength {
+		fail("must be at least %d characters long", *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		fail("must be at most %d characters long", *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		fail("must match pattern %q", s.pattern.String())
+	}
+}
+
+func typeOf(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !equal(av, bv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+func encode(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// escape encodes a property name as a JSON Pointer reference token
+func escape(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+func pointer(path string) string {
+	if path == "" {
+		return "#"
+	}
+	return "#" + path
+}
@@ -0,0 +1,126 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		// errors lists the expected violations as "path: message", in order
+		errors []string
+	}{
+		{"true schema", `true`, `{"a": [1, "b"]}`, nil},
+		{"false schema", `false`, `1`, []string{"no value is allowed here"}},
+		{"empty schema", `{}`, `null`, nil},
+
+		{"type string", `{"type": "string"}`, `"a"`, nil},
+		{"type string mismatch", `{"type": "string"}`, `1`, []string{"expected string, got integer"}},
+		{"type integer", `{"type": "integer"}`, `3.0`, nil},
+		{"type integer fraction", `{"type": "integer"}`, `3.5`, []string{"expected integer, got number"}},
+		{"type number accepts integers", `{"type": "number"}`, `3`, nil},
+		{"type boolean", `{"type": "boolean"}`, `false`, nil},
+		{"type null", `{"type": "null"}`, `null`, nil},
+		{"type object", `{"type": "object"}`, `[]`, []string{"expected object, got array"}},
+		{"type array", `{"type": "array"}`, `{}`, []string{"expected array, got object"}},
+		{"type list", `{"type": ["string", "null"]}`, `null`, nil},
+		{"type list mismatch", `{"type": ["string", "null"]}`, `true`, []string{"expected string or null, got boolean"}},
+
+		{"enum", `{"enum": ["a", 1, null]}`, `1`, nil},
+		{"enum mismatch", `{"enum": ["a", 1]}`, `"b"`, []string{`must be one of ["a",1]`}},
+		{"enum object", `{"enum": [{"a": [1]}]}`, `{"a": [1]}`, nil},
+		{"const", `{"const": {"a": 1}}`, `{"a": 1}`, nil},
+		{"const mismatch", `{"const": "a"}`, `"b"`, []string{`must be "a"`}},
+		{"const null", `{"const": null}`, `0`, []string{"must be null"}},
+
+		{"properties", `{"properties": {"a": {"type": "string"}}}`, `{"a": 1, "b": 2}`, []string{"/a: expected string, got integer"}},
+		{"properties ignore other types", `{"properties": {"a": {"type": "string"}}}`, `"a"`, nil},
+		{"required", `{"required": ["a", "b"]}`, `{"a": 1}`, []string{"/b: is required"}},
+		{"additionalProperties false", `{"properties": {"a": {}}, "additionalProperties": false}`, `{"a": 1, "b": 2}`, []string{"/b: is not allowed"}},
+		{"additionalProperties schema", `{"additionalProperties": {"type": "integer"}}`, `{"a": 1, "b": "c"}`, []string{"/b: expected integer, got string"}},
+		{"minProperties", `{"minProperties": 2}`, `{"a": 1}`, []string{"must have at least 2 properties"}},
+		{"maxProperties", `{"maxProperties": 1}`, `{"a": 1, "b": 2}`, []string{"must have at most 1 properties"}},
+		{"escaped property path", `{"properties": {"a/b~c": {"type": "string"}}}`, `{"a/b~c": 1}`, []string{"/a~1b~0c: expected string, got integer"}},
+
+		{"items", `{"items": {"type": "string"}}`, `["a", 1, "c", 2]`, []string{"/1: expected string, got integer", "/3: expected string, got integer"}},
+		{"minItems", `{"minItems": 1}`, `[]`, []string{"must have at least 1 items"}},
+		{"maxItems", `{"maxItems": 1}`, `[1, 2]`, []string{"must have at most 1 items"}},
+		{"uniqueItems", `{"uniqueItems": true}`, `[1, {"a": 2}, {"a": 2}]`, []string{"items 1 and 2 must be unique"}},
+		{"uniqueItems distinct", `{"uniqueItems": true}`, `[1, "1", [1]]`, nil},
+		{"nested path", `{"properties": {"a": {"items": {"required": ["b"]}}}}`, `{"a": [{}, {"b": 1}]}`, []string{"/a/0/b: is required"}},
+
+		{"minimum", `{"minimum": 2}`, `1`, []string{"must be >= 2"}},
+		{"minimum inclusive", `{"minimum": 2}`, `2`, nil},
+		{"maximum", `{"maximum": 2}`, `2.5`, []string{"must be <= 2"}},
+		{"exclusiveMinimum", `{"exclusiveMinimum": 2}`, `2`, []string{"must be > 2"}},
+		{"exclusiveMaximum", `{"exclusiveMaximum": 2}`, `2`, []string{"must be < 2"}},
+		{"multipleOf", `{"multipleOf": 0.5}`, `1.5`, nil},
+		{"multipleOf mismatch", `{"multipleOf": 3}`, `7`, []string{"must be a multiple of 3"}},
+
+		{"minLength counts characters", `{"minLength": 3}`, `"éé"`, []string{"must be at least 3 characters long"}},
+		{"maxLength", `{"maxLength": 2}`, `"abc"`, []string{"must be at most 2 characters long"}},
+		{"pattern", `{"pattern": "^[a-z]+$"}`, `"abc"`, nil},
+		{"pattern mismatch", `{"pattern": "^[a-z]+$"}`, `"ab1"`, []string{`must match pattern "^[a-z]+$"`}},
+		{"pattern is unanchored", `{"pattern": "b"}`, `"abc"`, nil},
+
+		{"allOf", `{"allOf": [{"minimum": 1}, {"maximum": 2}]}`, `3`, []string{"must be <= 2"}},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`, `1`, nil},
+		{"anyOf mismatch", `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`, `true`, []string{"must match at least one of the anyOf schemas"}},
+		{"oneOf", `{"oneOf": [{"type": "string"}, {"type": "integer"}]}`, `"a"`, nil},
+		{"oneOf several", `{"oneOf": [{"type": "number"}, {"type": "integer"}]}`, `1`, []string{"must match exactly one of the oneOf schemas, matched 2"}},
+		{"oneOf none", `{"oneOf": [{"type": "string"}]}`, `1`, []string{"must match exactly one of the oneOf schemas, matched 0"}},
+		{"not", `{"not": {"type": "string"}}`, `"a"`, []string{"must not match the not schema"}},
+		{"not mismatch", `{"not": {"type": "string"}}`, `1`, nil},
+
+		{"$ref to $defs", `{"$defs": {"s": {"type": "string"}}, "properties": {"a": {"$ref": "#/$defs/s"}}}`, `{"a": 1}`, []string{"/a: expected string, got integer"}},
+		{"$ref to definitions", `{"definitions": {"s": {"type": "string"}}, "items": {"$ref": "#/definitions/s"}}`, `["a"]`, nil},
+		{"$ref to root", `{"properties": {"child": {"$ref": "#"}, "name": {"type": "string"}}}`, `{"child": {"child": {"name": 1}}}`, []string{"/child/child/name: expected string, got integer"}},
+		{"$ref to escaped name", `{"$defs": {"a/b": {"type": "string"}}, "$ref": "#/$defs/a~1b"}`, `1`, []string{"expected string, got integer"}},
+
+		{"annotations ignored", `{"title": "t", "description": "d", "default": 1, "format": "email", "examples": [1]}`, `"not an email"`, nil},
+		{"several violations", `{"type": "object", "required": ["a"], "properties": {"b": {"type": "string"}}}`, `{"b": 1}`, []string{"/a: is required", "/b: expected string, got integer"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Compile([]byte(tt.schema))
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			err = schema.ValidateJSON([]byte(tt.instance))
+			if len(tt.errors) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateJSON(%s): %v", tt.instance, err)
+				}
+				return
+			}
+
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("ValidateJSON(%s) = %v, want ValidationErrors", tt.instance, err)
+			}
+			got := make([]string, len(errs))
+			for i, e := range errs {
+				got[i] = e.Error()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.errors, "\n") {
+				t.Errorf("ValidateJSON(%s) errors:\n%s\nwant:\n%s", tt.instance, strings.Join(got, "\n"), strings.Join(tt.errors, "\n"))
+			}
+		})
+	}
+}
+
+func TestValidateJSONMalformed(t *testing.T) {
+	schema := MustCompile([]byte(`{}`))
+	err := schema.ValidateJSON([]byte(`{`))
+	if err == nil {
+		t.Fatal("expected an error for malformed JSON")
+	}
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		t.Errorf("malformed JSON reported as validation errors: %v", err)
+	}
+}
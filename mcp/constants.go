@@ -0,0 +1,50 @@
+package mcp
+
+const (
+	JSONRPCVersion = "2.0"
+)
+
+// Standard JSON-RPC error codes
+const (
+	ErrorCodeParseError     = -32700
+	ErrorCodeInvalidRequest = -32600
+	ErrorCodeMethodNotFound = -32601
+	ErrorCodeInvalidParams  = -32602
+	ErrorCodeInternalError  = -32603
+)
+
+// Standard MCP methods
+const (
+	MethodInitialize           = "initialize"
+	MethodPing                 = "ping"
+	MethodResourcesList        = "resources/list"
+	MethodResourcesRead        = "resources/read"
+	MethodResourcesSubscribe   = "resources/subscribe"
+	MethodResourcesUnsubscribe = "resources/unsubscribe"
+	MethodPromptsList          = "prompts/list"
+	MethodPromptsGet           = "prompts/get"
+	MethodToolsList            = "tools/list"
+	MethodToolsCall            = "tools/call"
+	MethodLoggingSetLevel      = "logging/setLevel"
+	MethodCompletionComplete   = "completion/complete"
+)
+
+// Standard MCP methods sent by servers to clients
+const (
+	MethodSamplingCreateMessage = "sampling/createMessage"
+	MethodRootsList             = "roots/list"
+	MethodElicitationCreate     = "elicitation/create"
+)
+
+// Standard MCP notifications
+const (
+	MethodNotificationInitialized          = "notifications/initialized"
+	MethodNotificationCancelled            = "notifications/cancelled"
+	MethodNotificationProgress             = "notifications/progress"
+	MethodNotificationMessage              = "notifications/message"
+	MethodNotificationResourcesListChanged = "notifications/resources/list_changed"
+	MethodNotificationResourcesUpdated     = "notifications/resources/updated"
+	MethodNotificationPromptsListChanged   = "notifications/prompts/list_changed"
+	MethodNotificationToolsListChanged     = "notifications/tools/list_changed"
+	MethodNotificationRootsListChanged     = "notifications/roots/list_changed"
+)
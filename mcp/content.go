@@ -0,0 +1,222 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Values of the type discriminator of Content
+const (
+	ContentTypeText         = "text"
+	ContentTypeImage        = "image"
+	ContentTypeAudio        = "audio"
+	ContentTypeResourceLink = "resource_link"
+	ContentTypeResource     = "resource"
+)
+
+// Content is the union of the content types that can appear in tool results,
+// prompt messages and sampling messages. It is implemented by TextContent,
+// ImageContent, AudioContent, ResourceLink and EmbeddedResource, and by
+// UnknownContent for the types this package doesn't know.
+type Content interface {
+	// ContentType returns the value of the type discriminator
+	ContentType() string
+
+	isContent()
+}
+
+func (TextContent) ContentType() string      { return ContentTypeText }
+func (ImageContent) ContentType() string     { return ContentTypeImage }
+func (AudioContent) ContentType() string     { return ContentTypeAudio }
+func (ResourceLink) ContentType() string     { return ContentTypeResourceLink }
+func (EmbeddedResource) ContentType() string { return ContentTypeResource }
+
+func (TextContent) isContent()      {}
+func (ImageContent) isContent()     {}
+func (AudioContent) isContent()     {}
+func (ResourceLink) isContent()     {}
+func (EmbeddedResource) isContent() {}
+func (UnknownContent) isContent()   {}
+
+// UnknownContent is content of a type this package doesn't know, such as one
+// added by a later revision of the protocol. It keeps the JSON it was decoded
+// from, and encodes back to it.
+type UnknownContent struct {
+	Type string
+	Raw  json.RawMessage
+}
+
+// ContentType returns the value of the type discriminator
+func (c UnknownContent) ContentType() string { return c.Type }
+
+// MarshalJSON implements json.Marshaler, returning the JSON the content was
+// decoded from.
+func (c UnknownContent) MarshalJSON() ([]byte, error) {
+	if c.Raw == nil {
+		return nil, fmt.Errorf("content of unknown type %q has no JSON", c.Type)
+	}
+	return c.Raw, nil
+}
+
+// MarshalJSON implements json.Marshaler, filling in the type discriminator.
+func (j TextContent) MarshalJSON() ([]byte, error) {
+	type Plain TextContent
+	j.Type = ContentTypeText
+	return json.Marshal(Plain(j))
+}
+
+// MarshalJSON implements json.Marshaler, filling in the type discriminator.
+func (j ImageContent) MarshalJSON() ([]byte, error) {
+	type Plain ImageContent
+	j.Type = ContentTypeImage
+	return json.Marshal(Plain(j))
+}
+
+// MarshalJSON implements json.Marshaler, filling in the type discriminator.
+func (j AudioContent) MarshalJSON() ([]byte, error) {
+	type Plain AudioContent
+	j.Type = ContentTypeAudio
+	return json.Marshal(Plain(j))
+}
+
+// MarshalJSON implements json.Marshaler, filling in the type discriminator.
+func (j ResourceLink) MarshalJSON() ([]byte, error) {
+	type Plain ResourceLink
+	j.Type = ContentTypeResourceLink
+	return json.Marshal(Plain(j))
+}
+
+// MarshalJSON implements json.Marshaler, filling in the type discriminator.
+func (j EmbeddedResource) MarshalJSON() ([]byte, error) {
+	type Plain EmbeddedResource
+	j.Type = ContentTypeResource
+	return json.Marshal(Plain(j))
+}
+
+func NewTextContent(text string) TextContent {
+	return TextContent{Type: ContentTypeText, Text: text}
+}
+
+func NewImageContent(data, mimeType string) ImageContent {
+	return ImageContent{Type: ContentTypeImage, Data: data, MimeType: mimeType}
+}
+
+func NewAudioContent(data, mimeType string) AudioContent {
+	return AudioContent{Type: ContentTypeAudio, Data: data, MimeType: mimeType}
+}
+
+func NewResourceLink(uri, name string) ResourceLink {
+	return ResourceLink{Type: ContentTypeResourceLink, Uri: uri, Name: name}
+}
+
+func NewEmbeddedResource(resource ResourceContents) EmbeddedResource {
+	return EmbeddedResource{Type: ContentTypeResource, Resource: resource}
+}
+
+// UnmarshalContent decodes a single content item, dispatching on its type field.
+// Content of an unknown type decodes as UnknownContent.
+func UnmarshalContent(b []byte) (Content, error) {
+	var probe struct {
+		Type *string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return nil, err
+	}
+	if probe.Type == nil {
+		return nil, errors.New("content has no type")
+	}
+
+	switch *probe.Type {
+	case ContentTypeText:
+		var content TextContent
+		err := json.Unmarshal(b, &content)
+		return content, err
+	case ContentTypeImage:
+		var content ImageContent
+		err := json.Unmarshal(b, &content)
+		return content, err
+	case ContentTypeAudio:
+		var content AudioContent
+		err := json.Unmarshal(b, &content)
+		return content, err
+	case ContentTypeResourceLink:
+		var content ResourceLink
+		err := json.Unmarshal(b, &content)
+		return content, err
+	case ContentTypeResource:
+		var content EmbeddedResource
+		err := json.Unmarshal(b, &content)
+		return content, err
+	default:
+		return UnknownContent{Type: *probe.Type, Raw: append(json.RawMessage(nil), b...)}, nil
+	}
+}
+
+func unmarshalContents(raw []json.RawMessage) ([]Content, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	contents := make([]Content, 0, len(raw))
+	for i, b := range raw {
+		content, err := UnmarshalContent(b)
+		if err != nil {
+			return nil, fmt.Errorf("content %d: %w", i, err)
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
+
+// ResourceContents is the union of the contents of a specific resource or
+// sub-resource. It is implemented by TextResourceContents and
+// BlobResourceContents only.
+type ResourceContents interface {
+	// ResourceURI returns the URI of the resource
+	ResourceURI() string
+
+	isResourceContents()
+}
+
+func (j TextResourceContents) ResourceURI() string { return j.Uri }
+func (j BlobResourceContents) ResourceURI() string { return j.Uri }
+
+func (TextResourceContents) isResourceContents() {}
+func (BlobResourceContents) isResourceContents() {}
+
+// UnmarshalResourceContents decodes the contents of a resource. Contents with a
+// blob field decode as BlobResourceContents, all others as TextResourceContents.
+func UnmarshalResourceContents(b []byte) (ResourceContents, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return nil, err
+	}
+
+	if _, ok := probe["blob"]; ok {
+		var contents BlobResourceContents
+		err := json.Unmarshal(b, &contents)
+		return contents, err
+	}
+	var contents TextResourceContents
+	err := json.Unmarshal(b, &contents)
+	return contents, err
+}
+
+func unmarshalResourceContentsList(raw []json.RawMessage) ([]ResourceContents, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	contents := make([]ResourceContents, 0, len(raw))
+	for i, b := range raw {
+		c, err := UnmarshalResourceContents(b)
+		if err != nil {
+			return nil, fmt.Errorf("contents %d: %w", i, err)
+		}
+		contents = append(contents, c)
+	}
+	return contents, nil
+}
+
+func isNull(b json.RawMessage) bool {
+	return len(b) == 0 || string(b) == "null"
+}
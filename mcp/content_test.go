@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestContentRoundTrip(t *testing.T) {
+	result := CallToolResult{Content: []Content{
+		NewTextContent("hi"),
+		NewImageContent("AA==", "image/png"),
+		NewAudioContent("AA==", "audio/wav"),
+		NewResourceLink("file:///a.txt", "a.txt"),
+		NewEmbeddedResource(TextResourceContents{Uri: "file:///a.txt", Text: "a"}),
+		NewEmbeddedResource(BlobResourceContents{Uri: "file:///b.bin", Blob: "AA=="}),
+	}}
+	b, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded CallToolResult
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("decoding %s: %v", b, err)
+	}
+	if !reflect.DeepEqual(decoded.Content, result.Content) {
+		t.Errorf("round trip of %s = %#v", b, decoded.Content)
+	}
+}
+
+func TestContentTypeFilledIn(t *testing.T) {
+	// Content built without its constructor still encodes its discriminator
+	b, err := json.Marshal(TextContent{Text: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := UnmarshalContent(b)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", b, err)
+	}
+	if content.ContentType() != ContentTypeText {
+		t.Errorf("ContentType() = %q", content.ContentType())
+	}
+}
+
+func TestUnmarshalResourceContents(t *testing.T) {
+	tests := []struct {
+		input string
+		want  ResourceContents
+	}{
+		{`{"uri":"file:///a.txt","text":"a"}`, TextResourceContents{Uri: "file:///a.txt", Text: "a"}},
+		{`{"uri":"file:///b.bin","blob":"AA=="}`, BlobResourceContents{Uri: "file:///b.bin", Blob: "AA=="}},
+	}
+	for _, tt := range tests {
+		got, err := UnmarshalResourceContents([]byte(tt.input))
+		if err != nil {
+			t.Errorf("UnmarshalResourceContents(%s): %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("UnmarshalResourceContents(%s) = %#v, want %#v", tt.input, got, tt.want)
+		}
+		if got.ResourceURI() != tt.want.ResourceURI() {
+			t.Errorf("ResourceURI() = %q", got.ResourceURI())
+		}
+	}
+}
+
+func TestUnmarshalContentUnknownType(t *testing.T) {
+	b := []byte(`{"content":[{"type":"text","text":"hi"},{"type":"video","uri":"file:///a.mp4","duration":3}],"isError":false}`)
+	var result CallToolResult
+	if err := json.Unmarshal(b, &result); err != nil {
+		t.Fatalf("result with an unknown content type not decoded: %v", err)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("content = %v", result.Content)
+	}
+	unknown, ok := result.Content[1].(UnknownContent)
+	if !ok {
+		t.Fatalf("content of unknown type decoded as %T", result.Content[1])
+	}
+	if unknown.ContentType() != "video" {
+		t.Errorf("ContentType() = %q", unknown.ContentType())
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"content":[{"text":"hi","type":"text"},{"type":"video","uri":"file:///a.mp4","duration":3}]}`; string(encoded) != want {
+		t.Errorf("re-encoded as %s, want %s", encoded, want)
+	}
+}
+
+func TestUnmarshalContentMalformed(t *testing.T) {
+	for _, b := range []string{
+		`[]`,
+		`"text"`,
+		`null`,
+		`{}`,
+		`{"type":null}`,
+		`{"type":1}`,
+		`{"type":"text","text":1}`,
+		`{"type":"image","data":"AA=="}`,
+		`{"type":"video",}`,
+	} {
+		if content, err := UnmarshalContent([]byte(b)); err == nil {
+			t.Errorf("UnmarshalContent(%s) = %#v, want an error", b, content)
+		}
+	}
+
+	var result CallToolResult
+	if err := json.Unmarshal([]byte(`{"content":[{"text":"hi"}]}`), &result); err == nil {
+		t.Error("result with content missing its type decoded")
+	}
+}
@@ -0,0 +1,25 @@
+package mcp
+
+type JSONRPCErrorData struct {
+	// The error type that occurred.
+	Code int `json:"code" yaml:"code" mapstructure:"code"`
+
+	// Additional information about the error. The value of this member is defined by
+	// the sender (e.g. detailed error information, nested errors etc.).
+	Data interface{} `json:"data,omitempty" yaml:"data,omitempty" mapstructure:"data,omitempty"`
+
+	// A short description of the error. The message SHOULD be limited to a concise
+	// single sentence.
+	Message string `json:"message" yaml:"message" mapstructure:"message"`
+}
+
+// Error implements the error interface, so that handlers can return a JSON-RPC
+// error with a specific code and have it reported to the peer as is.
+func (e *JSONRPCErrorData) Error() string {
+	return e.Message
+}
+
+// NewError creates a JSON-RPC error with the given code and message
+func NewError(code int, message string) *JSONRPCErrorData {
+	return &JSONRPCErrorData{Code: code, Message: message}
+}
@@ -0,0 +1,282 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// decoders lists a constructor for every type with a custom UnmarshalJSON,
+// except for those with a fuzz target of their own. TestDecodersComplete
+// checks that none is missing.
+var decoders = map[string]func() interface{}{
+	"Annotations":                              func() interface{} { return new(Annotations) },
+	"AudioContent":                             func() interface{} { return new(AudioContent) },
+	"BaseMetadata":                             func() interface{} { return new(BaseMetadata) },
+	"BlobResourceContents":                     func() interface{} { return new(BlobResourceContents) },
+	"BooleanSchema":                            func() interface{} { return new(BooleanSchema) },
+	"CallToolRequest":                          func() interface{} { return new(CallToolRequest) },
+	"CallToolRequestParams":                    func() interface{} { return new(CallToolRequestParams) },
+	"CallToolResult":                           func() interface{} { return new(CallToolResult) },
+	"CancelledNotification":                    func() interface{} { return new(CancelledNotification) },
+	"CancelledNotificationParams":              func() interface{} { return new(CancelledNotificationParams) },
+	"CompleteRequest":                          func() interface{} { return new(CompleteRequest) },
+	"CompleteRequestParams":                    func() interface{} { return new(CompleteRequestParams) },
+	"CompleteRequestParamsArgument":            func() interface{} { return new(CompleteRequestParamsArgument) },
+	"CompleteResult":                           func() interface{} { return new(CompleteResult) },
+	"CompleteResultCompletion":                 func() interface{} { return new(CompleteResultCompletion) },
+	"CreateMessageRequest":                     func() interface{} { return new(CreateMessageRequest) },
+	"CreateMessageRequestParams":               func() interface{} { return new(CreateMessageRequestParams) },
+	"CreateMessageRequestParamsIncludeContext": func() interface{} { return new(CreateMessageRequestParamsIncludeContext) },
+	"CreateMessageResult":                      func() interface{} { return new(CreateMessageResult) },
+	"ElicitRequest":                            func() interface{} { return new(ElicitRequest) },
+	"ElicitRequestParams":                      func() interface{} { return new(ElicitRequestParams) },
+	"ElicitRequestParamsRequestedSchema":       func() interface{} { return new(ElicitRequestParamsRequestedSchema) },
+	"ElicitResult":                             func() interface{} { return new(ElicitResult) },
+	"ElicitResultAction":                       func() interface{} { return new(ElicitResultAction) },
+	"EmbeddedResource":                         func() interface{} { return new(EmbeddedResource) },
+	"EnumSchema":                               func() interface{} { return new(EnumSchema) },
+	"GetPromptRequest":                         func() interface{} { return new(GetPromptRequest) },
+	"GetPromptRequestParams":                   func() interface{} { return new(GetPromptRequestParams) },
+	"GetPromptResult":                          func() interface{} { return new(GetPromptResult) },
+	"ImageContent":                             func() interface{} { return new(ImageContent) },
+	"Implementation":                           func() interface{} { return new(Implementation) },
+	"InitializeRequest":                        func() interface{} { return new(InitializeRequest) },
+	"InitializeRequestParams":                  func() interface{} { return new(InitializeRequestParams) },
+	"InitializeResult":                         func() interface{} { return new(InitializeResult) },
+	"InitializedNotification":                  func() interface{} { return new(InitializedNotification) },
+	"JSONRPCNotification":                      func() interface{} { return new(JSONRPCNotification) },
+	"JSONRPCRequest":                           func() interface{} { return new(JSONRPCRequest) },
+	"JSONRPCResponse":                          func() interface{} { return new(JSONRPCResponse) },
+	"ListPromptsRequest":                       func() interface{} { return new(ListPromptsRequest) },
+	"ListPromptsResult":                        func() interface{} { return new(ListPromptsResult) },
+	"ListResourceTemplatesRequest":             func() interface{} { return new(ListResourceTemplatesRequest) },
+	"ListResourceTemplatesResult":              func() interface{} { return new(ListResourceTemplatesResult) },
+	"ListResourcesRequest":                     func() interface{} { return new(ListResourcesRequest) },
+	"ListResourcesResult":                      func() interface{} { return new(ListResourcesResult) },
+	"ListRootsRequest":                         func() interface{} { return new(ListRootsRequest) },
+	"ListRootsResult":                          func() interface{} { return new(ListRootsResult) },
+	"ListToolsRequest":                         func() interface{} { return new(ListToolsRequest) },
+	"ListToolsResult":                          func() interface{} { return new(ListToolsResult) },
+	"LoggingLevel":                             func() interface{} { return new(LoggingLevel) },
+	"LoggingMessageNotification":               func() interface{} { return new(LoggingMessageNotification) },
+	"LoggingMessageNotificationParams":         func() interface{} { return new(LoggingMessageNotificationParams) },
+	"ModelPreferences":                         func() interface{} { return new(ModelPreferences) },
+	"Notification":                             func() interface{} { return new(Notification) },
+	"NumberSchema":                             func() interface{} { return new(NumberSchema) },
+	"NumberSchemaType":                         func() interface{} { return new(NumberSchemaType) },
+	"PaginatedRequest":                         func() interface{} { return new(PaginatedRequest) },
+	"PingRequest":                              func() interface{} { return new(PingRequest) },
+	"ProgressNotification":                     func() interface{} { return new(ProgressNotification) },
+	"ProgressNotificationParams":               func() interface{} { return new(ProgressNotificationParams) },
+	"Prompt":                                   func() interface{} { return new(Prompt) },
+	"PromptArgument":                           func() interface{} { return new(PromptArgument) },
+	"PromptListChangedNotification":            func() interface{} { return new(PromptListChangedNotification) },
+	"PromptMessage":                            func() interface{} { return new(PromptMessage) },
+	"PromptReference":                          func() interface{} { return new(PromptReference) },
+	"ReadResourceRequest":                      func() interface{} { return new(ReadResourceRequest) },
+	"ReadResourceRequestParams":                func() interface{} { return new(ReadResourceRequestParams) },
+	"ReadResourceResult":                       func() interface{} { return new(ReadResourceResult) },
+	"Request":                                  func() interface{} { return new(Request) },
+	"Resource":                                 func() interface{} { return new(Resource) },
+	"ResourceLink":                             func() interface{} { return new(ResourceLink) },
+	"ResourceListChangedNotification":          func() interface{} { return new(ResourceListChangedNotification) },
+	"ResourceTemplate":                         func() interface{} { return new(ResourceTemplate) },
+	"ResourceTemplateReference":                func() interface{} { return new(ResourceTemplateReference) },
+	"ResourceUpdatedNotification":              func() interface{} { return new(ResourceUpdatedNotification) },
+	"ResourceUpdatedNotificationParams":        func() interface{} { return new(ResourceUpdatedNotificationParams) },
+	"Role":                                     func() interface{} { return new(Role) },
+	"Root":                                     func() interface{} { return new(Root) },
+	"RootsListChangedNotification":             func() interface{} { return new(RootsListChangedNotification) },
+	"SamplingMessage":                          func() interface{} { return new(SamplingMessage) },
+	"SetLevelRequest":                          func() interface{} { return new(SetLevelRequest) },
+	"SetLevelRequestParams":                    func() interface{} { return new(SetLevelRequestParams) },
+	"StringSchema":                             func() interface{} { return new(StringSchema) },
+	"StringSchemaFormat":                       func() interface{} { return new(StringSchemaFormat) },
+	"SubscribeRequest":                         func() interface{} { return new(SubscribeRequest) },
+	"SubscribeRequestParams":                   func() interface{} { return new(SubscribeRequestParams) },
+	"TextContent":                              func() interface{} { return new(TextContent) },
+	"TextResourceContents":                     func() interface{} { return new(TextResourceContents) },
+	"Tool":                                     func() interface{} { return new(Tool) },
+	"ToolInputSchema":                          func() interface{} { return new(ToolInputSchema) },
+	"ToolListChangedNotification":              func() interface{} { return new(ToolListChangedNotification) },
+	"UnsubscribeRequest":                       func() interface{} { return new(UnsubscribeRequest) },
+	"UnsubscribeRequestParams":                 func() interface{} { return new(UnsubscribeRequestParams) },
+}
+
+// ownFuzzTargets lists the types with a custom UnmarshalJSON that have a fuzz
+// target of their own
+var ownFuzzTargets = map[string]bool{
+	"RequestID":     true,
+	"ProgressToken": true,
+	"stringOrInt":   true,
+}
+
+func TestDecodersComplete(t *testing.T) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range pkgs["mcp"].Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Name.Name != "UnmarshalJSON" {
+				continue
+			}
+			receiver, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			name := receiver.X.(*ast.Ident).Name
+			if _, ok := decoders[name]; !ok && !ownFuzzTargets[name] {
+				t.Errorf("%s has a custom UnmarshalJSON but is missing from decoders", name)
+			}
+		}
+	}
+}
+
+// checkRoundTrip checks that a value decoded from b encodes to JSON that
+// decodes again, to an equal encoding
+func checkRoundTrip(t *testing.T, name string, decode func(b []byte) (interface{}, error), b []byte) {
+	t.Helper()
+	v, err := decode(b)
+	if err != nil {
+		return
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("%s decoded from %q does not encode: %v", name, b, err)
+	}
+	v2, err := decode(encoded)
+	if err != nil {
+		t.Fatalf("%s encoded as %s does not decode: %v", name, encoded, err)
+	}
+	reencoded, err := json.Marshal(v2)
+	if err != nil {
+		t.Fatalf("%s decoded from %s does not encode: %v", name, encoded, err)
+	}
+	if !bytes.Equal(encoded, reencoded) {
+		t.Fatalf("%s does not round-trip: %s became %s", name, encoded, reencoded)
+	}
+}
+
+func FuzzUnmarshalTypes(f *testing.F) {
+	for _, seed := range []string{
+		`{}`,
+		`null`,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":"a","result":{"content":[{"type":"text","text":"hi"}],"isError":false}}`,
+		`{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not found"}}`,
+		`{"content":[{"type":"image","data":"AA==","mimeType":"image/png"},{"type":"audio","data":"AA==","mimeType":"audio/wav"}]}`,
+		`{"content":[{"type":"resource","resource":{"uri":"file:///a","blob":"AA=="}},{"type":"resource_link","uri":"file:///b","name":"b"}]}`,
+		`{"content":[{"type":"video","uri":"file:///a.mp4"}],"isError":true}`,
+		`{"role":"user","content":{"type":"text","text":"hi"},"model":"m"}`,
+		`{"messages":[{"role":"assistant","content":{"type":"text","text":"hi"}}],"maxTokens":1}`,
+		`{"contents":[{"uri":"file:///a","text":"a"},{"uri":"file:///b","blob":"AA=="}]}`,
+		`{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"c","version":"1"}}`,
+		`{"requestId":"x","reason":"stop"}`,
+		`{"progressToken":1,"progress":0.5,"total":1}`,
+		`"debug"`,
+		`{"ref":{"type":"ref/prompt","name":"p"},"argument":{"name":"a","value":"v"}}`,
+		`{"name":"t","inputSchema":{"type":"object","properties":{}},"annotations":{"readOnlyHint":true}}`,
+		`{"role":null,"content":{"type":"text","text":"hi"},"model":"m"}`,
+		`{"type":"resource","resource":null,"content":null}`,
+		`{"level":null,"data":null,"logger":null}`,
+		`{"jsonrpc":"2.0","id":null,"error":null,"result":null}`,
+		`{"includeContext":null,"messages":[],"maxTokens":1}`,
+		`{"method":"elicitation/create","params":{"message":"m","requestedSchema":{"type":"object","properties":{"a":{"type":"string","format":"email"},"b":{"type":"integer","minimum":1},"c":{"type":"boolean","default":true},"d":{"type":"string","enum":["x","y"]}},"required":["a"]}}}`,
+		`{"action":"accept","content":{"a":"x","b":1,"c":true}}`,
+		`{"action":"decline"}`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		for name, newValue := range decoders {
+			checkRoundTrip(t, name, func(b []byte) (interface{}, error) {
+				v := newValue()
+				err := json.Unmarshal(b, v)
+				return v, err
+			}, b)
+		}
+	})
+}
+
+func FuzzUnmarshalContent(f *testing.F) {
+	for _, seed := range []string{
+		`{"type":"text","text":"hi","annotations":{"audience":["user"],"priority":0.5}}`,
+		`{"type":"image","data":"AA==","mimeType":"image/png"}`,
+		`{"type":"audio","data":"AA==","mimeType":"audio/wav"}`,
+		`{"type":"resource_link","uri":"file:///a","name":"a","size":3}`,
+		`{"type":"resource","resource":{"uri":"file:///a","text":"a"}}`,
+		`{"type":"resource","resource":{"uri":"file:///a","blob":"AA=="}}`,
+		`{"type":"video"}`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		content, err := UnmarshalContent(b)
+		if err != nil {
+			return
+		}
+		var probe struct {
+			Type string `json:"type"`
+		}
+		json.Unmarshal(b, &probe)
+		if content.ContentType() != probe.Type {
+			t.Fatalf("content of type %q decoded as %T", probe.Type, content)
+		}
+		checkRoundTrip(t, "Content", func(b []byte) (interface{}, error) { return UnmarshalContent(b) }, b)
+	})
+}
+
+func FuzzUnmarshalResourceContents(f *testing.F) {
+	for _, seed := range []string{
+		`{"uri":"file:///a","text":"a","mimeType":"text/plain"}`,
+		`{"uri":"file:///a","blob":"AA=="}`,
+		`{"uri":"file:///a","blob":null}`,
+		`{"text":"a"}`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		checkRoundTrip(t, "ResourceContents", func(b []byte) (interface{}, error) { return UnmarshalResourceContents(b) }, b)
+	})
+}
+
+func FuzzRequestID(f *testing.F) {
+	for _, seed := range []string{`1`, `-1`, `"a"`, `""`, `null`, `1.5`, `1e3`, `9223372036854775808`, `true`, `" 1"`} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var id RequestID
+		if err := json.Unmarshal(b, &id); err != nil {
+			return
+		}
+		encoded, err := json.Marshal(id)
+		if err != nil {
+			t.Fatalf("ID decoded from %q does not encode: %v", b, err)
+		}
+		var decoded RequestID
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("ID encoded as %s does not decode: %v", encoded, err)
+		}
+		if decoded != id {
+			t.Fatalf("ID %s decoded as %s", encoded, decoded)
+		}
+		if !reflect.DeepEqual(id.IsNull(), string(bytes.TrimSpace(b)) == "null") {
+			t.Fatalf("ID decoded from %q has IsNull %v", b, id.IsNull())
+		}
+	})
+}
@@ -0,0 +1,6 @@
+package mcp
+
+// The protocol types in types.go are generated from the vendored schemas of
+// each revision, in schema/<revision>/schema.json. Helpers and the types
+// needing custom JSON handling live in the other files.
+//go:generate go run ../internal/mcpgen -schema schema -out types.go
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// stringOrInt holds a JSON value that is either a string or an integer, keeping
+// track of which form it was given in. The zero value represents null.
+type stringOrInt struct {
+	str      string
+	num      int64
+	isString bool
+	valid    bool
+}
+
+// IsNull reports whether the value is null or absent
+func (v stringOrInt) IsNull() bool {
+	return !v.valid
+}
+
+// Int returns the integer form of the value, if it is an integer
+func (v stringOrInt) Int() (int64, bool) {
+	return v.num, v.valid && !v.isString
+}
+
+// Str returns the string form of the value, if it is a string
+func (v stringOrInt) Str() (string, bool) {
+	return v.str, v.valid && v.isString
+}
+
+func (v stringOrInt) String() string {
+	switch {
+	case !v.valid:
+		return "null"
+	case v.isString:
+		return strconv.Quote(v.str)
+	default:
+		return strconv.FormatInt(v.num, 10)
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v stringOrInt) MarshalJSON() ([]byte, error) {
+	switch {
+	case !v.valid:
+		return []byte("null"), nil
+	case v.isString:
+		return json.Marshal(v.str)
+	default:
+		return []byte(strconv.FormatInt(v.num, 10)), nil
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *stringOrInt) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	switch {
+	case string(b) == "null":
+		*v = stringOrInt{}
+	case len(b) > 0 && b[0] == '"':
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*v = stringOrInt{str: s, isString: true, valid: true}
+	default:
+		n, err := strconv.ParseInt(string(b), 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be a string or an integer: %s", b)
+		}
+		*v = stringOrInt{num: n, valid: true}
+	}
+	return nil
+}
+
+// A uniquely identifying ID for a request in JSON-RPC.
+//
+// It is either a string or an integer and round-trips in the form it was given
+// in. The zero value is the null ID. RequestIDs are comparable, so they can be
+// used as map keys.
+type RequestID struct {
+	stringOrInt
+}
+
+func NewRequestID(id int64) RequestID {
+	return RequestID{stringOrInt{num: id, valid: true}}
+}
+
+func NewStringRequestID(id string) RequestID {
+	return RequestID{stringOrInt{str: id, isString: true, valid: true}}
+}
+
+// A progress token, used to associate progress notifications with the original
+// request.
+//
+// It is either a string or an integer and round-trips in the form it was given
+// in. ProgressTokens are comparable, so they can be used as map keys.
+type ProgressToken struct {
+	stringOrInt
+}
+
+func NewProgressToken(token int64) ProgressToken {
+	return ProgressToken{stringOrInt{num: token, valid: true}}
+}
+
+func NewStringProgressToken(token string) ProgressToken {
+	return ProgressToken{stringOrInt{str: token, isString: true, valid: true}}
+}
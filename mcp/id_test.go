@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRequestIDRoundTrip(t *testing.T) {
+	for _, raw := range []string{`1`, `0`, `-7`, `"1"`, `"abc"`, `""`, `null`} {
+		var id RequestID
+		if err := json.Unmarshal([]byte(raw), &id); err != nil {
+			t.Errorf("Unmarshal(%s): %v", raw, err)
+			continue
+		}
+		b, err := json.Marshal(id)
+		if err != nil {
+			t.Errorf("Marshal(%s): %v", raw, err)
+			continue
+		}
+		if string(b) != raw {
+			t.Errorf("round trip of %s = %s", raw, b)
+		}
+	}
+}
+
+func TestRequestIDForms(t *testing.T) {
+	// An integer and a string with the same digits are different IDs
+	if NewRequestID(1) == NewStringRequestID("1") {
+		t.Error("integer and string IDs compare equal")
+	}
+	if NewRequestID(1) != NewRequestID(1) {
+		t.Error("equal IDs compare different")
+	}
+
+	var id RequestID
+	if err := json.Unmarshal([]byte(`"42"`), &id); err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := id.Str(); !ok || s != "42" {
+		t.Errorf("Str() = %q, %v", s, ok)
+	}
+	if _, ok := id.Int(); ok {
+		t.Error("string ID reported as an integer")
+	}
+	if id != NewStringRequestID("42") {
+		t.Error("decoded ID differs from NewStringRequestID")
+	}
+
+	if !(RequestID{}).IsNull() || NewRequestID(0).IsNull() {
+		t.Error("IsNull does not tell the zero value from ID 0")
+	}
+}
+
+func TestRequestIDInvalid(t *testing.T) {
+	for _, raw := range []string{`1.5`, `true`, `{}`, `[1]`, `1e3`} {
+		var id RequestID
+		if err := json.Unmarshal([]byte(raw), &id); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want an error", raw, id)
+		}
+	}
+}
+
+func TestProgressTokenInMeta(t *testing.T) {
+	var params struct {
+		Meta struct {
+			ProgressToken ProgressToken `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal([]byte(`{"_meta":{"progressToken":"tok"}}`), &params); err != nil {
+		t.Fatal(err)
+	}
+	if params.Meta.ProgressToken != NewStringProgressToken("tok") {
+		t.Errorf("progressToken = %v", params.Meta.ProgressToken)
+	}
+	b, err := json.Marshal(NewProgressToken(5))
+	if err != nil || string(b) != "5" {
+		t.Errorf("Marshal = %s, %v", b, err)
+	}
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// A request that expects a response.
+type JSONRPCRequest struct {
+	// Id corresponds to the JSON schema field "id".
+	Id RequestID `json:"id" yaml:"id" mapstructure:"id"`
+
+	// Jsonrpc corresponds to the JSON schema field "jsonrpc".
+	Jsonrpc string `json:"jsonrpc" yaml:"jsonrpc" mapstructure:"jsonrpc"`
+
+	// Method corresponds to the JSON schema field "method".
+	Method string `json:"method" yaml:"method" mapstructure:"method"`
+
+	// Params corresponds to the JSON schema field "params".
+	Params json.RawMessage `json:"params,omitempty" yaml:"params,omitempty" mapstructure:"params,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JSONRPCRequest) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["id"]; !ok {
+		return fmt.Errorf("field id in JSONRPCRequest: required")
+	}
+	if _, ok := raw["jsonrpc"]; !ok {
+		return fmt.Errorf("field jsonrpc in JSONRPCRequest: required")
+	}
+	if _, ok := raw["method"]; !ok {
+		return fmt.Errorf("field method in JSONRPCRequest: required")
+	}
+	type Plain JSONRPCRequest
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = JSONRPCRequest(plain)
+	return nil
+}
+
+// A response to a request, carrying either a result or an error.
+type JSONRPCResponse struct {
+	// Id corresponds to the JSON schema field "id".
+	Id RequestID `json:"id" yaml:"id" mapstructure:"id"`
+
+	// Jsonrpc corresponds to the JSON schema field "jsonrpc".
+	Jsonrpc string `json:"jsonrpc" yaml:"jsonrpc" mapstructure:"jsonrpc"`
+
+	// Result corresponds to the JSON schema field "result".
+	Result json.RawMessage `json:"result,omitempty" yaml:"result,omitempty" mapstructure:"result,omitempty"`
+
+	// Error corresponds to the JSON schema field "error".
+	Error *JSONRPCErrorData `json:"error,omitempty" yaml:"error,omitempty" mapstructure:"error,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JSONRPCResponse) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["id"]; !ok {
+		return fmt.Errorf("field id in JSONRPCResponse: required")
+	}
+	if _, ok := raw["jsonrpc"]; !ok {
+		return fmt.Errorf("field jsonrpc in JSONRPCResponse: required")
+	}
+	_, hasResult := raw["result"]
+	_, hasError := raw["error"]
+	if !hasResult && !hasError {
+		return fmt.Errorf("field result or error in JSONRPCResponse: required")
+	}
+	type Plain JSONRPCResponse
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = JSONRPCResponse(plain)
+	return nil
+}
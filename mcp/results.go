@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func (j *CallToolResult) AddTextContent(content TextContent) {
+	j.Content = append(j.Content, content)
+}
+
+// NewStructuredToolResult creates a result whose structured content is the JSON
+// object encoding of v. The same JSON is added as text content, for clients
+// that do not support structured content.
+func NewStructuredToolResult(v interface{}) (*CallToolResult, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var structured map[string]interface{}
+	if err := json.Unmarshal(b, &structured); err != nil {
+		return nil, fmt.Errorf("structured content must be a JSON object: %w", err)
+	}
+	result := &CallToolResult{StructuredContent: structured}
+	result.AddTextContent(NewTextContent(string(b)))
+	return result, nil
+}
+
+func (j *CallToolResult) AddContent(content Content) {
+	j.Content = append(j.Content, content)
+}
+
+func (r *ReadResourceResult) AddTextContent(content TextResourceContents) {
+	r.Contents = append(r.Contents, content)
+}
+
+func (r *ReadResourceResult) AddBlobContent(content BlobResourceContents) {
+	r.Contents = append(r.Contents, content)
+}
+
+// ToJSON implements json.Marshaler.
+func (j *CallToolResult) ToJSON() (json.RawMessage, error) {
+	return json.Marshal(j)
+}
+
+// ToJSON implements json.Marshaler.
+func (j *CompleteResult) ToJSON() (json.RawMessage, error) {
+	return json.Marshal(j)
+}
+
+// ToJSON implements json.Marshaler.
+func (j *GetPromptResult) ToJSON() (json.RawMessage, error) {
+	return json.Marshal(j)
+}
+
+// ToJSON implements json.Marshaler.
+func (j *InitializeResult) ToJSON() (json.RawMessage, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(b), nil
+}
+
+// ToJSON implements json.Marshaler.
+func (j *ListPromptsResult) ToJSON() (json.RawMessage, error) {
+	return json.Marshal(j)
+}
+
+// ToJSON implements json.Marshaler.
+func (j *ListResourcesResult) ToJSON() (json.RawMessage, error) {
+	return json.Marshal(j)
+}
+
+// ToJSON implements json.Marshaler.
+func (j *ListToolsResult) ToJSON() (json.RawMessage, error) {
+	return json.Marshal(j)
+}
+
+// ToJSON implements json.Marshaler.
+func (j *ReadResourceResult) ToJSON() (json.RawMessage, error) {
+	return json.Marshal(j)
+}
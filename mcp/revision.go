@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Fields of the generated types that were added to the protocol after its first
+// revision carry a struct tag naming the revision that introduced them:
+//
+//	Title *string `json:"title,omitempty" mcp:"since=2025-06-18"`
+//
+// ForProtocolVersion reads these tags to encode values for peers that
+// negotiated an earlier revision.
+const revisionTag = "mcp"
+
+// ForProtocolVersion returns a copy of v holding only what the given protocol
+// revision defines. Fields introduced by later revisions are cleared, and
+// content types unknown to the revision are replaced by text content. v is
+// returned as is for the latest revision or an empty version.
+//
+// The copy shares maps, and the values it does not need to change, with v.
+func ForProtocolVersion[T any](v T, version string) T {
+	if version == "" || ProtocolVersionAtLeast(version, LatestProtocolVersion) {
+		return v
+	}
+	rv := reflect.ValueOf(&v).Elem()
+	if !needsConversion(rv.Type()) {
+		return v
+	}
+	converted, _ := convertValue(rv, version).Interface().(T)
+	return converted
+}
+
+// revisionConverter is implemented by types that need more than clearing fields
+// to be encoded for an earlier protocol revision. forProtocolVersion is called
+// on a copy of the value before its fields are converted.
+type revisionConverter interface {
+	forProtocolVersion(version string)
+}
+
+var revisionConverterType = reflect.TypeFor[revisionConverter]()
+
+// conversions caches whether values of a type may need to be converted
+var conversions sync.Map
+
+func needsConversion(t reflect.Type) bool {
+	if needs, ok := conversions.Load(t); ok {
+		return needs.(bool)
+	}
+	needs := typeNeedsConversion(t, map[reflect.Type]bool{})
+	conversions.Store(t, needs)
+	return needs
+}
+
+func typeNeedsConversion(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	if reflect.PointerTo(t).Implements(revisionConverterType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice:
+		return typeNeedsConversion(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.IsExported() && (fieldRevision(f) != "" || typeNeedsConversion(f.Type, seen)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldRevision returns the protocol revision that introduced a field, or an
+// empty string if the field has always been part of the protocol
+func fieldRevision(f reflect.StructField) string {
+	since, _ := strings.CutPrefix(f.Tag.Get(revisionTag), "since=")
+	return since
+}
+
+// convertValue returns a copy of v, of the same type, converted for version
+func convertValue(v reflect.Value, version string) reflect.Value {
+	if !needsConversion(v.Type()) {
+		return v
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		elem := convertValue(v.Elem(), version)
+		if content, ok := elem.Interface().(Content); ok {
+			elem = reflect.ValueOf(contentForProtocolVersion(content, version))
+		}
+		if !elem.Type().AssignableTo(v.Type()) {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(elem)
+		return out
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(convertValue(v.Elem(), version))
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(convertValue(v.Index(i), version))
+		}
+		return out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		if converter, ok := out.Addr().Interface().(revisionConverter); ok {
+			converter.forProtocolVersion(version)
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if since := fieldRevision(f); since != "" && !ProtocolVersionAtLeast(version, since) {
+				out.Field(i).SetZero()
+				continue
+			}
+			out.Field(i).Set(convertValue(out.Field(i), version))
+		}
+		return out
+	}
+	return v
+}
+
+// contentForProtocolVersion replaces content types introduced after version by
+// text content describing them
+func contentForProtocolVersion(content Content, version string) Content {
+	switch c := content.(type) {
+	case AudioContent:
+		if !ProtocolVersionAtLeast(version, ProtocolVersion20250326) {
+			return TextContent{
+				Type:        ContentTypeText,
+				Text:        fmt.Sprintf("[%s audio]", c.MimeType),
+				Annotations: c.Annotations,
+			}
+		}
+	case ResourceLink:
+		if !ProtocolVersionAtLeast(version, ProtocolVersion20250618) {
+			return TextContent{
+				Type:        ContentTypeText,
+				Text:        fmt.Sprintf("[%s](%s)", c.Name, c.Uri),
+				Annotations: c.Annotations,
+			}
+		}
+	}
+	return content
+}
+
+// forProtocolVersion implements revisionConverter. Clients that predate
+// structured content get it as text, unless the result already has text content.
+func (j *CallToolResult) forProtocolVersion(version string) {
+	if j.StructuredContent == nil || ProtocolVersionAtLeast(version, ProtocolVersion20250618) {
+		return
+	}
+	for _, content := range j.Content {
+		if _, ok := content.(TextContent); ok {
+			return
+		}
+	}
+	b, err := json.Marshal(j.StructuredContent)
+	if err != nil {
+		return
+	}
+	j.Content = append(slices.Clip(j.Content), NewTextContent(string(b)))
+}
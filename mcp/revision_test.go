@@ -0,0 +1,94 @@
+package mcp
+
+import "testing"
+
+func TestForProtocolVersionClearsFields(t *testing.T) {
+	title := "Echo"
+	tool := Tool{
+		Name:         "echo",
+		Title:        &title,
+		Annotations:  &ToolAnnotations{},
+		OutputSchema: &ToolOutputSchema{Type: "object"},
+	}
+	result := ListToolsResult{Tools: []Tool{tool}}
+
+	tests := []struct {
+		version     string
+		title       bool
+		annotations bool
+	}{
+		{ProtocolVersion20250618, true, true},
+		{ProtocolVersion20250326, false, true},
+		{ProtocolVersion20241105, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got := ForProtocolVersion(result, tt.version).Tools[0]
+			if (got.Title != nil) != tt.title {
+				t.Errorf("title kept = %v, want %v", got.Title != nil, tt.title)
+			}
+			if (got.Annotations != nil) != tt.annotations {
+				t.Errorf("annotations kept = %v, want %v", got.Annotations != nil, tt.annotations)
+			}
+			if (got.OutputSchema != nil) != (tt.version == ProtocolVersion20250618) {
+				t.Errorf("outputSchema kept = %v", got.OutputSchema != nil)
+			}
+		})
+	}
+
+	// The original value is left untouched
+	if result.Tools[0].Title == nil || result.Tools[0].OutputSchema == nil {
+		t.Error("ForProtocolVersion modified its argument")
+	}
+}
+
+func TestForProtocolVersionContent(t *testing.T) {
+	result := CallToolResult{Content: []Content{
+		NewAudioContent("AAAA", "audio/wav"),
+		NewResourceLink("file:///a.txt", "a.txt"),
+	}}
+
+	got := ForProtocolVersion(result, ProtocolVersion20250326)
+	if _, ok := got.Content[0].(AudioContent); !ok {
+		t.Errorf("audio content converted for %s: %#v", ProtocolVersion20250326, got.Content[0])
+	}
+	if text, ok := got.Content[1].(TextContent); !ok || text.Text != "[a.txt](file:///a.txt)" {
+		t.Errorf("resource link = %#v, want text content", got.Content[1])
+	}
+
+	got = ForProtocolVersion(result, ProtocolVersion20241105)
+	if text, ok := got.Content[0].(TextContent); !ok || text.Text != "[audio/wav audio]" {
+		t.Errorf("audio content = %#v, want text content", got.Content[0])
+	}
+	if _, ok := result.Content[0].(AudioContent); !ok {
+		t.Error("ForProtocolVersion modified its argument")
+	}
+}
+
+func TestForProtocolVersionStructuredContent(t *testing.T) {
+	result := CallToolResult{
+		Content:           []Content{},
+		StructuredContent: map[string]interface{}{"count": 3},
+	}
+
+	got := ForProtocolVersion(result, ProtocolVersion20250326)
+	if got.StructuredContent != nil {
+		t.Errorf("structuredContent = %v, want it cleared", got.StructuredContent)
+	}
+	if len(got.Content) != 1 {
+		t.Fatalf("content = %#v, want the structured content as text", got.Content)
+	}
+	if text, ok := got.Content[0].(TextContent); !ok || text.Text != `{"count":3}` {
+		t.Errorf("content = %#v, want the structured content as text", got.Content[0])
+	}
+
+	// Results that already have text content are not given a second one
+	result.Content = []Content{NewTextContent("3")}
+	if got := ForProtocolVersion(result, ProtocolVersion20250326); len(got.Content) != 1 {
+		t.Errorf("content = %#v, want it unchanged", got.Content)
+	}
+
+	if got := ForProtocolVersion(result, LatestProtocolVersion); got.StructuredContent == nil {
+		t.Error("structuredContent cleared for the latest revision")
+	}
+}
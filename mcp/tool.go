@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// IsReadOnly reports the value of ReadOnlyHint, applying its default
+func (j *ToolAnnotations) IsReadOnly() bool {
+	return j != nil && j.ReadOnlyHint != nil && *j.ReadOnlyHint
+}
+
+// IsDestructive reports whether the tool may perform destructive updates,
+// applying the defaults of ReadOnlyHint and DestructiveHint
+func (j *ToolAnnotations) IsDestructive() bool {
+	if j.IsReadOnly() {
+		return false
+	}
+	return j == nil || j.DestructiveHint == nil || *j.DestructiveHint
+}
+
+// IsIdempotent reports whether repeated calls with the same arguments have no
+// additional effect. Read-only tools are always idempotent.
+func (j *ToolAnnotations) IsIdempotent() bool {
+	if j.IsReadOnly() {
+		return true
+	}
+	return j != nil && j.IdempotentHint != nil && *j.IdempotentHint
+}
+
+// IsOpenWorld reports the value of OpenWorldHint, applying its default
+func (j *ToolAnnotations) IsOpenWorld() bool {
+	return j == nil || j.OpenWorldHint == nil || *j.OpenWorldHint
+}
+
+// DisplayName returns the name to show for the tool in a UI: its title, the
+// title of its annotations, or its name, in that order of preference
+func (j Tool) DisplayName() string {
+	if j.Title != nil && *j.Title != "" {
+		return *j.Title
+	}
+	if j.Annotations != nil && j.Annotations.Title != nil && *j.Annotations.Title != "" {
+		return *j.Annotations.Title
+	}
+	return j.Name
+}
+
+// A JSON Schema object defining the expected parameters for the tool.
+//
+// The most common keywords are decoded into fields; every other keyword is kept
+// verbatim in Extra, so that arbitrary schemas round-trip losslessly.
+type ToolInputSchema struct {
+	// Reusable subschemas, referenced from elsewhere in the schema with
+	// "$ref": "#/$defs/<name>".
+	Defs ToolInputSchemaDefs `json:"$defs,omitempty" yaml:"$defs,omitempty" mapstructure:"$defs,omitempty"`
+
+	// Whether, or according to which schema, properties not listed in Properties
+	// are allowed. It is either a bool or a schema object.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty" mapstructure:"additionalProperties,omitempty"`
+
+	// Properties corresponds to the JSON schema field "properties".
+	Properties ToolInputSchemaProperties `json:"properties,omitempty" yaml:"properties,omitempty" mapstructure:"properties,omitempty"`
+
+	// The names of the properties that must be present.
+	Required []string `json:"required,omitempty" yaml:"required,omitempty" mapstructure:"required,omitempty"`
+
+	// Type corresponds to the JSON schema field "type".
+	Type string `json:"type" yaml:"type" mapstructure:"type"`
+
+	// All other JSON Schema keywords, keyed by name.
+	Extra map[string]json.RawMessage `json:"-" yaml:"-" mapstructure:",remain"`
+}
+
+// A JSON Schema object defining the structure of a tool's structured output.
+type ToolOutputSchema = ToolInputSchema
+
+// ToolInputSchemaProperties holds the schema of each property: a bool or a JSON
+// object, as decoded by encoding/json with numbers kept as json.Number
+type ToolInputSchemaProperties map[string]interface{}
+
+// ToolInputSchemaDefs holds subschemas by name, decoded as the values of
+// ToolInputSchemaProperties
+type ToolInputSchemaDefs map[string]interface{}
+
+var toolInputSchemaKeywords = map[string]bool{
+	"$defs":                true,
+	"additionalProperties": true,
+	"properties":           true,
+	"required":             true,
+	"type":                 true,
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *ToolInputSchema) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["type"]; !ok {
+		return fmt.Errorf("field type in ToolInputSchema: required")
+	}
+	type Plain ToolInputSchema
+	var plain Plain
+	// Keep numbers as written, as large integers do not survive float64
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&plain); err != nil {
+		return err
+	}
+	for keyword, value := range raw {
+		if toolInputSchemaKeywords[keyword] {
+			continue
+		}
+		if plain.Extra == nil {
+			plain.Extra = make(map[string]json.RawMessage)
+		}
+		plain.Extra[keyword] = value
+	}
+	*j = ToolInputSchema(plain)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, merging Extra into the encoded object.
+func (j ToolInputSchema) MarshalJSON() ([]byte, error) {
+	type Plain ToolInputSchema
+	if len(j.Extra) == 0 {
+		return json.Marshal(Plain(j))
+	}
+
+	object := make(map[string]interface{}, len(j.Extra)+len(toolInputSchemaKeywords))
+	for keyword, value := range j.Extra {
+		object[keyword] = value
+	}
+	b, err := json.Marshal(Plain(j))
+	if err != nil {
+		return nil, err
+	}
+	var known map[string]json.RawMessage
+	if err := json.Unmarshal(b, &known); err != nil {
+		return nil, err
+	}
+	for keyword, value := range known {
+		object[keyword] = value
+	}
+	return json.Marshal(object)
+}
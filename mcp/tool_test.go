@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToolInputSchemaRoundTrip(t *testing.T) {
+	input := `{"$defs":{"s":{"type":"string"}},"additionalProperties":false,"description":"d","properties":{"a":{"$ref":"#/$defs/s"}},"required":["a"],"type":"object","x-custom":[1,2]}`
+	var schema ToolInputSchema
+	if err := json.Unmarshal([]byte(input), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if len(schema.Required) != 1 || schema.AdditionalProperties != false {
+		t.Errorf("decoded schema = %+v", schema)
+	}
+	if def, ok := schema.Defs["s"].(map[string]interface{}); !ok || def["type"] != "string" {
+		t.Errorf("$defs = %v", schema.Defs)
+	}
+	if string(schema.Extra["x-custom"]) != "[1,2]" {
+		t.Errorf("extra keywords = %v", schema.Extra)
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != input {
+		t.Errorf("round trip = %s, want %s", b, input)
+	}
+}
+
+func TestToolInputSchemaLossless(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"boolean subschemas", `{"$defs":{"never":false},"properties":{"anything":true,"nothing":false},"type":"object"}`},
+		{"large integers", `{"properties":{"id":{"maximum":9007199254740993,"minimum":-9007199254740993,"type":"integer"}},"type":"object"}`},
+		{"decimals", `{"additionalProperties":{"multipleOf":0.1,"type":"number"},"type":"object"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema ToolInputSchema
+			if err := json.Unmarshal([]byte(tt.input), &schema); err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.input {
+				t.Errorf("round trip = %s, want %s", b, tt.input)
+			}
+		})
+	}
+}
+
+func TestNewStructuredToolResult(t *testing.T) {
+	result, err := NewStructuredToolResult(struct {
+		Count int `json:"count"`
+	}{3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.StructuredContent["count"] != float64(3) {
+		t.Errorf("structuredContent = %v", result.StructuredContent)
+	}
+	if text, ok := result.Content[0].(TextContent); !ok || text.Text != `{"count":3}` {
+		t.Errorf("content = %+v", result.Content)
+	}
+
+	if _, err := NewStructuredToolResult([]int{1}); err == nil {
+		t.Error("structured content that is not an object accepted")
+	}
+}
+
+func TestToolAnnotationDefaults(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name        string
+		annotations *ToolAnnotations
+		readOnly    bool
+		destructive bool
+		idempotent  bool
+		openWorld   bool
+	}{
+		{"none", nil, false, true, false, true},
+		{"empty", &ToolAnnotations{}, false, true, false, true},
+		{"read-only", &ToolAnnotations{ReadOnlyHint: &yes, DestructiveHint: &yes}, true, false, true, true},
+		{"additive", &ToolAnnotations{DestructiveHint: &no, IdempotentHint: &yes, OpenWorldHint: &no}, false, false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.annotations
+			if got := a.IsReadOnly(); got != tt.readOnly {
+				t.Errorf("IsReadOnly() = %v", got)
+			}
+			if got := a.IsDestructive(); got != tt.destructive {
+				t.Errorf("IsDestructive() = %v", got)
+			}
+			if got := a.IsIdempotent(); got != tt.idempotent {
+				t.Errorf("IsIdempotent() = %v", got)
+			}
+			if got := a.IsOpenWorld(); got != tt.openWorld {
+				t.Errorf("IsOpenWorld() = %v", got)
+			}
+		})
+	}
+}
+
+func TestToolDisplayName(t *testing.T) {
+	title, annotated, empty := "Title", "Annotated", ""
+	tests := []struct {
+		tool Tool
+		want string
+	}{
+		{Tool{Name: "name"}, "name"},
+		{Tool{Name: "name", Title: &empty}, "name"},
+		{Tool{Name: "name", Annotations: &ToolAnnotations{Title: &annotated}}, "Annotated"},
+		{Tool{Name: "name", Title: &title, Annotations: &ToolAnnotations{Title: &annotated}}, "Title"},
+	}
+	for _, tt := range tests {
+		if got := tt.tool.DisplayName(); got != tt.want {
+			t.Errorf("DisplayName() = %q, want %q", got, tt.want)
+		}
+	}
+}
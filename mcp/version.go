@@ -0,0 +1,57 @@
+package mcp
+
+import "slices"
+
+// Protocol revisions supported by this package
+const (
+	ProtocolVersion20241105 = "2024-11-05"
+	ProtocolVersion20250326 = "2025-03-26"
+	ProtocolVersion20250618 = "2025-06-18"
+)
+
+// LatestProtocolVersion is the newest protocol revision supported by this package
+const LatestProtocolVersion = ProtocolVersion20250618
+
+// SupportedProtocolVersions lists the protocol revisions supported by this
+// package, newest first
+var SupportedProtocolVersions = []string{
+	ProtocolVersion20250618,
+	ProtocolVersion20250326,
+	ProtocolVersion20241105,
+}
+
+// IsSupportedProtocolVersion reports whether version is one of SupportedProtocolVersions
+func IsSupportedProtocolVersion(version string) bool {
+	return slices.Contains(SupportedProtocolVersions, version)
+}
+
+// NegotiateProtocolVersion returns the version a server answers with when a
+// client requests the given version: the requested version if it is supported,
+// the latest supported version otherwise.
+func NegotiateProtocolVersion(requested string) string {
+	if IsSupportedProtocolVersion(requested) {
+		return requested
+	}
+	return LatestProtocolVersion
+}
+
+// ProtocolVersionAtLeast reports whether version is the same revision as
+// minimum or a later one. Revisions are dates, so they order lexically.
+func ProtocolVersionAtLeast(version, minimum string) bool {
+	return version >= minimum
+}
+
+// methodRevisions lists the methods added to the protocol after its first
+// revision, with the revision that introduced them. completion/complete is not
+// one of them: only its capability was added, in 2025-03-26.
+var methodRevisions = map[string]string{
+	MethodElicitationCreate: ProtocolVersion20250618,
+}
+
+// IsMethodAvailable reports whether method is defined by the given protocol
+// revision. Every method is available if version is empty, as before
+// initialization.
+func IsMethodAvailable(method, version string) bool {
+	since, ok := methodRevisions[method]
+	return !ok || version == "" || ProtocolVersionAtLeast(version, since)
+}
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestSchemaRevisions(t *testing.T) {
+	entries, err := os.ReadDir("schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var revisions []string
+	for _, entry := range entries {
+		if _, err := os.Stat("schema/" + entry.Name() + "/schema.json"); err != nil {
+			t.Errorf("revision %s: %v", entry.Name(), err)
+		}
+		revisions = append(revisions, entry.Name())
+	}
+	// Directories are listed oldest first
+	slices.Reverse(revisions)
+	if !slices.Equal(revisions, SupportedProtocolVersions) {
+		t.Errorf("vendored schemas = %v, want one per supported revision %v", revisions, SupportedProtocolVersions)
+	}
+	if SupportedProtocolVersions[0] != LatestProtocolVersion {
+		t.Errorf("types are generated from %s, want the latest revision %s", SupportedProtocolVersions[0], LatestProtocolVersion)
+	}
+}
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	tests := map[string]string{
+		ProtocolVersion20241105: ProtocolVersion20241105,
+		ProtocolVersion20250326: ProtocolVersion20250326,
+		ProtocolVersion20250618: ProtocolVersion20250618,
+		"2023-01-01":            LatestProtocolVersion,
+		"2099-01-01":            LatestProtocolVersion,
+		"":                      LatestProtocolVersion,
+	}
+	for requested, want := range tests {
+		if got := NegotiateProtocolVersion(requested); got != want {
+			t.Errorf("NegotiateProtocolVersion(%q) = %s, want %s", requested, got, want)
+		}
+	}
+}
+
+func TestProtocolVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version, minimum string
+		want             bool
+	}{
+		{ProtocolVersion20250618, ProtocolVersion20250326, true},
+		{ProtocolVersion20250326, ProtocolVersion20250326, true},
+		{ProtocolVersion20241105, ProtocolVersion20250326, false},
+	}
+	for _, tt := range tests {
+		if got := ProtocolVersionAtLeast(tt.version, tt.minimum); got != tt.want {
+			t.Errorf("ProtocolVersionAtLeast(%s, %s) = %v, want %v", tt.version, tt.minimum, got, tt.want)
+		}
+	}
+}
+
+func TestIsMethodAvailable(t *testing.T) {
+	tests := []struct {
+		method  string
+		version string
+		want    bool
+	}{
+		{MethodToolsCall, ProtocolVersion20241105, true},
+		{MethodCompletionComplete, ProtocolVersion20241105, true},
+		{MethodElicitationCreate, ProtocolVersion20250326, false},
+		{MethodElicitationCreate, ProtocolVersion20250618, true},
+		{MethodElicitationCreate, "", true},
+	}
+	for _, tt := range tests {
+		if got := IsMethodAvailable(tt.method, tt.version); got != tt.want {
+			t.Errorf("IsMethodAvailable(%s, %q) = %v, want %v", tt.method, tt.version, got, tt.want)
+		}
+	}
+}
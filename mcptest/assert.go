@@ -0,0 +1,49 @@
+package mcptest
+
+import (
+	"reflect"
+	"testing"
+)
+
+// AssertCalled fails the test if the server received no message for method
+func (s *Server) AssertCalled(t testing.TB, method string) {
+	t.Helper()
+	if len(s.CallsTo(method)) == 0 {
+		t.Errorf("expected a call to %s, got none", method)
+	}
+}
+
+// AssertNotCalled fails the test if the server received a message for method
+func (s *Server) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+	if n := len(s.CallsTo(method)); n > 0 {
+		t.Errorf("expected no call to %s, got %d", method, n)
+	}
+}
+
+// AssertCallCount fails the test unless the server received exactly n messages
+// for method
+func (s *Server) AssertCallCount(t testing.TB, method string, n int) {
+	t.Helper()
+	if got := len(s.CallsTo(method)); got != n {
+		t.Errorf("expected %d calls to %s, got %d", n, method, got)
+	}
+}
+
+// AssertToolCalled fails the test unless the named tool was called with
+// arguments, compared after decoding from JSON: numbers must be given as
+// float64
+func (s *Server) AssertToolCalled(t testing.TB, name string, arguments map[string]interface{}) {
+	t.Helper()
+	calls := s.ToolCalls(name)
+	for _, called := range calls {
+		if reflect.DeepEqual(called, arguments) || len(called) == 0 && len(arguments) == 0 {
+			return
+		}
+	}
+	if len(calls) == 0 {
+		t.Errorf("expected a call to tool %s, got none", name)
+		return
+	}
+	t.Errorf("expected a call to tool %s with arguments %v, got %v", name, arguments, calls)
+}
@@ -0,0 +1,240 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// clientEnv is the environment of a client check: the client under test and
+// the server end of its connection, played by the suite
+type clientEnv struct {
+	*conn
+	client client.MCPClient
+}
+
+// RunClientTests runs the client conformance checks, each as a subtest with its
+// own client returned by factory
+func RunClientTests(t *testing.T, factory ClientFactory, opts ...Option) {
+	t.Helper()
+	c := newConfig(opts)
+	run(t, c, clientChecks, func(t *testing.T) clientEnv {
+		clientEnd, serverEnd := pipe()
+		return clientEnv{conn: newConn(t, c, serverEnd), client: factory(t, clientEnd)}
+	})
+}
+
+var clientChecks = []check[clientEnv]{
+	{"lifecycle/initialize", checkClientInitialize},
+	{"lifecycle/unsupported-version", checkClientUnsupportedVersion},
+	{"ping", checkClientPing},
+	{"errors/unknown-method", checkClientUnknownMethod},
+	{"notifications/unknown", checkClientUnknownNotification},
+	{"pagination/cursor", checkClientCursor},
+	{"cancellation/request", checkClientCancel},
+	{"capabilities/gating", checkClientCapabilityGating},
+}
+
+// goAsync runs fn, a call of the client under test, in the background while
+// the suite plays the server
+func goAsync[T any](fn func(ctx context.Context) (T, error)) (context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := fn(ctx)
+		done <- err
+	}()
+	return cancel, done
+}
+
+// expectRequest waits for a request from the client and checks its method
+func (e clientEnv) expectRequest(method string) *message {
+	e.t.Helper()
+	m := e.next(e.config.timeout)
+	if m == nil {
+		e.t.Fatalf("client sent no %s request within %v", method, e.config.timeout)
+	}
+	if !m.isRequest() || m.Method != method {
+		e.t.Fatalf("expected a %s request, got %s", method, m)
+	}
+	return m
+}
+
+// expectNotification waits for a notification from the client and checks its
+// method
+func (e clientEnv) expectNotification(method string) *message {
+	e.t.Helper()
+	m := e.next(e.config.timeout)
+	if m == nil {
+		e.t.Fatalf("client sent no %s notification within %v", method, e.config.timeout)
+	}
+	if !m.isNotification() || m.Method != method {
+		e.t.Fatalf("expected a %s notification, got %s", method, m)
+	}
+	return m
+}
+
+func (e clientEnv) wait(done <-chan error) error {
+	e.t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(e.config.timeout):
+		e.t.Fatalf("client call did not return within %v", e.config.timeout)
+		return nil
+	}
+}
+
+// initialize has the client initialize the session, answering as a server
+// offering tools, and returns the parameters of the initialize request
+func (e clientEnv) initialize() map[string]json.RawMessage {
+	e.t.Helper()
+	_, done := goAsync(func(ctx context.Context) (*mcp.InitializeResult, error) {
+		return e.client.Initialize(ctx, mcp.ClientCapabilities{}, mcp.Implementation{Name: "conformance", Version: "1.0.0"}, "")
+	})
+
+	request := e.expectRequest(mcp.MethodInitialize)
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		e.t.Fatalf("failed to decode initialize parameters: %v: %s", err, request)
+	}
+	var version string
+	json.Unmarshal(params["protocolVersion"], &version)
+	e.respond(request.ID, map[string]interface{}{
+		"protocolVersion": mcp.NegotiateProtocolVersion(version),
+		"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+		"serverInfo":      map[string]interface{}{"name": "conformance", "version": "1.0.0"},
+	})
+
+	e.expectNotification(mcp.MethodNotificationInitialized)
+	if err := e.wait(done); err != nil {
+		e.t.Fatalf("failed to initialize: %v", err)
+	}
+	return params
+}
+
+func checkClientInitialize(t *testing.T, e clientEnv) {
+	params := e.initialize()
+	for _, field := range []string{"protocolVersion", "capabilities", "clientInfo"} {
+		if _, ok := params[field]; !ok {
+			t.Errorf("initialize request has no %s", field)
+		}
+	}
+	var version string
+	json.Unmarshal(params["protocolVersion"], &version)
+	if version == "" {
+		t.Errorf("initialize request has no protocol version")
+	}
+}
+
+func checkClientUnsupportedVersion(t *testing.T, e clientEnv) {
+	_, done := goAsync(func(ctx context.Context) (*mcp.InitializeResult, error) {
+		return e.client.Initialize(ctx, mcp.ClientCapabilities{}, mcp.Implementation{Name: "conformance", Version: "1.0.0"}, "")
+	})
+	request := e.expectRequest(mcp.MethodInitialize)
+	e.respond(request.ID, map[string]interface{}{
+		"protocolVersion": "1900-01-01",
+		"capabilities":    map[string]interface{}{},
+		"serverInfo":      map[string]interface{}{"name": "conformance", "version": "1.0.0"},
+	})
+	if err := e.wait(done); err == nil {
+		t.Errorf("client accepted an unsupported protocol version")
+	}
+}
+
+func checkClientPing(t *testing.T, e clientEnv) {
+	e.initialize()
+	var result map[string]json.RawMessage
+	expectResult(t, e.call(mcp.MethodPing, nil), &result)
+	delete(result, "_meta")
+	if len(result) != 0 {
+		t.Errorf("ping must be answered with an empty result, got %v", result)
+	}
+}
+
+func checkClientUnknownMethod(t *testing.T, e clientEnv) {
+	e.initialize()
+	expectError(t, e.call("conformance/unknown", nil), mcp.ErrorCodeMethodNotFound)
+}
+
+func checkClientUnknownNotification(t *testing.T, e clientEnv) {
+	e.initialize()
+	e.notify("notifications/conformance/unknown", map[string]interface{}{})
+	e.expectSilence("notifications must not be answered, even unknown ones")
+	expectResult(t, e.call(mcp.MethodPing, nil), nil)
+}
+
+func checkClientCursor(t *testing.T, e clientEnv) {
+	e.initialize()
+	cursor := "conformance-cursor"
+	for _, cursor := range []*string{nil, &cursor} {
+		_, done := goAsync(func(ctx context.Context) (*mcp.ListToolsResult, error) {
+			return e.client.ListTools(ctx, cursor)
+		})
+		request := e.expectRequest(mcp.MethodToolsList)
+		var params struct {
+			Cursor *string `json:"cursor"`
+		}
+		json.Unmarshal(request.Params, &params)
+		switch {
+		case cursor == nil && params.Cursor != nil:
+			t.Errorf("first page requested with cursor %q", *params.Cursor)
+		case cursor != nil && (params.Cursor == nil || *params.Cursor != *cursor):
+			t.Errorf("cursor not passed on to the server: %s", request)
+		}
+		e.respond(request.ID, map[string]interface{}{"tools": []interface{}{}})
+		if err := e.wait(done); err != nil {
+			t.Errorf("failed to list tools: %v", err)
+		}
+	}
+}
+
+func checkClientCancel(t *testing.T, e clientEnv) {
+	e.initialize()
+	cancel, done := goAsync(func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, e.client.Ping(ctx)
+	})
+	request := e.expectRequest(mcp.MethodPing)
+	cancel()
+	if err := e.wait(done); err == nil {
+		t.Errorf("cancelled request succeeded")
+	}
+
+	notification := e.expectNotification(mcp.MethodNotificationCancelled)
+	var params struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	json.Unmarshal(notification.Params, &params)
+	if !sameID(params.RequestID, request.ID) {
+		t.Errorf("cancellation refers to request %s instead of %s", params.RequestID, request.ID)
+	}
+
+	// A response crossing the cancellation must be ignored
+	e.respond(request.ID, struct{}{})
+	expectResult(t, e.call(mcp.MethodPing, nil), nil)
+}
+
+func checkClientCapabilityGating(t *testing.T, e clientEnv) {
+	var capabilities map[string]json.RawMessage
+	json.Unmarshal(e.initialize()["capabilities"], &capabilities)
+	gated := map[string]string{
+		"roots":       mcp.MethodRootsList,
+		"sampling":    mcp.MethodSamplingCreateMessage,
+		"elicitation": mcp.MethodElicitationCreate,
+	}
+	for capability, method := range gated {
+		if _, ok := capabilities[capability]; ok {
+			continue
+		}
+		response := e.call(method, map[string]interface{}{})
+		if response.Error == nil {
+			t.Errorf("%s is served although the %s capability was not declared: %s", method, capability, response)
+		} else if response.Error.Code != mcp.ErrorCodeMethodNotFound {
+			t.Errorf("%s: expected error code %d, got %d (%s)", method, mcp.ErrorCodeMethodNotFound, response.Error.Code, response.Error.Message)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package conformance checks that an MCP server or client follows the
+// behaviour the protocol specification requires of it: lifecycle ordering,
+// JSON-RPC error codes, pagination, cancellation, ping, capability gating and
+// the handling of unknown methods.
+//
+// The checks run as subtests, exchanging raw JSON-RPC messages over a
+// client.Transport, so that any implementation reachable through one can be
+// tested, whatever language it is written in:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.RunServerTests(t, func(t testing.TB) client.Transport {
+//			return mcptest.NewTransport(newServer())
+//		})
+//	}
+//
+// Checks covering behaviour the specification only recommends can be skipped
+// by name with WithSkip.
+package conformance
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/client"
+)
+
+// ServerFactory starts the server under test and returns a new connection to
+// it. Every check uses its own connection, which is closed when the check ends.
+type ServerFactory func(t testing.TB) client.Transport
+
+// ClientFactory returns the client under test, talking to a server through
+// transport. The suite plays the server and initializes the client itself.
+type ClientFactory func(t testing.TB, transport client.Transport) client.MCPClient
+
+// Option configures RunServerTests and RunClientTests
+type Option func(*config)
+
+type config struct {
+	timeout       time.Duration
+	quietPeriod   time.Duration
+	slowTool      string
+	slowArguments map[string]interface{}
+	skip          []string
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		timeout:     5 * time.Second,
+		quietPeriod: 200 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithTimeout sets how long a check waits for an expected message. It
+// defaults to 5 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// WithQuietPeriod sets how long a check waits before concluding that no
+// message is coming, for instance that a notification went unanswered. It
+// defaults to 200 milliseconds.
+func WithQuietPeriod(period time.Duration) Option {
+	return func(c *config) {
+		c.quietPeriod = period
+	}
+}
+
+// WithSlowTool names a tool of the server under test whose call with the given
+// arguments runs longer than the timeout unless it is cancelled. It enables the
+// check that cancelling a request in flight suppresses its response.
+func WithSlowTool(name string, arguments map[string]interface{}) Option {
+	return func(c *config) {
+		c.slowTool = name
+		c.slowArguments = arguments
+	}
+}
+
+// WithSkip skips the checks with the given names, such as
+// "lifecycle/request-before-initialize"
+func WithSkip(checks ...string) Option {
+	return func(c *config) {
+		c.skip = append(c.skip, checks...)
+	}
+}
+
+type check[T any] struct {
+	name string
+	run  func(t *testing.T, env T)
+}
+
+// run runs every check as a subtest, with the environment returned by setup
+func run[T any](t *testing.T, c *config, checks []check[T], setup func(t *testing.T) T) {
+	t.Helper()
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			if slices.Contains(c.skip, check.name) {
+				t.Skip("skipped by WithSkip")
+			}
+			check.run(t, setup(t))
+		})
+	}
+}
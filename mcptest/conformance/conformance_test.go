@@ -0,0 +1,43 @@
+package conformance_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/mcptest"
+	"github.com/WePrompt/gomcp/mcptest/conformance"
+	"github.com/WePrompt/gomcp/server"
+)
+
+func TestMCPServer(t *testing.T) {
+	conformance.RunServerTests(t, func(t testing.TB) client.Transport {
+		return mcptest.NewTransport(server.NewMCPServer())
+	})
+}
+
+func TestMCPServerWithFeatures(t *testing.T) {
+	s := mcptest.NewServer(mcptest.WithPageSize(2), mcptest.WithServerOptions(server.WithCompletions()))
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		s.AddToolResult(mcp.Tool{Name: name}, &mcp.CallToolResult{Content: []mcp.Content{}})
+		s.AddPromptResult(mcp.Prompt{Name: name}, &mcp.GetPromptResult{})
+		s.AddResourceText(mcp.Resource{Uri: "test://" + name, Name: name}, name)
+	}
+	s.AddTool(mcp.Tool{Name: "slow"}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	conformance.RunServerTests(t, func(t testing.TB) client.Transport {
+		return s.Transport()
+	}, conformance.WithSlowTool("slow", nil))
+}
+
+func TestClient(t *testing.T) {
+	conformance.RunClientTests(t, func(t testing.TB, transport client.Transport) client.MCPClient {
+		c := client.NewClient(transport)
+		t.Cleanup(func() { c.Close() })
+		return c
+	})
+}
@@ -0,0 +1,357 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// message is any JSON-RPC message, decoded leniently so that malformed
+// messages can be reported rather than dropped
+type message struct {
+	Jsonrpc string                `json:"jsonrpc"`
+	ID      json.RawMessage       `json:"id"`
+	Method  string                `json:"method"`
+	Params  json.RawMessage       `json:"params"`
+	Result  json.RawMessage       `json:"result"`
+	Error   *mcp.JSONRPCErrorData `json:"error"`
+
+	raw json.RawMessage
+}
+
+func (m *message) hasID() bool {
+	return len(m.ID) > 0 && string(m.ID) != "null"
+}
+
+func (m *message) isRequest() bool {
+	return m.Method != "" && m.hasID()
+}
+
+func (m *message) isNotification() bool {
+	return m.Method != "" && !m.hasID()
+}
+
+func (m *message) isResponse() bool {
+	return m.Method == ""
+}
+
+func (m *message) String() string {
+	return string(m.raw)
+}
+
+// conn exchanges raw messages with the implementation under test
+type conn struct {
+	t         testing.TB
+	config    *config
+	transport client.Transport
+	nextID    int
+
+	messages chan *message
+	// pending holds the responses received while waiting for another one
+	pending []*message
+}
+
+func newConn(t testing.TB, c *config, transport client.Transport) *conn {
+	cn := &conn{
+		t:         t,
+		config:    c,
+		transport: transport,
+		messages:  make(chan *message, 64),
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(cn.messages)
+		for {
+			raw, err := transport.Receive()
+			if err != nil {
+				return
+			}
+			m := &message{raw: raw}
+			if err := json.Unmarshal(raw, m); err != nil {
+				t.Errorf("received a message that is not a JSON-RPC object: %s", raw)
+				continue
+			}
+			cn.messages <- m
+		}
+	}()
+	t.Cleanup(func() {
+		transport.Close()
+		// Messages still queued would block the reader
+		go func() {
+			for range cn.messages {
+			}
+		}()
+		wg.Wait()
+	})
+	return cn
+}
+
+// sendRaw sends a message as is
+func (c *conn) sendRaw(raw string) {
+	c.t.Helper()
+	if err := c.transport.Send(context.Background(), json.RawMessage(raw)); err != nil {
+		c.t.Fatalf("failed to send %s: %v", raw, err)
+	}
+}
+
+func (c *conn) send(v interface{}) {
+	c.t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		c.t.Fatalf("failed to marshal message: %v", err)
+	}
+	c.sendRaw(string(raw))
+}
+
+// sendRequest sends a request with a new numeric ID and returns the ID
+func (c *conn) sendRequest(method string, params interface{}) json.RawMessage {
+	c.t.Helper()
+	c.nextID++
+	id := json.RawMessage(fmt.Sprint(c.nextID))
+	c.sendRequestWithID(id, method, params)
+	return id
+}
+
+func (c *conn) sendRequestWithID(id json.RawMessage, method string, params interface{}) {
+	c.t.Helper()
+	request := struct {
+		Jsonrpc string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  string          `json:"method"`
+		Params  interface{}     `json:"params,omitempty"`
+	}{mcp.JSONRPCVersion, id, method, params}
+	c.send(request)
+}
+
+func (c *conn) notify(method string, params interface{}) {
+	c.t.Helper()
+	notification := struct {
+		Jsonrpc string      `json:"jsonrpc"`
+		Method  string      `json:"method"`
+		Params  interface{} `json:"params,omitempty"`
+	}{mcp.JSONRPCVersion, method, params}
+	c.send(notification)
+}
+
+func (c *conn) respond(id json.RawMessage, result interface{}) {
+	c.t.Helper()
+	response := struct {
+		Jsonrpc string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  interface{}     `json:"result"`
+	}{mcp.JSONRPCVersion, id, result}
+	c.send(response)
+}
+
+func (c *conn) respondError(id json.RawMessage, rpcErr *mcp.JSONRPCErrorData) {
+	c.t.Helper()
+	response := struct {
+		Jsonrpc string                `json:"jsonrpc"`
+		ID      json.RawMessage       `json:"id"`
+		Error   *mcp.JSONRPCErrorData `json:"error"`
+	}{mcp.JSONRPCVersion, id, rpcErr}
+	c.send(response)
+}
+
+// call sends a request and waits for its response
+func (c *conn) call(method string, params interface{}) *message {
+	c.t.Helper()
+	return c.waitResponse(c.sendRequest(method, params))
+}
+
+// next returns the next message of the peer, or nil if none arrives within
+// timeout. Requests are left to the caller.
+func (c *conn) next(timeout time.Duration) *message {
+	c.t.Helper()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case m, ok := <-c.messages:
+		if !ok {
+			c.t.Fatalf("connection closed by the peer")
+		}
+		return m
+	case <-timer.C:
+		return nil
+	}
+}
+
+// waitResponse waits for the response to the request with the given ID,
+// answering the requests of the peer meanwhile
+func (c *conn) waitResponse(id json.RawMessage) *message {
+	c.t.Helper()
+	for i, m := range c.pending {
+		if sameID(m.ID, id) {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return m
+		}
+	}
+
+	deadline := time.Now().Add(c.config.timeout)
+	for {
+		m := c.next(time.Until(deadline))
+		if m == nil {
+			c.t.Fatalf("no response to request %s within %v", id, c.config.timeout)
+		}
+		switch {
+		case m.isResponse() && sameID(m.ID, id):
+			return m
+		case m.isResponse():
+			c.pending = append(c.pending, m)
+		case m.isRequest():
+			c.answerRequest(m)
+		}
+	}
+}
+
+// expectNoResponse fails the check if a response to the request with the
+// given ID arrives within the quiet period
+func (c *conn) expectNoResponse(id json.RawMessage, reason string) {
+	c.t.Helper()
+	deadline := time.Now().Add(c.config.quietPeriod)
+	for {
+		m := c.next(time.Until(deadline))
+		if m == nil {
+			return
+		}
+		switch {
+		case m.isResponse() && sameID(m.ID, id):
+			c.t.Errorf("%s, got %s", reason, m)
+			return
+		case m.isResponse():
+			c.pending = append(c.pending, m)
+		case m.isRequest():
+			c.answerRequest(m)
+		}
+	}
+}
+
+// expectSilence fails the check if any response arrives within the quiet
+// period
+func (c *conn) expectSilence(reason string) {
+	c.t.Helper()
+	deadline := time.Now().Add(c.config.quietPeriod)
+	for {
+		m := c.next(time.Until(deadline))
+		if m == nil {
+			return
+		}
+		switch {
+		case m.isResponse():
+			c.t.Errorf("%s, got %s", reason, m)
+			return
+		case m.isRequest():
+			c.answerRequest(m)
+		}
+	}
+}
+
+// answerRequest answers a request of the peer as a peer declaring no
+// capability would
+func (c *conn) answerRequest(m *message) {
+	c.t.Helper()
+	if m.Method == mcp.MethodPing {
+		c.respond(m.ID, struct{}{})
+		return
+	}
+	c.respondError(m.ID, mcp.NewError(mcp.ErrorCodeMethodNotFound, fmt.Sprintf("method not found: %s", m.Method)))
+}
+
+// expectResult fails the check unless m is a successful response, and decodes
+// its result into v if v is not nil
+func expectResult(t testing.TB, m *message, v interface{}) {
+	t.Helper()
+	if m.Error != nil {
+		t.Fatalf("expected a result, got error %d: %s", m.Error.Code, m.Error.Message)
+	}
+	if len(m.Result) == 0 || string(m.Result) == "null" {
+		t.Fatalf("response has no result: %s", m)
+	}
+	if v != nil {
+		if err := json.Unmarshal(m.Result, v); err != nil {
+			t.Fatalf("failed to decode result: %v: %s", err, m)
+		}
+	}
+}
+
+// expectError fails the check unless m is an error response with the given
+// code. A zero code accepts any error.
+func expectError(t testing.TB, m *message, code int) {
+	t.Helper()
+	if m.Error == nil {
+		t.Fatalf("expected an error response, got %s", m)
+	}
+	if len(m.Result) > 0 {
+		t.Errorf("error response must not carry a result: %s", m)
+	}
+	if code != 0 && m.Error.Code != code {
+		t.Errorf("expected error code %d, got %d (%s)", code, m.Error.Code, m.Error.Message)
+	}
+}
+
+func sameID(a, b json.RawMessage) bool {
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return false
+	}
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+}
+
+// pipeEnd is one end of an in-memory connection between the suite and a client
+// under test
+type pipeEnd struct {
+	in     chan json.RawMessage
+	out    chan json.RawMessage
+	closed chan struct{}
+	once   *sync.Once
+}
+
+// pipe returns the two ends of a new in-memory connection. Closing either end
+// closes both.
+func pipe() (*pipeEnd, *pipeEnd) {
+	a, b := make(chan json.RawMessage, 64), make(chan json.RawMessage, 64)
+	closed, once := make(chan struct{}), &sync.Once{}
+	return &pipeEnd{in: a, out: b, closed: closed, once: once},
+		&pipeEnd{in: b, out: a, closed: closed, once: once}
+}
+
+func (p *pipeEnd) Send(ctx context.Context, message json.RawMessage) error {
+	message = append(json.RawMessage(nil), message...)
+	select {
+	case <-p.closed:
+		return client.ErrConnectionClosed
+	default:
+	}
+	select {
+	case p.out <- message:
+		return nil
+	case <-p.closed:
+		return client.ErrConnectionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *pipeEnd) Receive() (json.RawMessage, error) {
+	select {
+	case message := <-p.in:
+		return message, nil
+	case <-p.closed:
+		return nil, io.EOF
+	}
+}
+
+func (p *pipeEnd) Close() error {
+	p.once.Do(func() { close(p.closed) })
+	return nil
+}
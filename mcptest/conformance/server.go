@@ -0,0 +1,339 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// serverEnv is the environment of a server check: a fresh connection to the
+// server under test
+type serverEnv struct {
+	*conn
+
+	// connect opens another connection to the server under test
+	connect func() serverEnv
+}
+
+// initialize performs the initialization handshake and returns its result
+func (e serverEnv) initialize() *mcp.InitializeResult {
+	e.t.Helper()
+	response := e.call(mcp.MethodInitialize, initializeParams(mcp.LatestProtocolVersion))
+	var result mcp.InitializeResult
+	expectResult(e.t, response, &result)
+	e.notify(mcp.MethodNotificationInitialized, nil)
+	return &result
+}
+
+func initializeParams(protocolVersion string) map[string]interface{} {
+	return map[string]interface{}{
+		"protocolVersion": protocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]interface{}{"name": "conformance", "version": "1.0.0"},
+	}
+}
+
+// RunServerTests runs the server conformance checks, each as a subtest with its
+// own connection returned by factory
+func RunServerTests(t *testing.T, factory ServerFactory, opts ...Option) {
+	t.Helper()
+	c := newConfig(opts)
+	run(t, c, serverChecks, func(t *testing.T) serverEnv {
+		var connect func() serverEnv
+		connect = func() serverEnv {
+			return serverEnv{conn: newConn(t, c, factory(t)), connect: connect}
+		}
+		return connect()
+	})
+}
+
+var serverChecks = []check[serverEnv]{
+	{"lifecycle/initialize", checkServerInitialize},
+	{"lifecycle/version-negotiation", checkServerVersionNegotiation},
+	{"lifecycle/initialized-notification", checkServerInitializedNotification},
+	{"lifecycle/ping-before-initialize", checkServerPingBeforeInitialize},
+	{"lifecycle/request-before-initialize", checkServerRequestBeforeInitialize},
+	{"ping", checkServerPing},
+	{"errors/parse-error", checkServerParseError},
+	{"errors/invalid-request", checkServerInvalidRequest},
+	{"errors/unknown-method", checkServerUnknownMethod},
+	{"errors/invalid-params", checkServerInvalidParams},
+	{"errors/string-id", checkServerStringID},
+	{"notifications/unknown", checkServerUnknownNotification},
+	{"pagination/tools", checkServerPagination(mcp.MethodToolsList)},
+	{"pagination/prompts", checkServerPagination(mcp.MethodPromptsList)},
+	{"pagination/resources", checkServerPagination(mcp.MethodResourcesList)},
+	{"pagination/invalid-cursor", checkServerInvalidCursor},
+	{"cancellation/unknown-request", checkServerCancelUnknown},
+	{"cancellation/in-flight", checkServerCancelInFlight},
+	{"capabilities/gating", checkServerCapabilityGating},
+	{"completion/earlier-revision", checkServerCompletionEarlierRevision},
+}
+
+func checkServerInitialize(t *testing.T, e serverEnv) {
+	result := e.initialize()
+	if !mcp.IsSupportedProtocolVersion(result.ProtocolVersion) {
+		t.Errorf("server answered an unknown protocol version %q", result.ProtocolVersion)
+	} else if result.ProtocolVersion != mcp.LatestProtocolVersion {
+		t.Logf("server negotiated %s instead of the requested %s", result.ProtocolVersion, mcp.LatestProtocolVersion)
+	}
+	if result.ServerInfo.Name == "" {
+		t.Errorf("serverInfo has no name")
+	}
+}
+
+func checkServerVersionNegotiation(t *testing.T, e serverEnv) {
+	const unsupported = "1900-01-01"
+	response := e.call(mcp.MethodInitialize, initializeParams(unsupported))
+	var result mcp.InitializeResult
+	expectResult(t, response, &result)
+	if result.ProtocolVersion == "" || result.ProtocolVersion == unsupported {
+		t.Errorf("server must answer an unsupported protocol version with one it supports, got %q", result.ProtocolVersion)
+	}
+}
+
+func checkServerInitializedNotification(t *testing.T, e serverEnv) {
+	e.initialize()
+	e.expectSilence("notifications/initialized must not be answered")
+	expectResult(t, e.call(mcp.MethodPing, nil), nil)
+}
+
+func checkServerPingBeforeInitialize(t *testing.T, e serverEnv) {
+	expectResult(t, e.call(mcp.MethodPing, nil), nil)
+}
+
+func checkServerRequestBeforeInitialize(t *testing.T, e serverEnv) {
+	expectError(t, e.call(mcp.MethodToolsList, nil), 0)
+}
+
+func checkServerPing(t *testing.T, e serverEnv) {
+	e.initialize()
+	var result map[string]json.RawMessage
+	expectResult(t, e.call(mcp.MethodPing, nil), &result)
+	delete(result, "_meta")
+	if len(result) != 0 {
+		t.Errorf("ping must be answered with an empty result, got %v", result)
+	}
+}
+
+func checkServerParseError(t *testing.T, e serverEnv) {
+	e.sendRaw(`{"jsonrpc": "2.0", "id": 1, "method": "ping"`)
+	response := e.waitResponse(json.RawMessage("null"))
+	expectError(t, response, mcp.ErrorCodeParseError)
+}
+
+func checkServerInvalidRequest(t *testing.T, e serverEnv) {
+	e.sendRaw(`{"jsonrpc": "1.0", "id": 1, "method": "ping"}`)
+	response := e.waitResponse(json.RawMessage("1"))
+	expectError(t, response, mcp.ErrorCodeInvalidRequest)
+
+	// An id that is neither a string nor an integer cannot be echoed back
+	e.sendRaw(`{"jsonrpc": "2.0", "id": 1.0, "method": "ping"}`)
+	response = e.waitResponse(json.RawMessage("null"))
+	expectError(t, response, mcp.ErrorCodeInvalidRequest)
+}
+
+func checkServerUnknownMethod(t *testing.T, e serverEnv) {
+	e.initialize()
+	expectError(t, e.call("conformance/unknown", nil), mcp.ErrorCodeMethodNotFound)
+}
+
+func checkServerInvalidParams(t *testing.T, e serverEnv) {
+	capabilities := e.initialize().Capabilities
+	switch {
+	case capabilities.Tools != nil:
+		expectError(t, e.call(mcp.MethodToolsCall, map[string]interface{}{"name": 42}), mcp.ErrorCodeInvalidParams)
+	case capabilities.Prompts != nil:
+		expectError(t, e.call(mcp.MethodPromptsGet, map[string]interface{}{"name": 42}), mcp.ErrorCodeInvalidParams)
+	case capabilities.Resources != nil:
+		expectError(t, e.call(mcp.MethodResourcesRead, map[string]interface{}{"uri": 42}), mcp.ErrorCodeInvalidParams)
+	default:
+		t.Skip("server offers no tools, prompts or resources")
+	}
+}
+
+func checkServerStringID(t *testing.T, e serverEnv) {
+	id := json.RawMessage(`"conformance-1"`)
+	e.sendRequestWithID(id, mcp.MethodPing, nil)
+	expectResult(t, e.waitResponse(id), nil)
+}
+
+func checkServerUnknownNotification(t *testing.T, e serverEnv) {
+	e.initialize()
+	e.notify("notifications/conformance/unknown", map[string]interface{}{})
+	e.expectSilence("notifications must not be answered, even unknown ones")
+	expectResult(t, e.call(mcp.MethodPing, nil), nil)
+}
+
+// maxPages bounds the pages read from a list, to detect cursors looping
+const maxPages = 1000
+
+func checkServerPagination(method string) func(t *testing.T, e serverEnv) {
+	return func(t *testing.T, e serverEnv) {
+		capabilities := e.initialize().Capabilities
+		if !listAdvertised(capabilities, method) {
+			t.Skipf("server does not advertise %s", method)
+		}
+
+		seen := make(map[string]bool)
+		cursors := make(map[string]bool)
+		var cursor *string
+		for pages := 1; ; pages++ {
+			params := map[string]interface{}{}
+			if cursor != nil {
+				params["cursor"] = *cursor
+			}
+			var page struct {
+				Tools      []struct{ Name string } `json:"tools"`
+				Prompts    []struct{ Name string } `json:"prompts"`
+				Resources  []struct{ URI string }  `json:"resources"`
+				NextCursor *string                 `json:"nextCursor"`
+			}
+			expectResult(t, e.call(method, params), &page)
+
+			var keys []string
+			for _, item := range page.Tools {
+				keys = append(keys, item.Name)
+			}
+			for _, item := range page.Prompts {
+				keys = append(keys, item.Name)
+			}
+			for _, item := range page.Resources {
+				keys = append(keys, item.URI)
+			}
+			for _, key := range keys {
+				if seen[key] {
+					t.Errorf("%s returned %q on more than one page", method, key)
+				}
+				seen[key] = true
+			}
+
+			if page.NextCursor == nil {
+				return
+			}
+			if cursors[*page.NextCursor] {
+				t.Fatalf("%s returned cursor %q twice", method, *page.NextCursor)
+			}
+			if pages == maxPages {
+				t.Fatalf("%s returned more than %d pages", method, maxPages)
+			}
+			cursors[*page.NextCursor] = true
+			cursor = page.NextCursor
+		}
+	}
+}
+
+func checkServerInvalidCursor(t *testing.T, e serverEnv) {
+	capabilities := e.initialize().Capabilities
+	for _, method := range []string{mcp.MethodToolsList, mcp.MethodPromptsList, mcp.MethodResourcesList} {
+		if listAdvertised(capabilities, method) {
+			response := e.call(method, map[string]interface{}{"cursor": "conformance-invalid-cursor"})
+			expectError(t, response, mcp.ErrorCodeInvalidParams)
+			return
+		}
+	}
+	t.Skip("server offers no tools, prompts or resources")
+}
+
+func listAdvertised(capabilities mcp.ServerCapabilities, method string) bool {
+	switch method {
+	case mcp.MethodToolsList:
+		return capabilities.Tools != nil
+	case mcp.MethodPromptsList:
+		return capabilities.Prompts != nil
+	case mcp.MethodResourcesList:
+		return capabilities.Resources != nil
+	}
+	return false
+}
+
+func checkServerCancelUnknown(t *testing.T, e serverEnv) {
+	e.initialize()
+	e.notify(mcp.MethodNotificationCancelled, map[string]interface{}{"requestId": 999999, "reason": "conformance"})
+	e.expectSilence("cancelling an unknown request must not be answered")
+	expectResult(t, e.call(mcp.MethodPing, nil), nil)
+}
+
+func checkServerCancelInFlight(t *testing.T, e serverEnv) {
+	if e.config.slowTool == "" {
+		t.Skip("no slow tool configured with WithSlowTool")
+	}
+	e.initialize()
+	id := e.sendRequest(mcp.MethodToolsCall, map[string]interface{}{
+		"name":      e.config.slowTool,
+		"arguments": e.config.slowArguments,
+	})
+	// Give the server time to start handling the request
+	time.Sleep(e.config.quietPeriod)
+	e.notify(mcp.MethodNotificationCancelled, map[string]interface{}{"requestId": json.RawMessage(id), "reason": "conformance"})
+	e.expectNoResponse(id, fmt.Sprintf("cancelled request %s should not be answered", id))
+	expectResult(t, e.call(mcp.MethodPing, nil), nil)
+}
+
+func checkServerCapabilityGating(t *testing.T, e serverEnv) {
+	capabilities := e.initialize().Capabilities
+	gated := map[string]interface{}{}
+	if capabilities.Tools == nil {
+		gated[mcp.MethodToolsList] = nil
+	}
+	if capabilities.Prompts == nil {
+		gated[mcp.MethodPromptsList] = nil
+	}
+	if capabilities.Resources == nil {
+		gated[mcp.MethodResourcesList] = nil
+	}
+	if capabilities.Resources == nil || !capabilities.Resources.Subscribe {
+		gated[mcp.MethodResourcesSubscribe] = map[string]interface{}{"uri": "conformance://resource"}
+	}
+	if capabilities.Logging == nil {
+		gated[mcp.MethodLoggingSetLevel] = map[string]interface{}{"level": "info"}
+	}
+	if capabilities.Completions == nil {
+		gated[mcp.MethodCompletionComplete] = map[string]interface{}{
+			"ref":      map[string]interface{}{"type": "ref/prompt", "name": "conformance"},
+			"argument": map[string]interface{}{"name": "argument", "value": ""},
+		}
+	}
+	if len(gated) == 0 {
+		t.Skip("server advertises every capability")
+	}
+	for _, method := range slices.Sorted(maps.Keys(gated)) {
+		response := e.call(method, gated[method])
+		if response.Error == nil {
+			t.Errorf("%s is served although its capability is not advertised: %s", method, response)
+		} else if response.Error.Code != mcp.ErrorCodeMethodNotFound {
+			t.Errorf("%s: expected error code %d, got %d (%s)", method, mcp.ErrorCodeMethodNotFound, response.Error.Code, response.Error.Message)
+		}
+	}
+}
+
+func checkServerCompletionEarlierRevision(t *testing.T, e serverEnv) {
+	if e.connect().initialize().Capabilities.Completions == nil {
+		t.Skip("server does not advertise completions")
+	}
+
+	// completion/complete is part of 2024-11-05, which has no capability for it
+	response := e.call(mcp.MethodInitialize, initializeParams(mcp.ProtocolVersion20241105))
+	var result mcp.InitializeResult
+	expectResult(t, response, &result)
+	if result.ProtocolVersion != mcp.ProtocolVersion20241105 {
+		t.Skipf("server negotiated %s instead of %s", result.ProtocolVersion, mcp.ProtocolVersion20241105)
+	}
+	if result.Capabilities.Completions != nil {
+		t.Errorf("completions capability advertised to a %s client", mcp.ProtocolVersion20241105)
+	}
+	e.notify(mcp.MethodNotificationInitialized, nil)
+
+	response = e.call(mcp.MethodCompletionComplete, map[string]interface{}{
+		"ref":      map[string]interface{}{"type": "ref/prompt", "name": "conformance"},
+		"argument": map[string]interface{}{"name": "argument", "value": ""},
+	})
+	if response.Error != nil && response.Error.Code == mcp.ErrorCodeMethodNotFound {
+		t.Errorf("%s is not served to a %s client: %s", mcp.MethodCompletionComplete, mcp.ProtocolVersion20241105, response.Error.Message)
+	}
+}
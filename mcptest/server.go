@@ -0,0 +1,480 @@
+// Package mcptest provides a scriptable mock MCP server for testing code that
+// talks to MCP servers through client.MCPClient.
+//
+// A Server declares tools, prompts and resources answered with canned results
+// or functions, injects errors and delays, sends notifications on demand and
+// records the calls it receives. Clients connect to it in-process through a
+// Transport, or through stdio by re-executing the test binary with
+// ServeIfChild and StartStdio.
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server"
+	"github.com/WePrompt/gomcp/server/handlers"
+)
+
+// ToolFunc answers calls to a mock tool
+type ToolFunc func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error)
+
+// PromptFunc answers requests for a mock prompt
+type PromptFunc func(ctx context.Context, arguments map[string]string) (*mcp.GetPromptResult, error)
+
+// ResourceFunc answers reads of a mock resource
+type ResourceFunc func(ctx context.Context, uri string) (*mcp.ReadResourceResult, error)
+
+// Fault alters how the server answers requests for a method
+type Fault struct {
+	// Delay is waited before handling the request, unless it is cancelled
+	Delay time.Duration
+
+	// Err is returned instead of handling the request. An *mcp.JSONRPCErrorData
+	// is reported to the client as is; other errors become internal errors.
+	Err error
+
+	// Times is the number of requests the fault applies to. Zero applies it to
+	// every request until the faults are cleared.
+	Times int
+}
+
+// Call is a message received by the server
+type Call struct {
+	Method string
+	Params json.RawMessage
+	Time   time.Time
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithServerInfo sets the implementation info returned from initialize
+func WithServerInfo(name, version string) Option {
+	return func(s *Server) {
+		s.name, s.version = name, version
+	}
+}
+
+// WithPageSize splits tool, prompt and resource lists into pages of size items
+func WithPageSize(size int) Option {
+	return func(s *Server) {
+		s.paginator = handlers.NewPaginator(size)
+	}
+}
+
+// WithServerOptions applies options to the underlying MCPServer, such as
+// server.WithRequestTimeout
+func WithServerOptions(opts ...server.ServerOption) Option {
+	return func(s *Server) {
+		s.serverOptions = append(s.serverOptions, opts...)
+	}
+}
+
+// Server is a mock MCP server. It is safe for concurrent use, and tools,
+// prompts and resources may be changed while clients are connected, which
+// sends them the matching list_changed notification.
+type Server struct {
+	name          string
+	version       string
+	paginator     *handlers.Paginator
+	serverOptions []server.ServerOption
+	mcp           *server.MCPServer
+
+	mu            sync.Mutex
+	tools         []mcp.Tool
+	toolFuncs     map[string]ToolFunc
+	prompts       []mcp.Prompt
+	promptFuncs   map[string]PromptFunc
+	resources     []mcp.Resource
+	resourceFuncs map[string]ResourceFunc
+	subscriptions map[string]bool
+	faults        map[string][]*Fault
+	calls         []Call
+	sessions      map[*server.Session]struct{}
+}
+
+// NewServer returns a mock server offering no tools, prompts or resources
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		name:          "mcptest",
+		version:       "1.0.0",
+		paginator:     handlers.NewPaginator(1000),
+		toolFuncs:     make(map[string]ToolFunc),
+		promptFuncs:   make(map[string]PromptFunc),
+		resourceFuncs: make(map[string]ResourceFunc),
+		subscriptions: make(map[string]bool),
+		faults:        make(map[string][]*Fault),
+		sessions:      make(map[*server.Session]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	options := []server.ServerOption{
+		server.WithServerInfo(s.name, s.version),
+		server.WithToolHandler(toolHandler{s}),
+		server.WithPromptHandler(promptHandler{s}),
+		server.WithResourceHandler(resourceHandler{s}),
+		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(true),
+		server.WithResourceCapabilities(true, true),
+		server.WithLogging(),
+		server.WithInterceptors(s.intercept),
+	}
+	s.mcp = server.NewMCPServer(append(options, s.serverOptions...)...)
+	return s
+}
+
+// MCPServer returns the server answering requests, to be served over any
+// transport
+func (s *Server) MCPServer() *server.MCPServer {
+	return s.mcp
+}
+
+// Transport returns a new in-process connection to the server
+func (s *Server) Transport() *Transport {
+	t := NewTransport(s.mcp)
+	s.track(t.Session())
+	return t
+}
+
+// Connect returns a client connected to the server in-process and initialized,
+// which is closed when the test ends
+func (s *Server) Connect(t testing.TB, opts ...client.ClientOption) *client.Client {
+	t.Helper()
+	c := client.NewClient(s.Transport(), opts...)
+	t.Cleanup(func() { c.Close() })
+	if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "mcptest", Version: "1.0.0"}, ""); err != nil {
+		t.Fatalf("failed to initialize mock server: %v", err)
+	}
+	return c
+}
+
+// AddTool declares a tool answered by fn, replacing any tool with the same name
+func (s *Server) AddTool(tool mcp.Tool, fn ToolFunc) {
+	if tool.InputSchema.Type == "" {
+		tool.InputSchema.Type = "object"
+	}
+	s.mu.Lock()
+	s.tools = replace(s.tools, tool, func(t mcp.Tool) bool { return t.Name == tool.Name })
+	s.toolFuncs[tool.Name] = fn
+	s.mu.Unlock()
+	s.Notify(mcp.MethodNotificationToolsListChanged, nil)
+}
+
+// AddToolResult declares a tool always answered with result
+func (s *Server) AddToolResult(tool mcp.Tool, result *mcp.CallToolResult) {
+	s.AddTool(tool, func(context.Context, map[string]interface{}) (*mcp.CallToolResult, error) {
+		return result, nil
+	})
+}
+
+// RemoveTool removes the named tool
+func (s *Server) RemoveTool(name string) {
+	s.mu.Lock()
+	s.tools = slices.DeleteFunc(s.tools, func(t mcp.Tool) bool { return t.Name == name })
+	delete(s.toolFuncs, name)
+	s.mu.Unlock()
+	s.Notify(mcp.MethodNotificationToolsListChanged, nil)
+}
+
+// AddPrompt declares a prompt answered by fn, replacing any prompt with the same
+// name
+func (s *Server) AddPrompt(prompt mcp.Prompt, fn PromptFunc) {
+	s.mu.Lock()
+	s.prompts = replace(s.prompts, prompt, func(p mcp.Prompt) bool { return p.Name == prompt.Name })
+	s.promptFuncs[prompt.Name] = fn
+	s.mu.Unlock()
+	s.Notify(mcp.MethodNotificationPromptsListChanged, nil)
+}
+
+// AddPromptResult declares a prompt always answered with result
+func (s *Server) AddPromptResult(prompt mcp.Prompt, result *mcp.GetPromptResult) {
+	s.AddPrompt(prompt, func(context.Context, map[string]string) (*mcp.GetPromptResult, error) {
+		return result, nil
+	})
+}
+
+// RemovePrompt removes the named prompt
+func (s *Server) RemovePrompt(name string) {
+	s.mu.Lock()
+	s.prompts = slices.DeleteFunc(s.prompts, func(p mcp.Prompt) bool { return p.Name == name })
+	delete(s.promptFuncs, name)
+	s.mu.Unlock()
+	s.Notify(mcp.MethodNotificationPromptsListChanged, nil)
+}
+
+// AddResource declares a resource whose reads are answered by fn, replacing any
+// resource with the same URI
+func (s *Server) AddResource(resource mcp.Resource, fn ResourceFunc) {
+	s.mu.Lock()
+	s.resources = replace(s.resources, resource, func(r mcp.Resource) bool { return r.Uri == resource.Uri })
+	s.resourceFuncs[resource.Uri] = fn
+	s.mu.Unlock()
+	s.Notify(mcp.MethodNotificationResourcesListChanged, nil)
+}
+
+// AddResourceText declares a resource whose content is text
+func (s *Server) AddResourceText(resource mcp.Resource, text string) {
+	s.AddResource(resource, func(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+		result := &mcp.ReadResourceResult{}
+		result.AddTextContent(mcp.TextResourceContents{Uri: uri, MimeType: resource.MimeType, Text: text})
+		return result, nil
+	})
+}
+
+// RemoveResource removes the resource with the given URI
+func (s *Server) RemoveResource(uri string) {
+	s.mu.Lock()
+	s.resources = slices.DeleteFunc(s.resources, func(r mcp.Resource) bool { return r.Uri == uri })
+	delete(s.resourceFuncs, uri)
+	s.mu.Unlock()
+	s.Notify(mcp.MethodNotificationResourcesListChanged, nil)
+}
+
+// UpdateResource sends notifications/resources/updated for uri, if a client
+// subscribed to it
+func (s *Server) UpdateResource(uri string) {
+	s.mu.Lock()
+	subscribed := s.subscriptions[uri]
+	s.mu.Unlock()
+	if subscribed {
+		s.Notify(mcp.MethodNotificationResourcesUpdated, map[string]string{"uri": uri})
+	}
+}
+
+// Subscribed reports whether a client is subscribed to uri
+func (s *Server) Subscribed(uri string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscriptions[uri]
+}
+
+// Notify sends a notification to every connected client. Clients connected over
+// stdio are only known once they sent a message.
+func (s *Server) Notify(method string, params interface{}) error {
+	s.mu.Lock()
+	sessions := make([]*server.Session, 0, len(s.sessions))
+	for session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	s.mu.Unlock()
+
+	var errs []error
+	for _, session := range sessions {
+		if err := session.SendNotification(method, params); err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify session %s: %w", session.ID(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Inject adds a fault to the requests for method. Faults for a method apply in
+// the order they were added.
+func (s *Server) Inject(method string, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[method] = append(s.faults[method], &fault)
+}
+
+// ClearFaults removes every injected fault
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = make(map[string][]*Fault)
+}
+
+// Calls returns the messages received so far, requests and notifications alike
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.calls)
+}
+
+// CallsTo returns the messages received so far for method
+func (s *Server) CallsTo(method string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var calls []Call
+	for _, call := range s.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// ToolCalls returns the arguments of every call to the named tool
+func (s *Server) ToolCalls(name string) []map[string]interface{} {
+	var arguments []map[string]interface{}
+	for _, call := range s.CallsTo(mcp.MethodToolsCall) {
+		var p struct {
+			Name      string                 `json:"name"`
+			Arguments map[string]interface{} `json:"arguments"`
+		}
+		if json.Unmarshal(call.Params, &p) == nil && p.Name == name {
+			arguments = append(arguments, p.Arguments)
+		}
+	}
+	return arguments
+}
+
+// ResetCalls forgets the messages received so far
+func (s *Server) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+// intercept records every message and applies the injected faults
+func (s *Server) intercept(ctx context.Context, method string, params json.RawMessage, next server.RequestHandlerFunc) (json.RawMessage, error) {
+	if session := server.SessionFromContext(ctx); session != nil {
+		s.track(session)
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Method: method, Params: params, Time: time.Now()})
+	var fault *Fault
+	if faults := s.faults[method]; len(faults) > 0 {
+		fault = faults[0]
+		if fault.Times > 0 {
+			if fault.Times--; fault.Times == 0 {
+				s.faults[method] = faults[1:]
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if fault != nil {
+		if fault.Delay > 0 {
+			timer := time.NewTimer(fault.Delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			}
+		}
+		if fault.Err != nil {
+			return nil, fault.Err
+		}
+	}
+	return next(ctx, method, params)
+}
+
+func (s *Server) track(session *server.Session) {
+	s.mu.Lock()
+	if _, ok := s.sessions[session]; ok {
+		s.mu.Unlock()
+		return
+	}
+	s.sessions[session] = struct{}{}
+	s.mu.Unlock()
+
+	session.OnClose(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.sessions, session)
+	})
+}
+
+func replace[T any](items []T, item T, same func(T) bool) []T {
+	if i := slices.IndexFunc(items, same); i >= 0 {
+		items = slices.Clone(items)
+		items[i] = item
+		return items
+	}
+	return append(slices.Clip(items), item)
+}
+
+type toolHandler struct{ s *Server }
+
+func (h toolHandler) List(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error) {
+	h.s.mu.Lock()
+	tools := h.s.tools
+	h.s.mu.Unlock()
+	page, next, err := handlers.Paginate(h.s.paginator, tools, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ListToolsResult{Tools: append([]mcp.Tool{}, page...), NextCursor: next}, nil
+}
+
+func (h toolHandler) Call(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	h.s.mu.Lock()
+	fn, ok := h.s.toolFuncs[name]
+	h.s.mu.Unlock()
+	if !ok {
+		return nil, mcp.NewError(mcp.ErrorCodeInvalidParams, fmt.Sprintf("unknown tool: %s", name))
+	}
+	return fn(ctx, arguments)
+}
+
+type promptHandler struct{ s *Server }
+
+func (h promptHandler) List(ctx context.Context, cursor *string) (*mcp.ListPromptsResult, error) {
+	h.s.mu.Lock()
+	prompts := h.s.prompts
+	h.s.mu.Unlock()
+	page, next, err := handlers.Paginate(h.s.paginator, prompts, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ListPromptsResult{Prompts: append([]mcp.Prompt{}, page...), NextCursor: next}, nil
+}
+
+func (h promptHandler) Get(ctx context.Context, name string, arguments map[string]string) (*mcp.GetPromptResult, error) {
+	h.s.mu.Lock()
+	fn, ok := h.s.promptFuncs[name]
+	h.s.mu.Unlock()
+	if !ok {
+		return nil, mcp.NewError(mcp.ErrorCodeInvalidParams, fmt.Sprintf("unknown prompt: %s", name))
+	}
+	return fn(ctx, arguments)
+}
+
+type resourceHandler struct{ s *Server }
+
+func (h resourceHandler) List(ctx context.Context, cursor *string) (*mcp.ListResourcesResult, error) {
+	h.s.mu.Lock()
+	resources := h.s.resources
+	h.s.mu.Unlock()
+	page, next, err := handlers.Paginate(h.s.paginator, resources, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ListResourcesResult{Resources: append([]mcp.Resource{}, page...), NextCursor: next}, nil
+}
+
+func (h resourceHandler) Read(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	h.s.mu.Lock()
+	fn, ok := h.s.resourceFuncs[uri]
+	h.s.mu.Unlock()
+	if !ok {
+		return nil, mcp.NewError(mcp.ErrorCodeInvalidParams, fmt.Sprintf("unknown resource: %s", uri))
+	}
+	return fn(ctx, uri)
+}
+
+func (h resourceHandler) Subscribe(ctx context.Context, uri string) error {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	h.s.subscriptions[uri] = true
+	return nil
+}
+
+func (h resourceHandler) Unsubscribe(ctx context.Context, uri string) error {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	delete(h.s.subscriptions, uri)
+	return nil
+}
@@ -0,0 +1,318 @@
+package mcptest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/mcptest"
+)
+
+func newEchoServer(opts ...mcptest.Option) *mcptest.Server {
+	s := mcptest.NewServer(opts...)
+	s.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		result := &mcp.CallToolResult{}
+		result.AddTextContent(mcp.NewTextContent(fmt.Sprint(arguments["text"])))
+		return result, nil
+	})
+	return s
+}
+
+func callEcho(c client.MCPClient, text string) error {
+	_, err := c.CallTool(context.Background(), "echo", map[string]interface{}{"text": text})
+	return err
+}
+
+func TestFaults(t *testing.T) {
+	s := newEchoServer()
+	c := s.Connect(t)
+
+	s.Inject(mcp.MethodToolsCall, mcptest.Fault{Err: mcp.NewError(mcp.ErrorCodeInvalidParams, "bad"), Times: 1})
+	s.Inject(mcp.MethodToolsCall, mcptest.Fault{Err: errors.New("boom"), Times: 2})
+
+	// Faults apply in turn, each to its number of requests
+	wantCodes := []int{mcp.ErrorCodeInvalidParams, mcp.ErrorCodeInternalError, mcp.ErrorCodeInternalError}
+	for i, want := range wantCodes {
+		var rpcErr *mcp.JSONRPCErrorData
+		if err := callEcho(c, "hi"); !errors.As(err, &rpcErr) || rpcErr.Code != want {
+			t.Errorf("call %d: error = %v, want code %d", i, err, want)
+		}
+	}
+	if err := callEcho(c, "hi"); err != nil {
+		t.Errorf("call after the faults: %v", err)
+	}
+
+	// A fault without Times applies until cleared
+	s.Inject(mcp.MethodToolsCall, mcptest.Fault{Err: errors.New("down")})
+	for i := 0; i < 3; i++ {
+		if err := callEcho(c, "hi"); err == nil {
+			t.Errorf("call %d succeeded with a permanent fault", i)
+		}
+	}
+	s.ClearFaults()
+	if err := callEcho(c, "hi"); err != nil {
+		t.Errorf("call after ClearFaults: %v", err)
+	}
+	// Faults only apply to their method
+	s.Inject(mcp.MethodToolsList, mcptest.Fault{Err: errors.New("down")})
+	if err := callEcho(c, "hi"); err != nil {
+		t.Errorf("tools/call with a tools/list fault: %v", err)
+	}
+}
+
+func TestFaultDelay(t *testing.T) {
+	s := newEchoServer()
+	c := s.Connect(t)
+
+	s.Inject(mcp.MethodToolsCall, mcptest.Fault{Delay: 50 * time.Millisecond, Times: 1})
+	start := time.Now()
+	if err := callEcho(c, "hi"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("call returned after %v, want the delay", elapsed)
+	}
+
+	// The delay is cut short when the request is cancelled
+	s.Inject(mcp.MethodToolsCall, mcptest.Fault{Delay: time.Minute, Times: 1})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	if _, err := c.CallTool(ctx, "echo", nil); err == nil {
+		t.Error("cancelled call succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled call returned after %v", elapsed)
+	}
+}
+
+func TestCalls(t *testing.T) {
+	s := newEchoServer()
+	c := s.Connect(t)
+
+	// notifications/initialized is handled concurrently with what follows
+	deadline := time.Now().Add(5 * time.Second)
+	for len(s.CallsTo(mcp.MethodNotificationInitialized)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	s.AssertCalled(t, mcp.MethodInitialize)
+	s.AssertCalled(t, mcp.MethodNotificationInitialized)
+	s.AssertNotCalled(t, mcp.MethodToolsCall)
+
+	callEcho(c, "a")
+	callEcho(c, "b")
+	c.CallTool(context.Background(), "missing", nil)
+	s.AssertCallCount(t, mcp.MethodToolsCall, 3)
+	s.AssertToolCalled(t, "echo", map[string]interface{}{"text": "b"})
+	s.AssertToolCalled(t, "missing", nil)
+
+	calls := s.ToolCalls("echo")
+	if len(calls) != 2 || calls[0]["text"] != "a" || calls[1]["text"] != "b" {
+		t.Errorf("ToolCalls = %v", calls)
+	}
+	if n := len(s.Calls()); n != 5 {
+		t.Errorf("Calls() has %d messages, want 5", n)
+	}
+
+	s.ResetCalls()
+	s.AssertNotCalled(t, mcp.MethodToolsCall)
+	if n := len(s.Calls()); n != 0 {
+		t.Errorf("Calls() after ResetCalls has %d messages", n)
+	}
+}
+
+// recorder is a testing.TB recording the failures of assertions
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertFailures(t *testing.T) {
+	s := newEchoServer()
+	c := s.Connect(t)
+	callEcho(c, "a")
+
+	tests := []struct {
+		name   string
+		assert func(tb testing.TB)
+		want   string
+	}{
+		{"AssertCalled", func(tb testing.TB) { s.AssertCalled(tb, mcp.MethodPing) }, "expected a call to ping, got none"},
+		{"AssertNotCalled", func(tb testing.TB) { s.AssertNotCalled(tb, mcp.MethodToolsCall) }, "expected no call to tools/call, got 1"},
+		{"AssertCallCount", func(tb testing.TB) { s.AssertCallCount(tb, mcp.MethodToolsCall, 2) }, "expected 2 calls to tools/call, got 1"},
+		{"AssertToolCalled none", func(tb testing.TB) { s.AssertToolCalled(tb, "other", nil) }, "expected a call to tool other, got none"},
+		{"AssertToolCalled arguments", func(tb testing.TB) {
+			s.AssertToolCalled(tb, "echo", map[string]interface{}{"text": "b"})
+		}, "expected a call to tool echo with arguments map[text:b], got [map[text:a]]"},
+	}
+	for _, tt := range tests {
+		r := &recorder{TB: t}
+		tt.assert(r)
+		if len(r.errors) != 1 || r.errors[0] != tt.want {
+			t.Errorf("%s reported %q, want %q", tt.name, r.errors, tt.want)
+		}
+	}
+}
+
+func TestNotify(t *testing.T) {
+	s := mcptest.NewServer()
+	received := make(chan string, 8)
+	var clients []*client.Client
+	for i := 0; i < 2; i++ {
+		c := client.NewClient(s.Transport())
+		c.OnNotification(mcp.MethodNotificationMessage, func(ctx context.Context, method string, params json.RawMessage) {
+			received <- string(params)
+		})
+		c.OnNotification(mcp.MethodNotificationToolsListChanged, func(ctx context.Context, method string, params json.RawMessage) {
+			received <- method
+		})
+		t.Cleanup(func() { c.Close() })
+		if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, ""); err != nil {
+			t.Fatal(err)
+		}
+		clients = append(clients, c)
+	}
+
+	next := func() string {
+		t.Helper()
+		select {
+		case message := <-received:
+			return message
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a notification")
+			return ""
+		}
+	}
+
+	// Every connected client is notified
+	if err := s.Notify(mcp.MethodNotificationMessage, map[string]string{"level": "info", "data": "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if message := next(); !strings.Contains(message, `"hi"`) {
+			t.Errorf("notification params = %s", message)
+		}
+	}
+
+	// Changing the tools sends list_changed, but not to closed clients
+	clients[1].Close()
+	s.AddToolResult(mcp.Tool{Name: "a"}, &mcp.CallToolResult{Content: []mcp.Content{}})
+	if message := next(); message != mcp.MethodNotificationToolsListChanged {
+		t.Errorf("notification = %s", message)
+	}
+	select {
+	case message := <-received:
+		t.Errorf("unexpected notification %s", message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestResources(t *testing.T) {
+	s := mcptest.NewServer()
+	const uri = "file:///a.txt"
+	s.AddResourceText(mcp.Resource{Uri: uri, Name: "a.txt"}, "content")
+	c := s.Connect(t)
+
+	result, err := c.ReadResource(context.Background(), uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Contents) != 1 {
+		t.Fatalf("contents = %+v", result.Contents)
+	}
+	contents, ok := result.Contents[0].(mcp.TextResourceContents)
+	if !ok || contents.Text != "content" {
+		t.Errorf("contents = %+v", result.Contents[0])
+	}
+
+	if s.Subscribed(uri) {
+		t.Error("subscribed before SubscribeResource")
+	}
+	if err := c.SubscribeResource(context.Background(), uri); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Subscribed(uri) {
+		t.Error("not subscribed after SubscribeResource")
+	}
+
+	s.RemoveResource(uri)
+	if _, err := c.ReadResource(context.Background(), uri); err == nil {
+		t.Error("read of a removed resource succeeded")
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	s := mcptest.NewServer(mcptest.WithPageSize(2), mcptest.WithServerInfo("paged", "2.0.0"))
+	for _, name := range []string{"a", "b", "c"} {
+		s.AddToolResult(mcp.Tool{Name: name}, &mcp.CallToolResult{Content: []mcp.Content{}})
+	}
+	c := client.NewClient(s.Transport())
+	defer c.Close()
+	result, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ServerInfo.Name != "paged" || result.ServerInfo.Version != "2.0.0" {
+		t.Errorf("server info = %+v", result.ServerInfo)
+	}
+
+	tools, err := c.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools.Tools) != 2 || tools.NextCursor == nil {
+		t.Fatalf("first page = %+v", tools)
+	}
+	tools, err = c.ListTools(context.Background(), tools.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "c" || tools.NextCursor != nil {
+		t.Errorf("last page = %+v", tools)
+	}
+}
+
+func TestTransportClose(t *testing.T) {
+	s := newEchoServer()
+	s.Inject(mcp.MethodToolsCall, mcptest.Fault{Delay: time.Minute})
+	transport := s.Transport()
+	c := client.NewClient(transport)
+	if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "test", Version: "1.0.0"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closing the connection fails the pending request and closes the session
+	done := make(chan error, 1)
+	go func() { done <- callEcho(c, "hi") }()
+	time.Sleep(10 * time.Millisecond)
+	transport.Close()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("pending call succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending call not failed by Close")
+	}
+	if err := transport.Send(context.Background(), json.RawMessage(`{}`)); !errors.Is(err, client.ErrConnectionClosed) {
+		t.Errorf("Send after Close = %v, want %v", err, client.ErrConnectionClosed)
+	}
+	select {
+	case <-transport.Session().Done():
+	default:
+		t.Error("session still open after Close")
+	}
+	c.Close()
+}
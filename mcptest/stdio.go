@@ -0,0 +1,66 @@
+package mcptest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server"
+)
+
+// childEnv names the mock server a re-executed test binary serves over stdio
+const childEnv = "MCPTEST_STDIO_SERVER"
+
+// ServeIfChild serves a mock server over stdio and exits if the process is a
+// test binary re-executed by StartStdio; otherwise it returns immediately.
+// servers maps the names given to StartStdio to functions building the mock
+// servers. It must be called first thing in TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		mcptest.ServeIfChild(map[string]func() *mcptest.Server{
+//			"weather": newWeatherServer,
+//		})
+//		os.Exit(m.Run())
+//	}
+func ServeIfChild(servers map[string]func() *Server) {
+	name, ok := os.LookupEnv(childEnv)
+	if !ok {
+		return
+	}
+	newServer, ok := servers[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "mcptest: unknown server %q\n", name)
+		os.Exit(2)
+	}
+	s := newServer()
+	if err := server.NewStdioServer(*s.MCPServer()).Serve(); err != nil {
+		fmt.Fprintf(os.Stderr, "mcptest: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// StartStdio re-executes the test binary as the named mock server, registered
+// with ServeIfChild, and returns an initialized client talking to it over
+// stdio, which is closed when the test ends. The mock server runs in the child
+// process, so its calls cannot be inspected from the test.
+func StartStdio(t testing.TB, name string, opts ...client.ClientOption) *client.StdioMCPClient {
+	t.Helper()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to locate test binary: %v", err)
+	}
+	opts = append([]client.ClientOption{client.WithEnv(childEnv + "=" + name)}, opts...)
+	c, err := client.NewStdioMCPClientWithOptions(executable, []string{"-test.run=^$"}, opts...)
+	if err != nil {
+		t.Fatalf("failed to start mock server %s: %v", name, err)
+	}
+	t.Cleanup(func() { c.Close() })
+	if _, err := c.Initialize(context.Background(), mcp.ClientCapabilities{}, mcp.Implementation{Name: "mcptest", Version: "1.0.0"}, ""); err != nil {
+		t.Fatalf("failed to initialize mock server %s: %v", name, err)
+	}
+	return c
+}
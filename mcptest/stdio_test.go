@@ -0,0 +1,38 @@
+package mcptest_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/mcptest"
+)
+
+func TestMain(m *testing.M) {
+	mcptest.ServeIfChild(map[string]func() *mcptest.Server{
+		"echo": func() *mcptest.Server {
+			return newEchoServer()
+		},
+	})
+	os.Exit(m.Run())
+}
+
+func TestStartStdio(t *testing.T) {
+	c := mcptest.StartStdio(t, "echo")
+
+	tools, err := c.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "echo" {
+		t.Errorf("tools = %+v", tools.Tools)
+	}
+	result, err := c.CallTool(context.Background(), "echo", map[string]interface{}{"text": "over stdio"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "over stdio" {
+		t.Errorf("content = %+v", result.Content)
+	}
+}
@@ -0,0 +1,96 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/WePrompt/gomcp/client"
+	"github.com/WePrompt/gomcp/server"
+)
+
+var _ client.Transport = &Transport{}
+
+var sessionCount atomic.Int64
+
+// Transport is an in-process client.Transport connected to an MCPServer. Each
+// Transport is a separate connection with its own server session. Messages are
+// handled concurrently, as a server reading from a real connection would.
+type Transport struct {
+	server  *server.MCPServer
+	session *server.Session
+
+	messages chan json.RawMessage
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+// NewTransport returns a transport connected to s
+func NewTransport(s *server.MCPServer) *Transport {
+	t := &Transport{
+		server:   s,
+		session:  server.NewSession(fmt.Sprintf("mcptest-%d", sessionCount.Add(1))),
+		messages: make(chan json.RawMessage, 16),
+	}
+	t.ctx, t.cancel = context.WithCancel(server.ContextWithSession(context.Background(), t.session))
+	t.session.SetSender(func(message json.RawMessage) error {
+		if !t.deliver(message) {
+			return client.ErrConnectionClosed
+		}
+		return nil
+	})
+	return t
+}
+
+// Session returns the server session of the connection
+func (t *Transport) Session() *server.Session {
+	return t.session
+}
+
+func (t *Transport) Send(ctx context.Context, message json.RawMessage) error {
+	if t.ctx.Err() != nil {
+		return client.ErrConnectionClosed
+	}
+	message = append(json.RawMessage(nil), message...)
+	go func() {
+		response, _ := t.server.HandleMessage(t.ctx, message)
+		if response != nil {
+			t.deliver(response)
+		}
+	}()
+	return nil
+}
+
+// deliver queues a message for Receive, unless the transport is closed
+func (t *Transport) deliver(message json.RawMessage) bool {
+	select {
+	case t.messages <- message:
+		return true
+	case <-t.ctx.Done():
+		return false
+	}
+}
+
+func (t *Transport) Receive() (json.RawMessage, error) {
+	select {
+	case message := <-t.messages:
+		return message, nil
+	case <-t.ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// Close ends the connection, cancelling the requests the server is handling
+// and closing the session
+func (t *Transport) Close() error {
+	t.closeOnce.Do(func() {
+		t.cancel()
+		t.session.Close()
+	})
+	return nil
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server/handlers"
+)
+
+var messageSeeds = []string{
+	`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"fuzz","version":"1"}}}`,
+	`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	`{"jsonrpc":"2.0","id":"a","method":"ping"}`,
+	`{"jsonrpc":"2.0","id":2,"method":"tools/list","params":{"cursor":"x"}}`,
+	`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+	`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{"text":1}}}`,
+	`{"jsonrpc":"2.0","id":5,"method":"resources/read","params":{"uri":"file:///a"}}`,
+	`{"jsonrpc":"2.0","id":6,"method":"prompts/get","params":{"name":"p","arguments":{"a":"b"}}}`,
+	`{"jsonrpc":"2.0","id":7,"method":"logging/setLevel","params":{"level":"debug"}}`,
+	`{"jsonrpc":"2.0","id":8,"method":"completion/complete","params":{"ref":{"type":"ref/prompt","name":"p"},"argument":{"name":"a","value":""}}}`,
+	`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":3}}`,
+	`{"jsonrpc":"2.0","id":9,"result":{}}`,
+	`{"jsonrpc":"1.0","id":10,"method":"ping"}`,
+	`{"jsonrpc":"2.0","id":null,"method":"ping"}`,
+	`{"jsonrpc":"2.0","id":[],"method":"ping"}`,
+	`{"jsonrpc":"2.0","id":11,"method":"initialize","params":null}`,
+	`[]`,
+	`{`,
+}
+
+func newFuzzServer() *MCPServer {
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{
+		Name: "echo",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: mcp.ToolInputSchemaProperties{"text": map[string]interface{}{"type": "string"}},
+			Required:   []string{"text"},
+		},
+	}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		text, _ := arguments["text"].(string)
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.NewTextContent(text)}}, nil
+	})
+	return NewMCPServer(
+		WithToolHandler(tools),
+		WithPromptHandler(handlers.NewDefaultPromptHandler()),
+		WithResourceHandler(handlers.NewDefaultResourceHandler()),
+		WithResourceCapabilities(true, true),
+		WithLogging(),
+		WithCompletions(),
+		WithToolArgumentValidation(),
+		WithToolOutputValidation(),
+		WithLogger(log.New(io.Discard, "", 0)),
+	)
+}
+
+// checkResponse checks that response is a well-formed answer to message, or
+// nil if message must not be answered
+func checkResponse(t *testing.T, message, response json.RawMessage) {
+	t.Helper()
+	var envelope struct {
+		Id     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	isObject := json.Unmarshal(message, &envelope) == nil
+	if isObject && (envelope.Method == "" || len(envelope.Id) == 0) {
+		if response != nil {
+			t.Fatalf("%s must not be answered, got %s", message, response)
+		}
+		return
+	}
+	if response == nil {
+		t.Fatalf("%s was not answered", message)
+	}
+
+	if bytes.ContainsAny(response, "\r\n") {
+		t.Fatalf("response %q spans several lines", response)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(response, &fields); err != nil {
+		t.Fatalf("response %s is not a JSON object: %v", response, err)
+	}
+	if string(fields["jsonrpc"]) != `"2.0"` {
+		t.Fatalf("response %s has no jsonrpc version", response)
+	}
+	_, hasResult := fields["result"]
+	_, hasError := fields["error"]
+	if hasResult == hasError {
+		t.Fatalf("response %s must have either a result or an error", response)
+	}
+	if hasError {
+		var rpcErr mcp.JSONRPCErrorData
+		if err := json.Unmarshal(fields["error"], &rpcErr); err != nil || rpcErr.Message == "" {
+			t.Fatalf("response %s has a malformed error", response)
+		}
+	}
+
+	var request mcp.JSONRPCRequest
+	if json.Unmarshal(message, &request) == nil {
+		id, _ := json.Marshal(request.Id)
+		if !bytes.Equal(fields["id"], id) {
+			t.Fatalf("response %s does not carry the id %s of the request", response, id)
+		}
+	}
+}
+
+func FuzzHandleMessage(f *testing.F) {
+	for _, seed := range messageSeeds {
+		f.Add([]byte(seed), true)
+		f.Add([]byte(seed), false)
+	}
+
+	s := newFuzzServer()
+	f.Fuzz(func(t *testing.T, message []byte, initialize bool) {
+		session := NewSession("fuzz")
+		defer session.Close()
+		ctx := ContextWithSession(context.Background(), session)
+		if initialize {
+			if _, err := s.HandleMessage(ctx, json.RawMessage(messageSeeds[0])); err != nil {
+				t.Fatalf("failed to initialize: %v", err)
+			}
+		}
+
+		response, _ := s.HandleMessage(ctx, message)
+		checkResponse(t, message, response)
+	})
+}
+
+func FuzzStdioServer(f *testing.F) {
+	f.Add([]byte(messageSeeds[0] + "\n" + messageSeeds[1] + "\n" + messageSeeds[4] + "\n"))
+	f.Add([]byte(messageSeeds[0] + "\r\n\r\n" + messageSeeds[2]))
+	f.Add([]byte("\n \n{}\n"))
+	f.Add([]byte(messageSeeds[2] + messageSeeds[2] + "\n"))
+
+	s := newFuzzServer()
+	f.Fuzz(func(t *testing.T, input []byte) {
+		var output bytes.Buffer
+		stdio := NewStdioServer(*s).WithLogger(log.New(io.Discard, "", 0))
+		stdio.in = bytes.NewReader(input)
+		stdio.out = bufio.NewWriter(&output)
+		if err := stdio.Serve(); err != nil {
+			t.Fatalf("failed to serve: %v", err)
+		}
+
+		var lines int
+		for _, line := range bytes.Split(input, []byte("\n")) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				lines++
+			}
+		}
+		responses := bytes.Split(output.Bytes(), []byte("\n"))
+		if last := responses[len(responses)-1]; len(last) > 0 {
+			t.Fatalf("output does not end with a newline: %q", last)
+		}
+		responses = responses[:len(responses)-1]
+		if len(responses) > lines {
+			t.Fatalf("%d responses to %d messages", len(responses), lines)
+		}
+		for _, response := range responses {
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(response, &fields); err != nil || string(fields["jsonrpc"]) != `"2.0"` {
+				t.Fatalf("output line %q is not a JSON-RPC message", response)
+			}
+		}
+	})
+}
@@ -78,20 +78,10 @@ func NewDefaultSystemHandler() SystemHandler {
 	return &DefaultSystemHandler{}
 }
 
+// Initialize returns an empty result; MCPServer fills in the server info,
+// capabilities and negotiated protocol version.
 func (h *DefaultSystemHandler) Initialize(ctx context.Context, capabilities mcp.ClientCapabilities, clientInfo mcp.Implementation, protocolVersion string) (*mcp.InitializeResult, error) {
-	return &mcp.InitializeResult{
-		ServerInfo: mcp.Implementation{
-			Name:    clientInfo.Name,
-			Version: clientInfo.Version,
-		},
-		ProtocolVersion: protocolVersion,
-		Capabilities: mcp.ServerCapabilities{
-			Resources: &mcp.ServerCapabilitiesResources{
-				ListChanged: true,
-				Subscribe:   true,
-			},
-		},
-	}, nil
+	return &mcp.InitializeResult{}, nil
 }
 
 func (h *DefaultSystemHandler) Ping(ctx context.Context) error {
@@ -23,6 +23,12 @@ type ToolHandler interface {
 	Call(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error)
 }
 
+// ToolChangeNotifier is implemented by tool handlers whose tools change at
+// runtime. MCPServer registers a function to be called after every change.
+type ToolChangeNotifier interface {
+	OnToolsChanged(fn func())
+}
+
 type SystemHandler interface {
 	Initialize(ctx context.Context, capabilities mcp.ClientCapabilities, clientInfo mcp.Implementation, protocolVersion string) (*mcp.InitializeResult, error)
 	Ping(ctx context.Context) error
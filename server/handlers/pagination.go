@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// DefaultPageSize is the page size used by paginators created with a
+// non-positive page size
+const DefaultPageSize = 50
+
+const cursorMACSize = 16
+
+// Paginator splits lists into pages addressed by opaque cursors. Cursors are
+// signed, so clients can neither forge them nor alter the position they encode.
+type Paginator struct {
+	pageSize int
+	key      []byte
+}
+
+// NewPaginator creates a paginator that signs cursors with a random key. Its
+// cursors are only valid for the lifetime of the process.
+func NewPaginator(pageSize int) *Paginator {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic("failed to generate cursor key: " + err.Error())
+	}
+	return NewPaginatorWithKey(pageSize, key)
+}
+
+// NewPaginatorWithKey creates a paginator that signs cursors with the given key,
+// so that cursors stay valid across restarts or server replicas sharing the key.
+func NewPaginatorWithKey(pageSize int, key []byte) *Paginator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return &Paginator{pageSize: pageSize, key: key}
+}
+
+func (p *Paginator) PageSize() int {
+	return p.pageSize
+}
+
+// Paginate returns the page of items starting at cursor, or at the beginning
+// if cursor is nil, along with the cursor of the next page, which is nil on the
+// last page. A cursor that was not issued by p is reported as an
+// invalid-params error.
+func Paginate[T any](p *Paginator, items []T, cursor *string) ([]T, *string, error) {
+	start := 0
+	if cursor != nil {
+		offset, ok := p.decodeCursor(*cursor)
+		if !ok {
+			return nil, nil, mcp.NewError(mcp.ErrorCodeInvalidParams, "invalid cursor")
+		}
+		start = min(offset, len(items))
+	}
+
+	end := min(start+p.pageSize, len(items))
+	var next *string
+	if end < len(items) {
+		c := p.encodeCursor(end)
+		next = &c
+	}
+	return items[start:end], next, nil
+}
+
+func (p *Paginator) encodeCursor(offset int) string {
+	b := binary.AppendUvarint(nil, uint64(offset))
+	b = append(b, p.mac(b)...)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (p *Paginator) decodeCursor(cursor string) (int, bool) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(b) <= cursorMACSize {
+		return 0, false
+	}
+	payload, mac := b[:len(b)-cursorMACSize], b[len(b)-cursorMACSize:]
+	if !hmac.Equal(mac, p.mac(payload)) {
+		return 0, false
+	}
+	offset, n := binary.Uvarint(payload)
+	if n != len(payload) || offset > uint64(^uint(0)>>1) {
+		return 0, false
+	}
+	return int(offset), true
+}
+
+func (p *Paginator) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, p.key)
+	h.Write(payload)
+	return h.Sum(nil)[:cursorMACSize]
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+func collectPages(t *testing.T, p *Paginator, items []int) [][]int {
+	t.Helper()
+	var pages [][]int
+	var cursor *string
+	for {
+		page, next, err := Paginate(p, items, cursor)
+		if err != nil {
+			t.Fatalf("Paginate: %v", err)
+		}
+		pages = append(pages, page)
+		if next == nil {
+			return pages
+		}
+		cursor = next
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	pages := collectPages(t, NewPaginator(2), items)
+	want := [][]int{{1, 2}, {3, 4}, {5}}
+	if !slices.EqualFunc(pages, want, slices.Equal) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+
+	// A list that fits in a page has no next cursor
+	if pages := collectPages(t, NewPaginator(5), items); len(pages) != 1 {
+		t.Errorf("pages = %v, want a single page", pages)
+	}
+	if pages := collectPages(t, NewPaginator(2), nil); len(pages) != 1 || len(pages[0]) != 0 {
+		t.Errorf("pages of an empty list = %v", pages)
+	}
+
+	if size := NewPaginator(0).PageSize(); size != DefaultPageSize {
+		t.Errorf("PageSize() = %d, want %d", size, DefaultPageSize)
+	}
+}
+
+func TestPaginateShrunkList(t *testing.T) {
+	p := NewPaginator(2)
+	_, next, err := Paginate(p, []int{1, 2, 3, 4, 5}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The list lost items since the cursor was issued
+	page, next, err := Paginate(p, []int{1}, next)
+	if err != nil || len(page) != 0 || next != nil {
+		t.Errorf("page = %v, next = %v, err = %v, want an empty last page", page, next, err)
+	}
+}
+
+func TestPaginateTamperedCursor(t *testing.T) {
+	p := NewPaginatorWithKey(2, []byte("key"))
+	items := []int{1, 2, 3, 4, 5}
+	_, next, err := Paginate(p, items, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(*next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flipped := slices.Clone(raw)
+	flipped[0] ^= 1
+	forged := NewPaginatorWithKey(2, []byte("other key")).encodeCursor(4)
+
+	cursors := map[string]string{
+		"altered offset": base64.RawURLEncoding.EncodeToString(flipped),
+		"truncated":      base64.RawURLEncoding.EncodeToString(raw[:len(raw)-1]),
+		"other key":      forged,
+		"not base64":     "!!!",
+		"empty":          "",
+		"padded":         *next + "==",
+	}
+	for name, cursor := range cursors {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := Paginate(p, items, &cursor)
+			var rpcErr *mcp.JSONRPCErrorData
+			if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorCodeInvalidParams {
+				t.Errorf("error = %v, want an invalid-params error", err)
+			}
+		})
+	}
+}
+
+func TestPaginateSharedKey(t *testing.T) {
+	key := []byte("shared key")
+	items := []int{1, 2, 3}
+	_, next, err := Paginate(NewPaginatorWithKey(2, key), items, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another replica with the same key accepts the cursor
+	page, _, err := Paginate(NewPaginatorWithKey(2, key), items, next)
+	if err != nil || !slices.Equal(page, []int{3}) {
+		t.Errorf("page = %v, err = %v, want [3]", page, err)
+	}
+}
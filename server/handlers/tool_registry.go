@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// ToolFunc handles a call to a tool registered with a ToolRegistry
+type ToolFunc func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error)
+
+// ToolOption sets metadata of a tool registered with a ToolRegistry
+type ToolOption func(*mcp.Tool)
+
+func WithToolTitle(title string) ToolOption {
+	return func(t *mcp.Tool) {
+		t.Title = &title
+	}
+}
+
+func WithToolDescription(description string) ToolOption {
+	return func(t *mcp.Tool) {
+		t.Description = &description
+	}
+}
+
+func WithOutputSchema(schema mcp.ToolOutputSchema) ToolOption {
+	return func(t *mcp.Tool) {
+		t.OutputSchema = &schema
+	}
+}
+
+// WithReadOnlyHint declares whether the tool leaves its environment unmodified
+func WithReadOnlyHint(readOnly bool) ToolOption {
+	return annotate(func(a *mcp.ToolAnnotations) {
+		a.ReadOnlyHint = &readOnly
+	})
+}
+
+// WithDestructiveHint declares whether the tool may perform destructive updates
+func WithDestructiveHint(destructive bool) ToolOption {
+	return annotate(func(a *mcp.ToolAnnotations) {
+		a.DestructiveHint = &destructive
+	})
+}
+
+// WithIdempotentHint declares whether repeated calls with the same arguments
+// have no additional effect
+func WithIdempotentHint(idempotent bool) ToolOption {
+	return annotate(func(a *mcp.ToolAnnotations) {
+		a.IdempotentHint = &idempotent
+	})
+}
+
+// WithOpenWorldHint declares whether the tool interacts with external entities
+func WithOpenWorldHint(openWorld bool) ToolOption {
+	return annotate(func(a *mcp.ToolAnnotations) {
+		a.OpenWorldHint = &openWorld
+	})
+}
+
+func annotate(fn func(*mcp.ToolAnnotations)) ToolOption {
+	return func(t *mcp.Tool) {
+		if t.Annotations == nil {
+			t.Annotations = &mcp.ToolAnnotations{}
+		}
+		fn(t.Annotations)
+	}
+}
+
+// ToolRegistry is a ToolHandler that serves a set of tools registered at
+// runtime, listing them in registration order. It implements ToolChangeNotifier.
+type ToolRegistry struct {
+	paginator *Paginator
+
+	mu       sync.RWMutex
+	tools    []mcp.Tool
+	funcs    map[string]ToolFunc
+	onChange []func()
+}
+
+func NewToolRegistry() *ToolRegistry {
+	return NewToolRegistryWithPaginator(NewPaginator(DefaultPageSize))
+}
+
+func NewToolRegistryWithPaginator(paginator *Paginator) *ToolRegistry {
+	return &ToolRegistry{
+		paginator: paginator,
+		funcs:     make(map[string]ToolFunc),
+	}
+}
+
+// Register adds a tool, replacing any tool registered under the same name
+func (r *ToolRegistry) Register(tool mcp.Tool, fn ToolFunc, opts ...ToolOption) {
+	for _, opt := range opts {
+		opt(&tool)
+	}
+
+	r.mu.Lock()
+	// List reads the slice without holding the lock, so it is never modified
+	// in place
+	tools := make([]mcp.Tool, 0, len(r.tools)+1)
+	replaced := false
+	for _, t := range r.tools {
+		if t.Name == tool.Name {
+			t, replaced = tool, true
+		}
+		tools = append(tools, t)
+	}
+	if !replaced {
+		tools = append(tools, tool)
+	}
+	r.tools = tools
+	r.funcs[tool.Name] = fn
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	notify(onChange)
+}
+
+// Unregister removes the named tool and reports whether it was registered
+func (r *ToolRegistry) Unregister(name string) bool {
+	r.mu.Lock()
+	if _, ok := r.funcs[name]; !ok {
+		r.mu.Unlock()
+		return false
+	}
+	delete(r.funcs, name)
+	for i := range r.tools {
+		if r.tools[i].Name == name {
+			r.tools = append(r.tools[:i:i], r.tools[i+1:]...)
+			break
+		}
+	}
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	notify(onChange)
+	return true
+}
+
+// OnToolsChanged registers fn to be called after a tool is registered,
+// replaced or unregistered
+func (r *ToolRegistry) OnToolsChanged(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = append(r.onChange, fn)
+}
+
+func notify(fns []func()) {
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+func (r *ToolRegistry) List(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error) {
+	r.mu.RLock()
+	tools := r.tools
+	r.mu.RUnlock()
+
+	page, next, err := Paginate(r.paginator, tools, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ListToolsResult{
+		Tools:      append([]mcp.Tool{}, page...),
+		NextCursor: next,
+	}, nil
+}
+
+func (r *ToolRegistry) Call(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	r.mu.RLock()
+	fn, ok := r.funcs[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, mcp.NewError(mcp.ErrorCodeInvalidParams, fmt.Sprintf("unknown tool: %s", name))
+	}
+	return fn(ctx, arguments)
+}
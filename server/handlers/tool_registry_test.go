@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+func resultText(text string) ToolFunc {
+	return func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.NewTextContent(text)}}, nil
+	}
+}
+
+func toolNames(tools []mcp.Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+func TestToolRegistryOptions(t *testing.T) {
+	r := NewToolRegistry()
+	r.Register(mcp.Tool{Name: "delete"}, resultText("ok"),
+		WithToolTitle("Delete file"),
+		WithToolDescription("Deletes a file"),
+		WithDestructiveHint(true),
+		WithIdempotentHint(true),
+		WithOpenWorldHint(false),
+	)
+
+	result, err := r.List(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tool := result.Tools[0]
+	if tool.Title == nil || *tool.Title != "Delete file" || tool.Description == nil || *tool.Description != "Deletes a file" {
+		t.Errorf("title = %v, description = %v", tool.Title, tool.Description)
+	}
+	a := tool.Annotations
+	if a.IsReadOnly() || !a.IsDestructive() || !a.IsIdempotent() || a.IsOpenWorld() {
+		t.Errorf("annotations = %+v", a)
+	}
+}
+
+func TestToolRegistryReplaceAndUnregister(t *testing.T) {
+	r := NewToolRegistry()
+	r.Register(mcp.Tool{Name: "a"}, resultText("a"))
+	r.Register(mcp.Tool{Name: "b"}, resultText("b"))
+	before, err := r.List(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replacing a tool keeps its position
+	r.Register(mcp.Tool{Name: "a"}, resultText("a2"), WithToolTitle("A"))
+	after, err := r.List(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := toolNames(after.Tools); len(names) != 2 || names[0] != "a" || after.Tools[0].Title == nil {
+		t.Errorf("tools after replacing a = %+v", after.Tools)
+	}
+	if before.Tools[0].Title != nil {
+		t.Error("an earlier List result was modified")
+	}
+	result, err := r.Call(context.Background(), "a", nil)
+	if err != nil || result.Content[0].(mcp.TextContent).Text != "a2" {
+		t.Errorf("Call(a) = %v, %v, want the replacement", result, err)
+	}
+
+	if !r.Unregister("a") || r.Unregister("a") {
+		t.Error("Unregister does not report whether the tool was registered")
+	}
+	after, err = r.List(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := toolNames(after.Tools); len(names) != 1 || names[0] != "b" {
+		t.Errorf("tools after unregistering a = %v", names)
+	}
+}
+
+func TestToolRegistryUnknownTool(t *testing.T) {
+	_, err := NewToolRegistry().Call(context.Background(), "missing", nil)
+	var rpcErr *mcp.JSONRPCErrorData
+	if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorCodeInvalidParams {
+		t.Errorf("error = %v, want an invalid-params error", err)
+	}
+}
+
+func TestToolRegistryPages(t *testing.T) {
+	r := NewToolRegistryWithPaginator(NewPaginator(2))
+	for _, name := range []string{"a", "b", "c"} {
+		r.Register(mcp.Tool{Name: name}, resultText(name))
+	}
+	first, err := r.List(context.Background(), nil)
+	if err != nil || len(first.Tools) != 2 || first.NextCursor == nil {
+		t.Fatalf("first page = %+v, %v", first, err)
+	}
+	second, err := r.List(context.Background(), first.NextCursor)
+	if err != nil || len(second.Tools) != 1 || second.Tools[0].Name != "c" || second.NextCursor != nil {
+		t.Errorf("second page = %+v, %v", second, err)
+	}
+}
+
+func TestToolRegistryOnToolsChanged(t *testing.T) {
+	r := NewToolRegistry()
+	changes := 0
+	r.OnToolsChanged(func() {
+		// Called without holding the registry's lock
+		r.List(context.Background(), nil)
+		changes++
+	})
+
+	r.Register(mcp.Tool{Name: "a"}, resultText("a"))
+	r.Register(mcp.Tool{Name: "a"}, resultText("a2"))
+	r.Unregister("a")
+	if changes != 3 {
+		t.Errorf("%d changes reported, want 3", changes)
+	}
+	r.Unregister("a")
+	if changes != 3 {
+		t.Error("unregistering an unknown tool reported a change")
+	}
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// RequestHandlerFunc handles a single request or notification dispatched by an MCPServer
+type RequestHandlerFunc func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error)
+
+// Interceptor wraps the dispatch of every request and notification handled by an
+// MCPServer. It sees the method and raw params before the handler runs and the
+// result or error after it returns, and may short-circuit the call by returning
+// without invoking next. The client session, if any, is available through
+// SessionFromContext.
+type Interceptor func(ctx context.Context, method string, params json.RawMessage, next RequestHandlerFunc) (json.RawMessage, error)
+
+// WithInterceptors appends interceptors to the server's chain. The first
+// interceptor is the outermost one.
+func WithInterceptors(interceptors ...Interceptor) ServerOption {
+	return func(s *MCPServer) {
+		s.interceptors = append(s.interceptors, interceptors...)
+	}
+}
+
+func chainInterceptors(interceptors []Interceptor, handler RequestHandlerFunc) RequestHandlerFunc {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+			return interceptor(ctx, method, params, next)
+		}
+	}
+	return handler
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// echoTools is a ToolHandler recording whether it was called
+type echoTools struct {
+	called bool
+}
+
+func (h *echoTools) List(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error) {
+	return &mcp.ListToolsResult{Tools: []mcp.Tool{{Name: "echo"}}}, nil
+}
+
+func (h *echoTools) Call(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	h.called = true
+	return &mcp.CallToolResult{}, nil
+}
+
+func TestInterceptorOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, method string, params json.RawMessage, next RequestHandlerFunc) (json.RawMessage, error) {
+			calls = append(calls, name+" "+method)
+			result, err := next(ctx, method, params)
+			calls = append(calls, name+" done")
+			return result, err
+		}
+	}
+	s := NewMCPServer(WithInterceptors(record("outer")), WithInterceptors(record("inner")))
+	if _, err := s.Request(context.Background(), mcp.MethodPing, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"outer ping", "inner ping", "inner done", "outer done"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	tools := &echoTools{}
+	denied := errors.New("denied")
+	s := NewMCPServer(WithToolHandler(tools), WithInterceptors(func(ctx context.Context, method string, params json.RawMessage, next RequestHandlerFunc) (json.RawMessage, error) {
+		if method == mcp.MethodToolsCall {
+			return nil, denied
+		}
+		return next(ctx, method, params)
+	}))
+
+	params := json.RawMessage(`{"name":"echo","arguments":{}}`)
+	if _, err := s.Request(context.Background(), mcp.MethodToolsCall, params); !errors.Is(err, denied) {
+		t.Errorf("error = %v, want the interceptor's", err)
+	}
+	if tools.called {
+		t.Error("handler called although the interceptor returned early")
+	}
+	if _, err := s.Request(context.Background(), mcp.MethodToolsList, json.RawMessage(`{}`)); err != nil {
+		t.Errorf("other methods: %v", err)
+	}
+}
+
+func TestInterceptorSeesSession(t *testing.T) {
+	var clients []string
+	s := NewMCPServer(WithInterceptors(func(ctx context.Context, method string, params json.RawMessage, next RequestHandlerFunc) (json.RawMessage, error) {
+		result, err := next(ctx, method, params)
+		if session := SessionFromContext(ctx); session != nil {
+			clients = append(clients, method+" "+session.ClientInfo().Name)
+		}
+		return result, err
+	}))
+
+	ctx := ContextWithSession(context.Background(), NewSession("test"))
+	initialize := json.RawMessage(`{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"client","version":"1"}}`)
+	if _, err := s.Request(ctx, mcp.MethodInitialize, initialize); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Request(ctx, mcp.MethodPing, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"initialize client", "ping client"}
+	if !slices.Equal(clients, want) {
+		t.Errorf("interceptor saw %v, want %v", clients, want)
+	}
+}
@@ -3,8 +3,13 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/WePrompt/gomcp/mcp"
 	"github.com/WePrompt/gomcp/server/handlers"
@@ -16,7 +21,19 @@ type MCPServer struct {
 	toolHandler     handlers.ToolHandler
 	systemHandler   handlers.SystemHandler
 	notifyHandlers  map[string]handlers.NotificationHandler
+	interceptors    []Interceptor
 	serverInfo      ServerInfo
+	capabilities    mcp.ServerCapabilities
+	features        serverFeatures
+	errLogger       *log.Logger
+	requestTimeout  time.Duration
+	methodTimeouts  map[string]time.Duration
+
+	validateToolArguments bool
+	validateToolOutput    bool
+	schemas               *schemaCache
+	tools                 *toolCache
+	sessions              *sessionSet
 }
 
 type ServerInfo struct {
@@ -24,11 +41,26 @@ type ServerInfo struct {
 	version string
 }
 
+// serverFeatures holds the optional capability flags set through server options
+type serverFeatures struct {
+	resourcesSubscribe   bool
+	resourcesListChanged bool
+	promptsListChanged   bool
+	toolsListChanged     bool
+	logging              bool
+	completions          bool
+}
+
 type ServerOption func(*MCPServer)
 
 func NewMCPServer(opts ...ServerOption) *MCPServer {
 	s := &MCPServer{
 		notifyHandlers: make(map[string]handlers.NotificationHandler),
+		methodTimeouts: make(map[string]time.Duration),
+		schemas:        newSchemaCache(),
+		tools:          &toolCache{},
+		sessions:       newSessionSet(),
+		errLogger:      log.New(os.Stderr, "", log.LstdFlags),
 		serverInfo: ServerInfo{
 			name:    "default",
 			version: "1.0.0",
@@ -40,6 +72,8 @@ func NewMCPServer(opts ...ServerOption) *MCPServer {
 		opt(s)
 	}
 
+	s.capabilities = s.buildCapabilities()
+
 	// Set default handlers if not provided
 	if s.resourceHandler == nil {
 		s.resourceHandler = handlers.NewDefaultResourceHandler()
@@ -53,6 +87,9 @@ func NewMCPServer(opts ...ServerOption) *MCPServer {
 	if s.systemHandler == nil {
 		s.systemHandler = handlers.NewDefaultSystemHandler()
 	}
+	if notifier, ok := s.toolHandler.(handlers.ToolChangeNotifier); ok {
+		notifier.OnToolsChanged(s.toolsChanged)
+	}
 
 	return s
 }
@@ -63,6 +100,49 @@ func WithServerInfo(name, version string) ServerOption {
 	}
 }
 
+// WithResourceCapabilities declares whether the registered resource handler
+// supports subscriptions and sends list_changed notifications
+func WithResourceCapabilities(subscribe, listChanged bool) ServerOption {
+	return func(s *MCPServer) {
+		s.features.resourcesSubscribe = subscribe
+		s.features.resourcesListChanged = listChanged
+	}
+}
+
+// WithPromptCapabilities declares whether the registered prompt handler sends
+// list_changed notifications
+func WithPromptCapabilities(listChanged bool) ServerOption {
+	return func(s *MCPServer) {
+		s.features.promptsListChanged = listChanged
+	}
+}
+
+// WithToolCapabilities declares whether the registered tool handler sends
+// list_changed notifications. Handlers implementing handlers.ToolChangeNotifier,
+// such as handlers.ToolRegistry, have the server send them for every change.
+func WithToolCapabilities(listChanged bool) ServerOption {
+	return func(s *MCPServer) {
+		s.features.toolsListChanged = listChanged
+	}
+}
+
+// WithCompletions declares that the system handler offers argument
+// autocompletion. The completions capability is only advertised to clients
+// that negotiated protocol revision 2025-03-26 or later, which introduced it;
+// clients of earlier revisions may call completion/complete all the same.
+func WithCompletions() ServerOption {
+	return func(s *MCPServer) {
+		s.features.completions = true
+	}
+}
+
+// WithLogging declares that the server sends log messages to the client
+func WithLogging() ServerOption {
+	return func(s *MCPServer) {
+		s.features.logging = true
+	}
+}
+
 func WithResourceHandler(h handlers.ResourceHandler) ServerOption {
 	return func(s *MCPServer) {
 		s.resourceHandler = h
@@ -93,14 +173,266 @@ func WithNotificationHandler(method string, h handlers.NotificationHandler) Serv
 	}
 }
 
+// buildCapabilities derives the advertised capabilities from the handlers that
+// were registered through options, before defaults are filled in
+func (s *MCPServer) buildCapabilities() mcp.ServerCapabilities {
+	var capabilities mcp.ServerCapabilities
+	if s.resourceHandler != nil {
+		capabilities.Resources = &mcp.ServerCapabilitiesResources{
+			Subscribe:   s.features.resourcesSubscribe,
+			ListChanged: s.features.resourcesListChanged,
+		}
+	}
+	if s.promptHandler != nil {
+		capabilities.Prompts = &mcp.ServerCapabilitiesPrompts{
+			ListChanged: s.features.promptsListChanged,
+		}
+	}
+	if s.toolHandler != nil {
+		_, notifies := s.toolHandler.(handlers.ToolChangeNotifier)
+		capabilities.Tools = &mcp.ServerCapabilitiesTools{
+			ListChanged: s.features.toolsListChanged || notifies,
+		}
+	}
+	if s.features.logging {
+		capabilities.Logging = &mcp.ServerCapabilitiesLogging{}
+	}
+	return capabilities
+}
+
+// Capabilities returns the capabilities the server advertises to clients
+func (s *MCPServer) Capabilities() mcp.ServerCapabilities {
+	return s.capabilities
+}
+
+// WithLogger sets the logger used to report recovered panics
+func WithLogger(logger *log.Logger) ServerOption {
+	return func(s *MCPServer) {
+		s.errLogger = logger
+	}
+}
+
+// WithRequestTimeout sets the default deadline applied to every request.
+// A zero duration disables the deadline.
+func WithRequestTimeout(timeout time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.requestTimeout = timeout
+	}
+}
+
+// WithMethodTimeout overrides the default request deadline for a single method.
+// A zero duration disables the deadline for that method.
+func WithMethodTimeout(method string, timeout time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.methodTimeouts[method] = timeout
+	}
+}
+
+// Request dispatches a request or notification to the registered handlers,
+// passing it through the interceptor chain first. Panics in handlers or
+// interceptors are recovered and reported as internal errors, and the call is
+// abandoned with an error once its deadline expires.
 func (s *MCPServer) Request(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+	timeout, ok := s.methodTimeouts[method]
+	if !ok {
+		timeout = s.requestTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		result json.RawMessage
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := s.recoverRequest(ctx, method, params)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, mcp.NewError(mcp.ErrorCodeInternalError, fmt.Sprintf("request %s timed out", method))
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// HandleMessage handles a single JSON-RPC message received from a client and
+// returns the response to send back, or nil if there is none, as notifications
+// and responses are never answered. A non-nil error describes a failure to
+// handle the message for logging; the response already reports it to the
+// client.
+func (s *MCPServer) HandleMessage(ctx context.Context, message json.RawMessage) (json.RawMessage, error) {
+	var envelope struct {
+		Id     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(message, &envelope); err == nil {
+		switch {
+		case envelope.Method == "":
+			// Responses are not expected, as the server sends no requests
+			return nil, nil
+		case len(envelope.Id) == 0:
+			// Notifications are never answered, not even with an error
+			if envelope.Method == mcp.MethodNotificationCancelled {
+				s.cancelRequest(ctx, envelope.Params)
+			}
+			if _, err := s.Request(ctx, envelope.Method, envelope.Params); err != nil {
+				return nil, fmt.Errorf("notification handling error: %w", err)
+			}
+			return nil, nil
+		}
+	}
+
+	var request mcp.JSONRPCRequest
+	if err := json.Unmarshal(message, &request); err != nil {
+		if !json.Valid(message) {
+			return errorResponse(mcp.RequestID{}, mcp.ErrorCodeParseError, "Failed to parse JSON-RPC request"),
+				fmt.Errorf("failed to parse JSON-RPC request: %w", err)
+		}
+		// Valid JSON that is not a request, such as one whose id is neither a
+		// string nor an integer
+		return errorResponse(mcp.RequestID{}, mcp.ErrorCodeInvalidRequest, "Invalid JSON-RPC request"),
+			fmt.Errorf("invalid JSON-RPC request: %w", err)
+	}
+
+	if request.Jsonrpc != mcp.JSONRPCVersion {
+		return errorResponse(request.Id, mcp.ErrorCodeInvalidRequest, "Invalid JSON-RPC version"),
+			fmt.Errorf("invalid JSON-RPC version")
+	}
+
+	if session := SessionFromContext(ctx); session != nil {
+		if !session.Initialized() && request.Method != mcp.MethodInitialize && request.Method != mcp.MethodPing {
+			return errorResponse(request.Id, mcp.ErrorCodeInvalidRequest, "Session not initialized"),
+				fmt.Errorf("%s received before initialize", request.Method)
+		}
+
+		if !isTrackedRequest(ctx) {
+			var cancel context.CancelCauseFunc
+			ctx, cancel = context.WithCancelCause(ctx)
+			defer cancel(nil)
+			defer session.trackRequest(request.Id, cancel)()
+		}
+	}
+
+	result, err := s.Request(ctx, request.Method, request.Params)
+	if errors.Is(context.Cause(ctx), errRequestCancelled) {
+		// The client no longer expects a response
+		return nil, nil
+	}
+	if err != nil {
+		var rpcErr *mcp.JSONRPCErrorData
+		if errors.As(err, &rpcErr) {
+			return errorResponse(request.Id, rpcErr.Code, rpcErr.Message), fmt.Errorf("request handling error: %w", err)
+		}
+		return errorResponse(request.Id, mcp.ErrorCodeInternalError, "Internal server error"),
+			fmt.Errorf("request handling error: %w", err)
+	}
+
+	// A successful response must carry a result, even for methods like ping
+	// that have nothing to return
+	if result == nil {
+		result = json.RawMessage("{}")
+	}
+
+	response, err := json.Marshal(mcp.JSONRPCResponse{
+		Jsonrpc: mcp.JSONRPCVersion,
+		Id:      request.Id,
+		Result:  result,
+	})
+	if err != nil {
+		return errorResponse(request.Id, mcp.ErrorCodeInternalError, "Internal server error"),
+			fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return response, nil
+}
+
+// toolsChanged drops the cached tools and sends notifications/tools/list_changed
+// to every initialized session once the tools of a ToolChangeNotifier changed
+func (s *MCPServer) toolsChanged() {
+	s.tools.invalidate()
+	for _, session := range s.sessions.list() {
+		err := session.SendNotification(mcp.MethodNotificationToolsListChanged, nil)
+		if err != nil && !errors.Is(err, ErrNoSender) {
+			s.errLogger.Printf("Failed to notify session %s of changed tools: %v", session.ID(), err)
+		}
+	}
+}
+
+// cancelRequest aborts the request a notifications/cancelled refers to. Unknown
+// and finished requests are ignored, as the notification may cross the response.
+func (s *MCPServer) cancelRequest(ctx context.Context, params json.RawMessage) {
+	session := SessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+	var p mcp.CancelledNotificationParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return
+	}
+	session.cancelRequest(p.RequestId)
+}
+
+func errorResponse(id mcp.RequestID, code int, message string) json.RawMessage {
+	response, _ := json.Marshal(mcp.JSONRPCResponse{
+		Jsonrpc: mcp.JSONRPCVersion,
+		Id:      id,
+		Error: &mcp.JSONRPCErrorData{
+			Code:    code,
+			Message: message,
+		},
+	})
+	return response
+}
+
+func (s *MCPServer) recoverRequest(ctx context.Context, method string, params json.RawMessage) (result json.RawMessage, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.errLogger.Printf("Panic handling %s: %v\n%s", method, r, debug.Stack())
+			result, err = nil, mcp.NewError(mcp.ErrorCodeInternalError, fmt.Sprintf("panic handling %s", method))
+		}
+	}()
+	return chainInterceptors(s.interceptors, s.dispatch)(ctx, method, params)
+}
+
+func (s *MCPServer) dispatch(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
 	if strings.HasPrefix(method, "notifications/") {
-		var notification mcp.Notification
-		if err := json.Unmarshal(params, &notification); err != nil {
-			return nil, fmt.Errorf("failed to parse notification: %w", err)
+		notification := mcp.Notification{Method: method}
+		if len(params) > 0 && string(params) != "null" {
+			if err := json.Unmarshal(params, &notification.Params); err != nil {
+				return nil, fmt.Errorf("failed to parse notification: %w", err)
+			}
+			// Keep the notification-specific parameters, such as the requestId
+			// of notifications/cancelled
+			var additional map[string]interface{}
+			if err := json.Unmarshal(params, &additional); err == nil {
+				delete(additional, "_meta")
+				notification.Params.AdditionalProperties = additional
+			}
 		}
-		err := s.notifyHandlers[method].Handle(ctx, notification)
-		return nil, err
+		handler, ok := s.notifyHandlers[method]
+		if !ok {
+			return nil, nil
+		}
+		return nil, handler.Handle(ctx, notification)
+	}
+
+	// Methods of a capability the server does not advertise do not exist as far
+	// as the client is concerned
+	if !s.supports(method) {
+		return nil, mcp.NewError(mcp.ErrorCodeMethodNotFound, fmt.Sprintf("method not found: %s", method))
+	}
+	// Nor do methods added by a later revision than the one negotiated
+	if session := SessionFromContext(ctx); session != nil && !mcp.IsMethodAvailable(method, session.ProtocolVersion()) {
+		return nil, mcp.NewError(mcp.ErrorCodeMethodNotFound, fmt.Sprintf("method not found: %s", method))
 	}
 
 	switch method {
@@ -110,14 +442,34 @@ func (s *MCPServer) Request(ctx context.Context, method string, params json.RawM
 			ClientInfo      *mcp.Implementation     `json:"clientInfo"`
 			ProtocolVersion string                  `json:"protocolVersion"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Capabilities == nil || p.ClientInfo == nil {
+			return nil, mcp.NewError(mcp.ErrorCodeInvalidParams, "invalid parameters: capabilities and clientInfo are required")
 		}
 		result, err := s.systemHandler.Initialize(ctx, *p.Capabilities, *p.ClientInfo, p.ProtocolVersion)
 		if err != nil {
 			return nil, err
 		}
-		return result.ToJSON()
+		protocolVersion := mcp.NegotiateProtocolVersion(p.ProtocolVersion)
+		experimental := result.Capabilities.Experimental
+		result.Capabilities = s.capabilities
+		result.Capabilities.Experimental = experimental
+		if s.features.completions {
+			result.Capabilities.Completions = &mcp.ServerCapabilitiesCompletions{}
+		}
+		result.ServerInfo = mcp.Implementation{
+			Name:    s.serverInfo.name,
+			Version: s.serverInfo.version,
+		}
+		result.ProtocolVersion = protocolVersion
+		if session := SessionFromContext(ctx); session != nil {
+			session.setInitialized(*p.ClientInfo, *p.Capabilities, protocolVersion)
+			session.setNotified(s.tools.notified)
+			s.sessions.add(session)
+		}
+		return mcp.ForProtocolVersion(result, protocolVersion).ToJSON()
 
 	case mcp.MethodPing:
 		return nil, s.systemHandler.Ping(ctx)
@@ -126,34 +478,34 @@ func (s *MCPServer) Request(ctx context.Context, method string, params json.RawM
 		var p struct {
 			Cursor *string `json:"cursor,omitempty"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
 		}
 		result, err := s.resourceHandler.List(ctx, p.Cursor)
 		if err != nil {
 			return nil, err
 		}
-		return result.ToJSON()
+		return encodeResult(ctx, result)
 
 	case mcp.MethodResourcesRead:
 		var p struct {
 			URI string `json:"uri"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
 		}
 		result, err := s.resourceHandler.Read(ctx, p.URI)
 		if err != nil {
 			return nil, err
 		}
-		return result.ToJSON()
+		return encodeResult(ctx, result)
 
 	case mcp.MethodResourcesSubscribe:
 		var p struct {
 			URI string `json:"uri"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
 		}
 		return nil, s.resourceHandler.Subscribe(ctx, p.URI)
 
@@ -161,8 +513,8 @@ func (s *MCPServer) Request(ctx context.Context, method string, params json.RawM
 		var p struct {
 			URI string `json:"uri"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
 		}
 		return nil, s.resourceHandler.Unsubscribe(ctx, p.URI)
 
@@ -170,62 +522,76 @@ func (s *MCPServer) Request(ctx context.Context, method string, params json.RawM
 		var p struct {
 			Cursor *string `json:"cursor,omitempty"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
 		}
 		result, err := s.promptHandler.List(ctx, p.Cursor)
 		if err != nil {
 			return nil, err
 		}
-		return result.ToJSON()
+		return encodeResult(ctx, result)
 
 	case mcp.MethodPromptsGet:
 		var p struct {
 			Name      string            `json:"name"`
 			Arguments map[string]string `json:"arguments,omitempty"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
 		}
 		result, err := s.promptHandler.Get(ctx, p.Name, p.Arguments)
 		if err != nil {
 			return nil, err
 		}
-		return result.ToJSON()
+		return encodeResult(ctx, result)
 
 	case mcp.MethodToolsList:
 		var p struct {
 			Cursor *string `json:"cursor,omitempty"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
 		}
 		result, err := s.toolHandler.List(ctx, p.Cursor)
 		if err != nil {
 			return nil, err
 		}
-		return result.ToJSON()
+		return encodeResult(ctx, result)
 
 	case mcp.MethodToolsCall:
 		var p struct {
 			Name      string                 `json:"name"`
 			Arguments map[string]interface{} `json:"arguments,omitempty"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
+		}
+		if s.validateToolArguments {
+			invalid, err := s.checkToolArguments(ctx, p.Name, p.Arguments)
+			if err != nil {
+				return nil, err
+			}
+			if invalid != nil {
+				return encodeResult(ctx, invalid)
+			}
 		}
 		result, err := s.toolHandler.Call(ctx, p.Name, p.Arguments)
 		if err != nil {
 			return nil, err
 		}
-		return result.ToJSON()
+		if s.validateToolOutput {
+			if err := s.checkToolOutput(ctx, p.Name, result); err != nil {
+				return nil, err
+			}
+		}
+		return encodeResult(ctx, result)
 
 	case mcp.MethodLoggingSetLevel:
 		var p struct {
 			Level mcp.LoggingLevel `json:"level"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
 		}
 		return nil, s.systemHandler.SetLevel(ctx, p.Level)
 
@@ -234,16 +600,59 @@ func (s *MCPServer) Request(ctx context.Context, method string, params json.RawM
 			Ref      interface{}         `json:"ref"`
 			Argument mcp.CompleteRequest `json:"argument"`
 		}
-		if err := json.Unmarshal(params, &p); err != nil {
-			return nil, fmt.Errorf("failed to parse parameters: %w", err)
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
 		}
 		result, err := s.systemHandler.Complete(ctx, p.Ref, p.Argument)
 		if err != nil {
 			return nil, err
 		}
-		return result.ToJSON()
+		return encodeResult(ctx, result)
 
 	default:
-		return nil, fmt.Errorf("method not found: %s", method)
+		return nil, mcp.NewError(mcp.ErrorCodeMethodNotFound, fmt.Sprintf("method not found: %s", method))
+	}
+}
+
+// supports reports whether method belongs to a capability the server
+// advertises. Methods outside of any capability are always supported.
+func (s *MCPServer) supports(method string) bool {
+	switch method {
+	case mcp.MethodResourcesList, mcp.MethodResourcesRead:
+		return s.capabilities.Resources != nil
+	case mcp.MethodResourcesSubscribe, mcp.MethodResourcesUnsubscribe:
+		return s.capabilities.Resources != nil && s.capabilities.Resources.Subscribe
+	case mcp.MethodPromptsList, mcp.MethodPromptsGet:
+		return s.capabilities.Prompts != nil
+	case mcp.MethodToolsList, mcp.MethodToolsCall:
+		return s.capabilities.Tools != nil
+	case mcp.MethodLoggingSetLevel:
+		return s.capabilities.Logging != nil
+	case mcp.MethodCompletionComplete:
+		return s.features.completions
+	}
+	return true
+}
+
+// encodeResult encodes the result of a request for the protocol revision
+// negotiated by the session it was received on
+func encodeResult(ctx context.Context, result interface {
+	ToJSON() (json.RawMessage, error)
+}) (json.RawMessage, error) {
+	if session := SessionFromContext(ctx); session != nil {
+		result = mcp.ForProtocolVersion(result, session.ProtocolVersion())
+	}
+	return result.ToJSON()
+}
+
+// parseParams decodes the parameters of a request into v. Parameters may be
+// omitted when none of them is required.
+func parseParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 || string(params) == "null" {
+		params = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return mcp.NewError(mcp.ErrorCodeInvalidParams, fmt.Sprintf("invalid parameters: %v", err))
 	}
+	return nil
 }
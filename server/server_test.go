@@ -0,0 +1,315 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server/handlers"
+)
+
+// funcTools is a ToolHandler answering calls to a single tool with fn
+type funcTools func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error)
+
+func (fn funcTools) List(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error) {
+	return &mcp.ListToolsResult{Tools: []mcp.Tool{{Name: "run"}}}, nil
+}
+
+func (fn funcTools) Call(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	return fn(ctx, arguments)
+}
+
+func newToolServer(fn funcTools, opts ...ServerOption) *MCPServer {
+	return NewMCPServer(append([]ServerOption{WithToolHandler(fn)}, opts...)...)
+}
+
+func callTool(t *testing.T, s *MCPServer, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	t.Helper()
+	params, err := json.Marshal(map[string]interface{}{"name": name, "arguments": arguments})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.Request(context.Background(), mcp.MethodToolsCall, params)
+	if err != nil {
+		return nil, err
+	}
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(b, &result); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	return &result, nil
+}
+
+func TestRecoverHandlerPanic(t *testing.T) {
+	var logs bytes.Buffer
+	s := newToolServer(func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		panic("boom")
+	}, WithLogger(log.New(&logs, "", 0)))
+
+	_, err := callTool(t, s, "run", nil)
+	var rpcErr *mcp.JSONRPCErrorData
+	if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorCodeInternalError {
+		t.Fatalf("error = %v, want an internal error", err)
+	}
+	if !strings.Contains(logs.String(), "boom") {
+		t.Errorf("panic not logged: %q", logs.String())
+	}
+
+	// The server keeps serving after the panic
+	if _, err := s.Request(context.Background(), mcp.MethodPing, nil); err != nil {
+		t.Errorf("ping after panic: %v", err)
+	}
+}
+
+func TestRecoverInterceptorPanic(t *testing.T) {
+	s := NewMCPServer(
+		WithLogger(log.New(&bytes.Buffer{}, "", 0)),
+		WithInterceptors(func(ctx context.Context, method string, params json.RawMessage, next RequestHandlerFunc) (json.RawMessage, error) {
+			panic("boom")
+		}),
+	)
+	_, err := s.Request(context.Background(), mcp.MethodPing, nil)
+	var rpcErr *mcp.JSONRPCErrorData
+	if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorCodeInternalError {
+		t.Errorf("error = %v, want an internal error", err)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	s := newToolServer(func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		// Ignore ctx, as a misbehaving handler would
+		<-release
+		return &mcp.CallToolResult{}, nil
+	}, WithRequestTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	_, err := callTool(t, s, "run", nil)
+	var rpcErr *mcp.JSONRPCErrorData
+	if !errors.As(err, &rpcErr) || !strings.Contains(rpcErr.Message, "timed out") {
+		t.Fatalf("error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request abandoned after %v", elapsed)
+	}
+}
+
+func TestMethodTimeout(t *testing.T) {
+	slow := func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return &mcp.CallToolResult{}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		s := newToolServer(slow, WithRequestTimeout(time.Millisecond), WithMethodTimeout(mcp.MethodToolsCall, 0))
+		if _, err := callTool(t, s, "run", nil); err != nil {
+			t.Errorf("CallTool: %v", err)
+		}
+	})
+
+	t.Run("shorter", func(t *testing.T) {
+		s := newToolServer(slow, WithMethodTimeout(mcp.MethodToolsCall, time.Millisecond))
+		if _, err := callTool(t, s, "run", nil); err == nil {
+			t.Error("expected a timeout")
+		}
+		if _, err := s.Request(context.Background(), mcp.MethodPing, nil); err != nil {
+			t.Errorf("other methods: %v", err)
+		}
+	})
+}
+
+func TestRequestCancelled(t *testing.T) {
+	s := newToolServer(func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, WithRequestTimeout(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	params := json.RawMessage(`{"name":"run"}`)
+	if _, err := s.Request(ctx, mcp.MethodToolsCall, params); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestCapabilitiesFromHandlers(t *testing.T) {
+	if c := NewMCPServer().Capabilities(); c.Tools != nil || c.Prompts != nil || c.Resources != nil || c.Logging != nil {
+		t.Errorf("server without handlers advertises %+v", c)
+	}
+
+	s := NewMCPServer(
+		WithToolHandler(handlers.NewDefaultToolHandler()),
+		WithToolCapabilities(true),
+		WithResourceHandler(handlers.NewDefaultResourceHandler()),
+		WithResourceCapabilities(true, false),
+		WithLogging(),
+	)
+	c := s.Capabilities()
+	if c.Tools == nil || !c.Tools.ListChanged {
+		t.Errorf("tools = %+v, want listChanged", c.Tools)
+	}
+	if c.Resources == nil || !c.Resources.Subscribe || c.Resources.ListChanged {
+		t.Errorf("resources = %+v, want subscribe only", c.Resources)
+	}
+	if c.Prompts != nil {
+		t.Errorf("prompts = %+v, want none without a prompt handler", c.Prompts)
+	}
+	if c.Logging == nil {
+		t.Error("logging not advertised")
+	}
+}
+
+func initialize(t *testing.T, s *MCPServer, version string) mcp.InitializeResult {
+	t.Helper()
+	params, err := json.Marshal(map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]interface{}{"name": "test", "version": "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.Request(context.Background(), mcp.MethodInitialize, params)
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	var result mcp.InitializeResult
+	if err := json.Unmarshal(b, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestInitializeResult(t *testing.T) {
+	s := NewMCPServer(WithServerInfo("test-server", "2.0.0"), WithToolHandler(handlers.NewDefaultToolHandler()), WithCompletions())
+
+	tests := []struct {
+		requested   string
+		negotiated  string
+		completions bool
+	}{
+		{mcp.LatestProtocolVersion, mcp.LatestProtocolVersion, true},
+		{mcp.ProtocolVersion20250326, mcp.ProtocolVersion20250326, true},
+		{mcp.ProtocolVersion20241105, mcp.ProtocolVersion20241105, false},
+		{"2023-01-01", mcp.LatestProtocolVersion, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.requested, func(t *testing.T) {
+			result := initialize(t, s, tt.requested)
+			if result.ProtocolVersion != tt.negotiated {
+				t.Errorf("protocolVersion = %s, want %s", result.ProtocolVersion, tt.negotiated)
+			}
+			if result.ServerInfo.Name != "test-server" || result.ServerInfo.Version != "2.0.0" {
+				t.Errorf("serverInfo = %+v", result.ServerInfo)
+			}
+			if result.Capabilities.Tools == nil {
+				t.Error("tools capability missing")
+			}
+			if result.Capabilities.Prompts != nil {
+				t.Error("prompts advertised without a prompt handler")
+			}
+			if got := result.Capabilities.Completions != nil; got != tt.completions {
+				t.Errorf("completions advertised = %v, want %v", got, tt.completions)
+			}
+		})
+	}
+}
+
+func TestResponseKeepsRequestID(t *testing.T) {
+	s := NewMCPServer()
+	for _, id := range []string{`7`, `"7"`, `"req-1"`} {
+		message := json.RawMessage(`{"jsonrpc":"2.0","id":` + id + `,"method":"ping"}`)
+		response, err := s.HandleMessage(context.Background(), message)
+		if err != nil {
+			t.Fatalf("id %s: %v", id, err)
+		}
+		var decoded struct {
+			Id json.RawMessage `json:"id"`
+		}
+		if err := json.Unmarshal(response, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if string(decoded.Id) != id {
+			t.Errorf("response id = %s, want %s", decoded.Id, id)
+		}
+	}
+}
+
+func TestMalformedRequest(t *testing.T) {
+	s := NewMCPServer()
+	tests := []struct {
+		message string
+		code    int
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":`, mcp.ErrorCodeParseError},
+		{`{"jsonrpc":"2.0","id":1.0,"method":"ping"}`, mcp.ErrorCodeInvalidRequest},
+		{`{"jsonrpc":"2.0","id":{},"method":"ping"}`, mcp.ErrorCodeInvalidRequest},
+		{`[]`, mcp.ErrorCodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		response, err := s.HandleMessage(context.Background(), json.RawMessage(tt.message))
+		if err == nil {
+			t.Errorf("%s: no error reported", tt.message)
+		}
+		var decoded struct {
+			Id    json.RawMessage `json:"id"`
+			Error struct {
+				Code int `json:"code"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(response, &decoded); err != nil {
+			t.Fatalf("%s: %v", tt.message, err)
+		}
+		if decoded.Error.Code != tt.code || string(decoded.Id) != "null" {
+			t.Errorf("%s: response = %s, want error %d with a null id", tt.message, response, tt.code)
+		}
+	}
+}
+
+type notificationRecorder struct {
+	notifications chan mcp.Notification
+}
+
+func (r notificationRecorder) Handle(ctx context.Context, notification mcp.Notification) error {
+	r.notifications <- notification
+	return nil
+}
+
+func TestNotificationHandlerParams(t *testing.T) {
+	recorder := notificationRecorder{notifications: make(chan mcp.Notification, 2)}
+	s := NewMCPServer(WithNotificationHandler(mcp.MethodNotificationCancelled, recorder))
+
+	messages := []string{
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":3,"reason":"timeout","_meta":{"k":"v"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/cancelled"}`,
+	}
+	for _, message := range messages {
+		response, err := s.HandleMessage(context.Background(), json.RawMessage(message))
+		if err != nil || response != nil {
+			t.Fatalf("notification answered with %s, %v", response, err)
+		}
+	}
+
+	n := <-recorder.notifications
+	additional, _ := n.Params.AdditionalProperties.(map[string]interface{})
+	if n.Method != mcp.MethodNotificationCancelled || additional["requestId"] != float64(3) || additional["reason"] != "timeout" {
+		t.Errorf("notification = %+v, want its requestId and reason", n)
+	}
+	if _, ok := additional["_meta"]; ok || n.Params.Meta["k"] != "v" {
+		t.Errorf("_meta = %v, additional = %v, want _meta apart", n.Params.Meta, additional)
+	}
+	if n := <-recorder.notifications; n.Params != nil {
+		t.Errorf("params = %+v, want none", n.Params)
+	}
+}
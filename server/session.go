@@ -0,0 +1,262 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// Session holds the per-connection state of a client talking to an MCPServer
+type Session struct {
+	id string
+
+	mu                 sync.RWMutex
+	initialized        bool
+	clientInfo         mcp.Implementation
+	clientCapabilities mcp.ClientCapabilities
+	protocolVersion    string
+	closers            []func()
+	done               chan struct{}
+	closed             bool
+	send               func(message json.RawMessage) error
+	// notified is called with the method of every notification sent
+	notified func(method string)
+	// requests holds the requests being handled, which the client may cancel
+	requests map[mcp.RequestID]*inflightRequest
+}
+
+type inflightRequest struct {
+	cancel context.CancelCauseFunc
+}
+
+// errRequestCancelled is the cause of the context of a request cancelled by
+// the client
+var errRequestCancelled = errors.New("request cancelled by the client")
+
+// ErrNoSender is returned when sending a notification on a session whose
+// transport cannot deliver messages to the client
+var ErrNoSender = errors.New("session cannot send messages")
+
+// NewSession creates an uninitialized session with the given identifier
+func NewSession(id string) *Session {
+	return &Session{id: id, done: make(chan struct{})}
+}
+
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Initialized reports whether the client has completed the initialize request
+func (s *Session) Initialized() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.initialized
+}
+
+func (s *Session) ClientInfo() mcp.Implementation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.clientInfo
+}
+
+func (s *Session) ClientCapabilities() mcp.ClientCapabilities {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.clientCapabilities
+}
+
+// ProtocolVersion returns the protocol revision negotiated during
+// initialization, or an empty string if the session is not initialized yet
+func (s *Session) ProtocolVersion() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.protocolVersion
+}
+
+func (s *Session) setInitialized(clientInfo mcp.Implementation, capabilities mcp.ClientCapabilities, protocolVersion string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initialized = true
+	s.clientInfo = clientInfo
+	s.clientCapabilities = capabilities
+	s.protocolVersion = protocolVersion
+}
+
+// OnClose registers fn to be called when the session is closed, so handlers can
+// release per-session resources such as subscriptions or pending sampling
+// requests. If the session is already closed, fn is called immediately.
+func (s *Session) OnClose(fn func()) {
+	s.mu.Lock()
+	if !s.closed {
+		s.closers = append(s.closers, fn)
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+	fn()
+}
+
+// Done returns a channel that is closed when the session is closed
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close closes the session and runs the functions registered with OnClose in
+// reverse order. It is called by transports when the connection ends.
+func (s *Session) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	closers := s.closers
+	s.closers = nil
+	close(s.done)
+	s.mu.Unlock()
+
+	for i := len(closers) - 1; i >= 0; i-- {
+		closers[i]()
+	}
+}
+
+// SetSender sets the function delivering messages to the client. It is called
+// by transports able to send messages on their own initiative.
+func (s *Session) SetSender(send func(message json.RawMessage) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.send = send
+}
+
+// SendNotification sends a notification to the client, such as
+// notifications/tools/list_changed
+func (s *Session) SendNotification(method string, params interface{}) error {
+	s.mu.RLock()
+	send, closed, notified := s.send, s.closed, s.notified
+	s.mu.RUnlock()
+	if notified != nil {
+		notified(method)
+	}
+	if send == nil {
+		return ErrNoSender
+	}
+	if closed {
+		return fmt.Errorf("session %s is closed", s.id)
+	}
+
+	notification := struct {
+		Jsonrpc string      `json:"jsonrpc"`
+		Method  string      `json:"method"`
+		Params  interface{} `json:"params,omitempty"`
+	}{
+		Jsonrpc: mcp.JSONRPCVersion,
+		Method:  method,
+		Params:  mcp.ForProtocolVersion(params, s.ProtocolVersion()),
+	}
+	message, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	return send(message)
+}
+
+// setNotified sets the function called with the method of every notification
+// sent on the session
+func (s *Session) setNotified(fn func(method string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notified = fn
+}
+
+// trackRequest records a request being handled until the returned function is
+// called
+func (s *Session) trackRequest(id mcp.RequestID, cancel context.CancelCauseFunc) func() {
+	request := &inflightRequest{cancel: cancel}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requests == nil {
+		s.requests = make(map[mcp.RequestID]*inflightRequest)
+	}
+	s.requests[id] = request
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// The ID may have been reused by a newer request in the meantime
+		if s.requests[id] == request {
+			delete(s.requests, id)
+		}
+	}
+}
+
+// cancelRequest cancels the request with the given ID if it is still being
+// handled
+func (s *Session) cancelRequest(id mcp.RequestID) {
+	s.mu.RLock()
+	request := s.requests[id]
+	s.mu.RUnlock()
+	if request != nil {
+		request.cancel(errRequestCancelled)
+	}
+}
+
+// trackedRequestKey marks the context of a request already registered with
+// trackRequest by its transport
+type trackedRequestKey struct{}
+
+func isTrackedRequest(ctx context.Context) bool {
+	tracked, _ := ctx.Value(trackedRequestKey{}).(bool)
+	return tracked
+}
+
+type sessionContextKey struct{}
+
+// sessionSet holds the initialized sessions of a server until they are closed
+type sessionSet struct {
+	mu       sync.Mutex
+	sessions map[*Session]struct{}
+}
+
+func newSessionSet() *sessionSet {
+	return &sessionSet{sessions: make(map[*Session]struct{})}
+}
+
+func (set *sessionSet) add(session *Session) {
+	set.mu.Lock()
+	if _, ok := set.sessions[session]; ok {
+		set.mu.Unlock()
+		return
+	}
+	set.sessions[session] = struct{}{}
+	set.mu.Unlock()
+
+	session.OnClose(func() {
+		set.mu.Lock()
+		defer set.mu.Unlock()
+		delete(set.sessions, session)
+	})
+}
+
+func (set *sessionSet) list() []*Session {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	sessions := make([]*Session, 0, len(set.sessions))
+	for session := range set.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// ContextWithSession returns a copy of ctx carrying the given session
+func ContextWithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
+
+// SessionFromContext returns the session carried by ctx, or nil if there is none
+func SessionFromContext(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionContextKey{}).(*Session)
+	return session
+}
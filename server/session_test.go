@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server/handlers"
+)
+
+func TestSessionInitialize(t *testing.T) {
+	s := NewMCPServer()
+	session := NewSession("test")
+	if session.ID() != "test" {
+		t.Errorf("ID() = %q", session.ID())
+	}
+	if session.Initialized() {
+		t.Error("session initialized before initialize")
+	}
+
+	ctx := ContextWithSession(context.Background(), session)
+	initialize := json.RawMessage(`{"protocolVersion":"2024-11-05","capabilities":{"roots":{"listChanged":true}},"clientInfo":{"name":"client","version":"1.2"}}`)
+	if _, err := s.Request(ctx, mcp.MethodInitialize, initialize); err != nil {
+		t.Fatal(err)
+	}
+	if !session.Initialized() {
+		t.Error("session not initialized after initialize")
+	}
+	if info := session.ClientInfo(); info.Name != "client" || info.Version != "1.2" {
+		t.Errorf("ClientInfo() = %+v", info)
+	}
+	if version := session.ProtocolVersion(); version != mcp.ProtocolVersion20241105 {
+		t.Errorf("ProtocolVersion() = %q, want the negotiated revision", version)
+	}
+	if roots := session.ClientCapabilities().Roots; roots == nil {
+		t.Error("ClientCapabilities() lost the roots capability")
+	}
+}
+
+func TestSessionFromContext(t *testing.T) {
+	if session := SessionFromContext(context.Background()); session != nil {
+		t.Errorf("SessionFromContext without a session = %v", session)
+	}
+	session := NewSession("test")
+	if got := SessionFromContext(ContextWithSession(context.Background(), session)); got != session {
+		t.Errorf("SessionFromContext = %v, want the session", got)
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	session := NewSession("test")
+	var closed []int
+	session.OnClose(func() { closed = append(closed, 1) })
+	session.OnClose(func() { closed = append(closed, 2) })
+
+	session.Close()
+	session.Close()
+	if !slices.Equal(closed, []int{2, 1}) {
+		t.Errorf("closers ran as %v, want once each in reverse order", closed)
+	}
+	select {
+	case <-session.Done():
+	default:
+		t.Error("Done not closed")
+	}
+
+	session.OnClose(func() { closed = append(closed, 3) })
+	if !slices.Equal(closed, []int{2, 1, 3}) {
+		t.Errorf("closer registered after Close did not run immediately: %v", closed)
+	}
+}
+
+func TestSessionSendNotification(t *testing.T) {
+	session := NewSession("test")
+	if err := session.SendNotification(mcp.MethodNotificationToolsListChanged, nil); !errors.Is(err, ErrNoSender) {
+		t.Errorf("without sender: %v", err)
+	}
+
+	var sent []string
+	session.SetSender(func(message json.RawMessage) error {
+		sent = append(sent, string(message))
+		return nil
+	})
+	if err := session.SendNotification(mcp.MethodNotificationToolsListChanged, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`; len(sent) != 1 || sent[0] != want {
+		t.Errorf("sent %v, want %s", sent, want)
+	}
+
+	session.Close()
+	if err := session.SendNotification(mcp.MethodNotificationToolsListChanged, nil); err == nil {
+		t.Error("notification sent on a closed session")
+	}
+}
+
+func TestSessionRequiresInitialize(t *testing.T) {
+	s := NewMCPServer()
+	ctx := ContextWithSession(context.Background(), NewSession("test"))
+
+	response, err := s.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	if err == nil || !strings.Contains(string(response), "Session not initialized") {
+		t.Errorf("request before initialize = %s, %v", response, err)
+	}
+	// Ping is allowed at any time
+	if _, err := s.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"ping"}`)); err != nil {
+		t.Errorf("ping before initialize: %v", err)
+	}
+}
+
+func TestSessionProtocolVersionEncoding(t *testing.T) {
+	title := "Echo"
+	registry := handlers.NewToolRegistry()
+	registry.Register(mcp.Tool{Name: "echo", Title: &title}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{}}, nil
+	})
+	s := NewMCPServer(WithToolHandler(registry))
+
+	for _, version := range []string{mcp.ProtocolVersion20241105, mcp.LatestProtocolVersion} {
+		session := NewSession(version)
+		ctx := ContextWithSession(context.Background(), session)
+		initialize := json.RawMessage(`{"protocolVersion":"` + version + `","capabilities":{},"clientInfo":{"name":"client","version":"1"}}`)
+		if _, err := s.Request(ctx, mcp.MethodInitialize, initialize); err != nil {
+			t.Fatal(err)
+		}
+		b, err := s.Request(ctx, mcp.MethodToolsList, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := strings.Contains(string(b), `"title"`), version != mcp.ProtocolVersion20241105; got != want {
+			t.Errorf("%s: tools/list = %s, title sent = %v, want %v", version, b, got, want)
+		}
+	}
+}
+
+func TestSessionToolsChanged(t *testing.T) {
+	tools := handlers.NewToolRegistry()
+	s := NewMCPServer(WithToolHandler(tools))
+	if c := s.Capabilities().Tools; c == nil || !c.ListChanged {
+		t.Errorf("tools = %+v, want listChanged for a tool registry", c)
+	}
+
+	newSession := func(id string) (*Session, *[]string) {
+		session := NewSession(id)
+		sent := &[]string{}
+		session.SetSender(func(message json.RawMessage) error {
+			*sent = append(*sent, string(message))
+			return nil
+		})
+		return session, sent
+	}
+	initialized, sent := newSession("initialized")
+	ctx := ContextWithSession(context.Background(), initialized)
+	initialize := json.RawMessage(`{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"client","version":"1"}}`)
+	if _, err := s.Request(ctx, mcp.MethodInitialize, initialize); err != nil {
+		t.Fatal(err)
+	}
+	_, unknown := newSession("uninitialized")
+
+	tools.Register(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{}}, nil
+	})
+	tools.Unregister("echo")
+	want := `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`
+	if len(*sent) != 2 || (*sent)[0] != want || (*sent)[1] != want {
+		t.Errorf("sent %v, want %s for each change", *sent, want)
+	}
+	if len(*unknown) != 0 {
+		t.Errorf("uninitialized session notified: %v", *unknown)
+	}
+
+	// Closed sessions are forgotten
+	initialized.Close()
+	tools.Register(mcp.Tool{Name: "echo"}, nil)
+	if len(*sent) != 2 {
+		t.Errorf("closed session notified: %v", *sent)
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,34 +10,69 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/WePrompt/gomcp/mcp"
 )
 
+var _ Transport = &StdioServer{}
+
+const defaultShutdownTimeout = 10 * time.Second
+
 // StdioServer wraps an MCPServer and handles stdio communication
 type StdioServer struct {
-	server    MCPServer
-	stopChan  chan os.Signal
-	done      chan struct{}
-	errLogger *log.Logger
+	server          MCPServer
+	session         *Session
+	stopChan        chan os.Signal
+	done            chan struct{}
+	errLogger       *log.Logger
+	shutdownTimeout time.Duration
+
+	// ctx is the parent context of every in-flight request; cancel aborts them
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu           sync.Mutex
+	shuttingDown bool
+	inflight     sync.WaitGroup
+	shutdownOnce sync.Once
+
+	in      io.Reader
+	writeMu sync.Mutex
+	out     *bufio.Writer
 }
 
 // NewStdioServer creates a stdio server wrapper around an existing MCPServer
 func NewStdioServer(server MCPServer) *StdioServer {
 	s := &StdioServer{
-		server:    server,
-		stopChan:  make(chan os.Signal, 1),
-		errLogger: log.New(os.Stderr, "", log.LstdFlags),
-		done:      make(chan struct{}),
+		server:          server,
+		session:         NewSession("stdio"),
+		stopChan:        make(chan os.Signal, 1),
+		errLogger:       log.New(os.Stderr, "", log.LstdFlags),
+		shutdownTimeout: defaultShutdownTimeout,
+		done:            make(chan struct{}),
+		in:              os.Stdin,
+		out:             bufio.NewWriter(os.Stdout),
 	}
+	s.session.SetSender(s.writeMessage)
+	s.ctx, s.cancel = context.WithCancel(ContextWithSession(context.Background(), s.session))
 
 	// Listen for shutdown signals
 	signal.Notify(s.stopChan, syscall.SIGTERM, syscall.SIGINT)
 
 	go func() {
-		<-s.stopChan
-		close(s.done)
+		select {
+		case <-s.stopChan:
+		case <-s.done:
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != nil {
+			s.errLogger.Printf("Error shutting down: %v", err)
+		}
 	}()
 
 	return s
@@ -47,72 +83,160 @@ func (s *StdioServer) WithLogger(logger *log.Logger) *StdioServer {
 	return s
 }
 
-func (s *StdioServer) Serve() error {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// WithShutdownTimeout sets how long in-flight requests may take to finish when
+// the server is stopped by a signal or stdin is closed.
+func (s *StdioServer) WithShutdownTimeout(timeout time.Duration) *StdioServer {
+	s.shutdownTimeout = timeout
+	return s
+}
 
+// Serve reads requests from stdin and handles each of them concurrently until
+// stdin is closed or the server is shut down.
+func (s *StdioServer) Serve() error {
+	lines := make(chan string)
+	readErr := make(chan error, 1)
 	go func() {
-		<-s.done
-		cancel()
+		// Unlike bufio.Scanner, bufio.Reader does not limit the length of a
+		// line, as requests may carry large arguments
+		reader := bufio.NewReader(s.in)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				select {
+				case lines <- string(line):
+				case <-s.done:
+					return
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				readErr <- err
+				return
+			}
+		}
 	}()
 
-	reader := bufio.NewScanner(os.Stdin)
 	for {
 		select {
-		case <-ctx.Done():
+		case <-s.done:
 			return nil
-		default:
-			if !reader.Scan() {
-				if err := reader.Err(); err != nil {
-					s.errLogger.Printf("Error reading input: %v", err)
-					return err
-				}
-				// EOF reached
-				return nil
+		case err := <-readErr:
+			// EOF reached, let in-flight requests finish before returning
+			if err != nil {
+				s.errLogger.Printf("Error reading input: %v", err)
 			}
-
-			if err := s.handleMessage(ctx, reader.Text()); err != nil {
-				if err == io.EOF {
-					return nil
-				}
-				s.errLogger.Printf("Error handling message: %v", err)
+			ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+			defer cancel()
+			if err := s.Shutdown(ctx); err != nil {
+				s.errLogger.Printf("Error shutting down: %v", err)
 			}
+			return err
+		case line := <-lines:
+			if !s.acquire() {
+				s.refuse(line)
+				continue
+			}
+			ctx, untrack := s.trackRequest(line)
+			go func() {
+				defer s.inflight.Done()
+				defer untrack()
+				if err := s.handleMessage(ctx, line); err != nil {
+					if err == io.EOF {
+						return
+					}
+					s.errLogger.Printf("Error handling message: %v", err)
+				}
+			}()
 		}
 	}
 }
 
-func (s *StdioServer) handleMessage(ctx context.Context, line string) error {
-	var request mcp.JSONRPCRequest
-	if err := json.Unmarshal([]byte(line), &request); err != nil {
-		s.writeError(nil, mcp.ErrorCodeParseError, "Failed to parse JSON-RPC request")
-		return fmt.Errorf("failed to parse JSON-RPC request: %w", err)
-	}
+// Shutdown stops accepting new requests and waits for in-flight requests to
+// finish. If ctx expires first, the remaining requests are cancelled and
+// Shutdown returns the context's error once they have returned. Pending
+// responses are flushed and the session's resources are released before
+// Shutdown returns, after which Serve returns nil.
+func (s *StdioServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.shuttingDown = true
+	s.mu.Unlock()
 
-	if request.Jsonrpc != mcp.JSONRPCVersion {
-		s.writeError(request.Id, mcp.ErrorCodeInvalidRequest, "Invalid JSON-RPC version")
-		return fmt.Errorf("invalid JSON-RPC version")
+	drained := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+		s.cancel()
+		<-drained
 	}
 
-	result, err := s.server.Request(ctx, request.Method, request.Params)
-	if err != nil {
-		s.writeError(request.Id, mcp.ErrorCodeInternalError, "Internal server error")
-		return fmt.Errorf("request handling error: %w", err)
+	s.shutdownOnce.Do(func() {
+		signal.Stop(s.stopChan)
+		s.cancel()
+		s.session.Close()
+		s.flush()
+		close(s.done)
+	})
+	return err
+}
+
+// acquire registers a new in-flight request, unless the server is shutting down
+func (s *StdioServer) acquire() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shuttingDown {
+		return false
 	}
+	s.inflight.Add(1)
+	return true
+}
 
-	response := mcp.JSONRPCResponse{
-		Jsonrpc: mcp.JSONRPCVersion,
-		Id:      request.Id,
-		Result:  result,
+// trackRequest registers the request a message carries with the session before
+// the message is handled concurrently with the next ones, so that a
+// notifications/cancelled read right after it finds the request. It returns the
+// context to handle the message with and the function to call once it is
+// handled.
+func (s *StdioServer) trackRequest(line string) (context.Context, func()) {
+	var request mcp.JSONRPCRequest
+	if err := json.Unmarshal([]byte(line), &request); err != nil || request.Method == "" || request.Id.IsNull() {
+		return s.ctx, func() {}
 	}
+	ctx, cancel := context.WithCancelCause(s.ctx)
+	untrack := s.session.trackRequest(request.Id, cancel)
+	return context.WithValue(ctx, trackedRequestKey{}, true), func() {
+		untrack()
+		cancel(nil)
+	}
+}
 
-	if err := s.writeResponse(response); err != nil {
-		return fmt.Errorf("failed to write response: %w", err)
+// refuse answers a request received during shutdown with an error
+func (s *StdioServer) refuse(line string) {
+	var request mcp.JSONRPCRequest
+	if err := json.Unmarshal([]byte(line), &request); err != nil || request.Method == "" {
+		return
 	}
+	s.writeError(request.Id, mcp.ErrorCodeInternalError, ErrServerShuttingDown.Error())
+}
 
-	return nil
+func (s *StdioServer) handleMessage(ctx context.Context, line string) error {
+	response, err := s.server.HandleMessage(ctx, json.RawMessage(line))
+	if response != nil {
+		if err := s.writeMessage(response); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	return err
 }
 
-func (s *StdioServer) writeError(id interface{}, code int, message string) {
+func (s *StdioServer) writeError(id mcp.RequestID, code int, message string) {
 	response := mcp.JSONRPCResponse{
 		Jsonrpc: mcp.JSONRPCVersion,
 		Id:      id,
@@ -132,11 +256,30 @@ func (s *StdioServer) writeResponse(response mcp.JSONRPCResponse) error {
 		s.errLogger.Printf("Error marshal response: %v", err)
 		return err
 	}
+	return s.writeMessage(responseBytes)
+}
 
-	if _, err := fmt.Fprintf(os.Stdout, "%s\n", responseBytes); err != nil {
+// writeMessage writes a single message as a line of output
+func (s *StdioServer) writeMessage(message json.RawMessage) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if _, err := fmt.Fprintf(s.out, "%s\n", message); err != nil {
+		s.errLogger.Printf("Error writing response: %v", err)
+		return err
+	}
+	if err := s.out.Flush(); err != nil {
 		s.errLogger.Printf("Error writing response: %v", err)
 		return err
 	}
 
 	return nil
 }
+
+func (s *StdioServer) flush() {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.out.Flush(); err != nil {
+		s.errLogger.Printf("Error flushing output: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server/handlers"
+)
+
+func TestStdioCancelRightAfterRequest(t *testing.T) {
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{Name: "wait"}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(2 * time.Second):
+			result := &mcp.CallToolResult{}
+			result.AddTextContent(mcp.NewTextContent("not cancelled"))
+			return result, nil
+		}
+	})
+	s := NewMCPServer(WithToolHandler(tools))
+
+	// The cancellation is read right after the request it refers to, while
+	// both are handled concurrently
+	for i := 0; i < 20; i++ {
+		input := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"wait"}}`+"\n"+
+			`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":%d}}`+"\n", i, i)
+		var output bytes.Buffer
+		stdio := NewStdioServer(*s).WithLogger(log.New(io.Discard, "", 0))
+		stdio.session.setInitialized(mcp.Implementation{Name: "test", Version: "1"}, mcp.ClientCapabilities{}, mcp.LatestProtocolVersion)
+		stdio.in = strings.NewReader(input)
+		stdio.out = bufio.NewWriter(&output)
+		if err := stdio.Serve(); err != nil {
+			t.Fatal(err)
+		}
+		if output.Len() > 0 {
+			t.Fatalf("cancelled request %d was answered: %s", i, output.String())
+		}
+	}
+}
+
+// syncBuffer collects the output of a server while the test reads it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newBlockingStdio returns a stdio server whose "wait" tool signals started and
+// blocks until release is closed or its context is done
+func newBlockingStdio(t *testing.T, started chan<- struct{}, release <-chan struct{}) (*StdioServer, *io.PipeWriter, *syncBuffer) {
+	t.Helper()
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{Name: "wait"}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		started <- struct{}{}
+		select {
+		case <-release:
+			return &mcp.CallToolResult{Content: []mcp.Content{}}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	s := NewMCPServer(WithToolHandler(tools))
+
+	in, pw := io.Pipe()
+	t.Cleanup(func() { pw.Close() })
+	output := &syncBuffer{}
+	stdio := NewStdioServer(*s).WithLogger(log.New(io.Discard, "", 0))
+	stdio.session.setInitialized(mcp.Implementation{Name: "test", Version: "1"}, mcp.ClientCapabilities{}, mcp.LatestProtocolVersion)
+	stdio.in = in
+	stdio.out = bufio.NewWriter(output)
+	return stdio, pw, output
+}
+
+func callWait(t *testing.T, w io.Writer, id int) {
+	t.Helper()
+	if _, err := fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"wait"}}`+"\n", id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitUntil(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting: %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStdioShutdownDrainsRequests(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	stdio, in, output := newBlockingStdio(t, started, release)
+	served := make(chan error, 1)
+	go func() { served <- stdio.Serve() }()
+
+	callWait(t, in, 1)
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- stdio.Shutdown(context.Background()) }()
+	waitUntil(t, "shutdown to start", func() bool {
+		stdio.mu.Lock()
+		defer stdio.mu.Unlock()
+		return stdio.shuttingDown
+	})
+
+	// Requests received during shutdown are refused
+	callWait(t, in, 2)
+	waitUntil(t, "the second request to be refused", func() bool {
+		return strings.Contains(output.String(), ErrServerShuttingDown.Error())
+	})
+
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown returned before the request finished: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-shutdown; err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	if !strings.Contains(output.String(), `{"id":1,"jsonrpc":"2.0","result"`) {
+		t.Errorf("in-flight request not answered: %s", output.String())
+	}
+}
+
+func TestStdioShutdownTimeout(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	defer close(release)
+	stdio, in, _ := newBlockingStdio(t, started, release)
+	served := make(chan error, 1)
+	go func() { served <- stdio.Serve() }()
+
+	callWait(t, in, 1)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := stdio.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	if stdio.ctx.Err() == nil {
+		t.Error("in-flight requests not cancelled")
+	}
+}
+
+func TestStdioEOFShutdownTimeout(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	defer close(release)
+	stdio, in, _ := newBlockingStdio(t, started, release)
+	stdio.WithShutdownTimeout(20 * time.Millisecond)
+	served := make(chan error, 1)
+	go func() { served <- stdio.Serve() }()
+
+	callWait(t, in, 1)
+	<-started
+
+	// Closing stdin shuts the server down, cancelling the request once the
+	// timeout expires
+	in.Close()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("Serve: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after the shutdown timeout")
+	}
+	if stdio.ctx.Err() == nil {
+		t.Error("in-flight requests not cancelled")
+	}
+}
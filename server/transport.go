@@ -0,0 +1,22 @@
+package server
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrServerShuttingDown is reported to clients whose requests arrive after
+// Shutdown has been called
+var ErrServerShuttingDown = errors.New("server is shutting down")
+
+// Transport serves an MCPServer over a particular kind of connection
+type Transport interface {
+	// Serve handles incoming requests until the connection is closed or the
+	// transport is shut down.
+	Serve() error
+
+	// Shutdown gracefully stops the transport. New requests are refused,
+	// in-flight requests are given until ctx expires to finish and are cancelled
+	// afterwards, pending writes are flushed and session resources are released.
+	Shutdown(ctx context.Context) error
+}
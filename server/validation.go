@@ -0,0 +1,215 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/WePrompt/gomcp/jsonschema"
+	"github.com/WePrompt/gomcp/mcp"
+)
+
+// WithToolArgumentValidation makes the server check tools/call arguments against
+// the tool's inputSchema before calling the tool handler. Invalid arguments are
+// reported as a tool result with isError set that lists every offending field,
+// so that the model can correct the call.
+//
+// Tools are looked up by name in a cache of the tool handler's list, dropped
+// whenever the server sends notifications/tools/list_changed and whenever the
+// tools of a handlers.ToolChangeNotifier change. Tools missing from the cache
+// are looked up again, but other handlers replacing a tool must send the
+// notification for its new schema to apply.
+func WithToolArgumentValidation() ServerOption {
+	return func(s *MCPServer) {
+		s.validateToolArguments = true
+	}
+}
+
+// WithToolOutputValidation makes the server check the structured content returned
+// by tools that declare an outputSchema against that schema. A result that does
+// not conform is a bug in the tool, so it is reported as an internal error
+// rather than passed on to the client. Tools are looked up as with
+// WithToolArgumentValidation.
+func WithToolOutputValidation() ServerOption {
+	return func(s *MCPServer) {
+		s.validateToolOutput = true
+	}
+}
+
+// schemaCache holds compiled schemas keyed by their JSON encoding. Schemas that
+// fail to compile are kept along with their error.
+type schemaCache struct {
+	mu      sync.Mutex
+	schemas map[string]compiledSchema
+}
+
+type compiledSchema struct {
+	schema *jsonschema.Schema
+	err    error
+}
+
+func newSchemaCache() *schemaCache {
+	return &schemaCache{schemas: make(map[string]compiledSchema)}
+}
+
+func (c *schemaCache) compile(schema interface{}) (*jsonschema.Schema, error) {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	compiled, ok := c.schemas[string(b)]
+	if !ok {
+		compiled.schema, compiled.err = jsonschema.Compile(b)
+		c.schemas[string(b)] = compiled
+	}
+	return compiled.schema, compiled.err
+}
+
+// toolCache holds the tools of the tool handler by name. They are listed when
+// first needed and dropped whenever the tools change, as reported by a
+// handlers.ToolChangeNotifier or notifications/tools/list_changed.
+type toolCache struct {
+	mu    sync.Mutex
+	tools map[string]mcp.Tool
+
+	// generation counts the invalidations, so that a list fetched while the
+	// tools changed is not kept
+	generation uint64
+}
+
+// notified drops the cached tools when the tool list changed
+func (c *toolCache) notified(method string) {
+	if method == mcp.MethodNotificationToolsListChanged {
+		c.invalidate()
+	}
+}
+
+// invalidate drops the cached tools
+func (c *toolCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tools = nil
+	c.generation++
+}
+
+// findTool returns the named tool, or nil if the tool handler doesn't list it.
+// Tools missing from the cache are looked for in a fresh list, in case they
+// were added since.
+func (s *MCPServer) findTool(ctx context.Context, name string) (*mcp.Tool, error) {
+	s.tools.mu.Lock()
+	tools, generation := s.tools.tools, s.tools.generation
+	s.tools.mu.Unlock()
+
+	if tool, ok := tools[name]; ok {
+		return &tool, nil
+	}
+
+	tools, err := s.listTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.tools.mu.Lock()
+	if s.tools.generation == generation {
+		s.tools.tools = tools
+	}
+	s.tools.mu.Unlock()
+
+	tool, ok := tools[name]
+	if !ok {
+		return nil, nil
+	}
+	return &tool, nil
+}
+
+// listTools pages through the tool handler's list
+func (s *MCPServer) listTools(ctx context.Context) (map[string]mcp.Tool, error) {
+	tools := make(map[string]mcp.Tool)
+	var cursor *string
+	for {
+		result, err := s.toolHandler.List(ctx, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		for _, tool := range result.Tools {
+			tools[tool.Name] = tool
+		}
+		if result.NextCursor == nil || *result.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = result.NextCursor
+	}
+}
+
+// checkToolArguments validates arguments against the input schema of the named
+// tool. It returns an error result if they are invalid, and nil if they are
+// valid or the tool is unknown, leaving it to the handler to reject the call.
+func (s *MCPServer) checkToolArguments(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	tool, err := s.findTool(ctx, name)
+	if err != nil || tool == nil {
+		return nil, err
+	}
+
+	schema, err := s.schemas.compile(tool.InputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input schema for tool %s: %w", name, err)
+	}
+
+	var instance interface{} = arguments
+	if arguments == nil {
+		instance = map[string]interface{}{}
+	}
+	err = schema.Validate(instance)
+	var validationErrs jsonschema.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Invalid arguments for tool %s:", name)
+	for _, e := range validationErrs {
+		path := strings.TrimPrefix(e.Path, "/")
+		if path == "" {
+			path = "arguments"
+		}
+		fmt.Fprintf(&sb, "\n- %s: %s", path, e.Message)
+	}
+	result := &mcp.CallToolResult{IsError: true}
+	result.AddTextContent(mcp.NewTextContent(sb.String()))
+	return result, nil
+}
+
+// checkToolOutput validates the structured content of a successful result
+// against the output schema of the named tool, if it declares one
+func (s *MCPServer) checkToolOutput(ctx context.Context, name string, result *mcp.CallToolResult) error {
+	if result.IsError {
+		return nil
+	}
+	tool, err := s.findTool(ctx, name)
+	if err != nil || tool == nil || tool.OutputSchema == nil {
+		return err
+	}
+	if result.StructuredContent == nil {
+		return fmt.Errorf("tool %s declares an output schema but returned no structured content", name)
+	}
+
+	schema, err := s.schemas.compile(tool.OutputSchema)
+	if err != nil {
+		return fmt.Errorf("invalid output schema for tool %s: %w", name, err)
+	}
+	// Round-trip the structured content so that it holds the types the client
+	// decodes, rather than the Go values the tool built it from
+	content, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured content of tool %s: %w", name, err)
+	}
+	if err := schema.ValidateJSON(content); err != nil {
+		return fmt.Errorf("structured content of tool %s does not match its output schema: %w", name, err)
+	}
+	return nil
+}
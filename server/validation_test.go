@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/WePrompt/gomcp/mcp"
+	"github.com/WePrompt/gomcp/server/handlers"
+)
+
+var countSchema = &mcp.ToolOutputSchema{
+	Type: "object",
+	Properties: mcp.ToolInputSchemaProperties{
+		"count": map[string]interface{}{"type": "integer"},
+		"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		"meta":  map[string]interface{}{"type": "object"},
+	},
+	Required: []string{"count"},
+}
+
+// newOutputServer returns a server validating the output of a tool returning
+// the given structured content
+func newOutputServer(structured map[string]interface{}) *MCPServer {
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{Name: "count", OutputSchema: countSchema}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{}, StructuredContent: structured}, nil
+	})
+	return NewMCPServer(WithToolHandler(tools), WithToolOutputValidation())
+}
+
+func TestToolOutputValidationGoValues(t *testing.T) {
+	// Structured content built in Go holds ints, typed slices and nested
+	// struct-like maps rather than the types encoding/json decodes
+	s := newOutputServer(map[string]interface{}{
+		"count": 3,
+		"tags":  []string{"a", "b"},
+		"meta":  map[string]string{"source": "test"},
+	})
+	result, err := callTool(t, s, "count", nil)
+	if err != nil {
+		t.Fatalf("valid structured content rejected: %v", err)
+	}
+	if result.StructuredContent["count"] != float64(3) {
+		t.Errorf("structuredContent = %v", result.StructuredContent)
+	}
+}
+
+func TestToolOutputValidationInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		structured map[string]interface{}
+		err        string
+	}{
+		{"wrong type", map[string]interface{}{"count": 1.5}, "/count: expected integer, got number"},
+		{"wrong item type", map[string]interface{}{"count": 1, "tags": []int{1}}, "/tags/0: expected string, got integer"},
+		{"missing property", map[string]interface{}{"tags": []string{}}, "/count: is required"},
+		{"no structured content", nil, "returned no structured content"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := callTool(t, newOutputServer(tt.structured), "count", nil)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestToolOutputValidationSkipsErrorResults(t *testing.T) {
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{Name: "fail", OutputSchema: countSchema}, func(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		result := &mcp.CallToolResult{IsError: true}
+		result.AddTextContent(mcp.NewTextContent("failed"))
+		return result, nil
+	})
+	s := NewMCPServer(WithToolHandler(tools), WithToolOutputValidation())
+	result, err := callTool(t, s, "fail", nil)
+	if err != nil {
+		t.Fatalf("error result rejected: %v", err)
+	}
+	if !result.IsError {
+		t.Errorf("isError was lost: %+v", result)
+	}
+}
+
+// countingTools counts the calls to List of the tool handler it wraps
+type countingTools struct {
+	*handlers.ToolRegistry
+	lists int
+}
+
+func (c *countingTools) List(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error) {
+	c.lists++
+	return c.ToolRegistry.List(ctx, cursor)
+}
+
+func echoTool(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	result := &mcp.CallToolResult{}
+	result.AddTextContent(mcp.NewTextContent("ok"))
+	return result, nil
+}
+
+func inputSchema(required ...string) mcp.ToolInputSchema {
+	return mcp.ToolInputSchema{
+		Type: "object",
+		Properties: mcp.ToolInputSchemaProperties{
+			"text":  map[string]interface{}{"type": "string"},
+			"count": map[string]interface{}{"type": "integer", "minimum": 1},
+			"name":  map[string]interface{}{"type": "string", "pattern": "^(?!admin)"},
+		},
+		Required: required,
+	}
+}
+
+func TestToolArgumentValidation(t *testing.T) {
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{Name: "echo", InputSchema: inputSchema("text")}, echoTool)
+	s := NewMCPServer(WithToolHandler(tools), WithToolArgumentValidation())
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		invalid   []string
+	}{
+		{"valid", map[string]interface{}{"text": "hi", "count": 2}, nil},
+		{"unsupported pattern ignored", map[string]interface{}{"text": "hi", "name": "admin"}, nil},
+		{"missing required", nil, []string{"- text: is required"}},
+		{"several errors", map[string]interface{}{"text": 1, "count": 0}, []string{"- text: expected string, got integer", "- count: must be >= 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := callTool(t, s, "echo", tt.arguments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			text := result.Content[0].(mcp.TextContent).Text
+			if result.IsError != (tt.invalid != nil) {
+				t.Fatalf("isError = %v: %s", result.IsError, text)
+			}
+			for _, want := range tt.invalid {
+				if !strings.Contains(text, want) {
+					t.Errorf("result %q does not contain %q", text, want)
+				}
+			}
+		})
+	}
+
+	// Unknown tools are left to the handler to reject
+	if _, err := callTool(t, s, "missing", nil); err == nil || !strings.Contains(err.Error(), "unknown tool") {
+		t.Errorf("unknown tool: %v", err)
+	}
+}
+
+func TestToolArgumentValidationDisabled(t *testing.T) {
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{Name: "echo", InputSchema: inputSchema("text")}, echoTool)
+	s := NewMCPServer(WithToolHandler(tools))
+	result, err := callTool(t, s, "echo", map[string]interface{}{"text": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError {
+		t.Errorf("arguments validated without WithToolArgumentValidation: %+v", result)
+	}
+}
+
+func TestToolCacheListChanged(t *testing.T) {
+	tools := &countingTools{ToolRegistry: handlers.NewToolRegistryWithPaginator(handlers.NewPaginator(1))}
+	tools.Register(mcp.Tool{Name: "a", InputSchema: inputSchema()}, echoTool)
+	tools.Register(mcp.Tool{Name: "echo", InputSchema: inputSchema()}, echoTool)
+	s := NewMCPServer(WithToolHandler(tools), WithToolArgumentValidation(), WithToolCapabilities(true))
+
+	session := NewSession("test")
+	session.SetSender(func(message json.RawMessage) error { return nil })
+	ctx := ContextWithSession(context.Background(), session)
+	initialize := json.RawMessage(`{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}`)
+	if _, err := s.Request(ctx, mcp.MethodInitialize, initialize); err != nil {
+		t.Fatal(err)
+	}
+
+	call := func() *mcp.CallToolResult {
+		t.Helper()
+		b, err := s.Request(ctx, mcp.MethodToolsCall, json.RawMessage(`{"name":"echo","arguments":{}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result mcp.CallToolResult
+		if err := json.Unmarshal(b, &result); err != nil {
+			t.Fatal(err)
+		}
+		return &result
+	}
+
+	for i := 0; i < 3; i++ {
+		if result := call(); result.IsError {
+			t.Fatalf("call %d rejected", i)
+		}
+	}
+	if tools.lists != 2 {
+		t.Errorf("tools listed %d times for 3 calls, want the 2 pages listed once", tools.lists)
+	}
+
+	// The new schema applies once the server notifies the list changed
+	tools.Register(mcp.Tool{Name: "echo", InputSchema: inputSchema("text")}, echoTool)
+	if err := session.SendNotification(mcp.MethodNotificationToolsListChanged, nil); err != nil {
+		t.Fatal(err)
+	}
+	if result := call(); !result.IsError {
+		t.Error("call validated against the schema listed before tools/list_changed")
+	}
+	if tools.lists != 4 {
+		t.Errorf("tools listed %d times, want 4 after tools/list_changed", tools.lists)
+	}
+}
+
+func TestToolCacheRegistryChanges(t *testing.T) {
+	tools := handlers.NewToolRegistry()
+	tools.Register(mcp.Tool{Name: "echo", InputSchema: inputSchema()}, echoTool)
+	s := NewMCPServer(WithToolHandler(tools), WithToolArgumentValidation())
+
+	// No session is told about the changes, yet the cache follows the registry
+	if result, err := callTool(t, s, "echo", nil); err != nil || result.IsError {
+		t.Fatalf("first call = %+v, %v", result, err)
+	}
+	tools.Register(mcp.Tool{Name: "later", InputSchema: inputSchema("text")}, echoTool)
+	if result, err := callTool(t, s, "later", nil); err != nil || !result.IsError {
+		t.Errorf("tool registered after the first call not validated: %+v, %v", result, err)
+	}
+	tools.Register(mcp.Tool{Name: "echo", InputSchema: inputSchema("text")}, echoTool)
+	if result, err := callTool(t, s, "echo", nil); err != nil || !result.IsError {
+		t.Errorf("replaced tool validated against its previous schema: %+v, %v", result, err)
+	}
+	tools.Unregister("later")
+	if _, err := callTool(t, s, "later", map[string]interface{}{"text": "hi"}); err == nil || !strings.Contains(err.Error(), "unknown tool") {
+		t.Errorf("unregistered tool: %v", err)
+	}
+}